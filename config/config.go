@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"reflect"
 	"runtime/debug"
 	"strings"
 	"syscall"
@@ -30,6 +31,9 @@ type config struct {
 		IdleTimeout          time.Duration `mapstructure:"idleTimeout"`
 		DisableKeepalive     bool          `mapstructure:"disableKeepalive"`
 		RequestTimeout       time.Duration `mapstructure:"requestTimeout"`
+		BatchRequestTimeout  time.Duration `mapstructure:"batchRequestTimeout"`
+		BatchConcurrency     int           `mapstructure:"batchConcurrency"`
+		MatrixConcurrency    int           `mapstructure:"matrixConcurrency"`
 		LivezTimeout         time.Duration `mapstructure:"livezTimeout"`
 		ReadyzTimeout        time.Duration `mapstructure:"readyzTimeout"`
 		RememberBusyTimeout  time.Duration `mapstructure:"rememberBusyTimeout"`
@@ -37,7 +41,9 @@ type config struct {
 	}
 	Linter struct {
 		MaxQueueSize   int           `mapstructure:"maxQueueSize"`
+		MaxQueueWait   time.Duration `mapstructure:"maxQueueWait"`
 		BackendTimeout time.Duration `mapstructure:"backendTimeout"`
+		PipelineDepth  int           `mapstructure:"pipelineDepth"`
 		Badkeys        struct {
 			NumProcesses int    `mapstructure:"numProcesses"`
 			PythonDir    string `mapstructure:"pythonDir"`
@@ -77,10 +83,44 @@ type config struct {
 		Zlint struct {
 			NumGoroutines int `mapstructure:"numGoroutines"`
 		}
+		Scaling struct {
+			MinInstances map[string]int `mapstructure:"minInstances"` // Per linter name.  Only external backends are scaled.
+			MaxInstances map[string]int `mapstructure:"maxInstances"` // Per linter name.
+			ScaleUpWait  time.Duration  `mapstructure:"scaleUpWait"`  // Add an instance when the estimated queue wait exceeds this.
+			CoolDown     time.Duration  `mapstructure:"coolDown"`     // Retire an added instance once it has been idle for this long.
+			Interval     time.Duration  `mapstructure:"interval"`     // How often to reconsider the number of instances (0 = never).
+		}
 	}
 	Response struct {
-		DefaultFormat   string `mapstructure:"defaultFormat"`
-		JsonPrettyPrint bool   `mapstructure:"jsonPrettyPrint"`
+		DefaultFormat        string `mapstructure:"defaultFormat"`
+		JsonPrettyPrint      bool   `mapstructure:"jsonPrettyPrint"`
+		JunitFailureSeverity string `mapstructure:"junitFailureSeverity"`
+	}
+	Profiles struct {
+		Custom []CustomProfile `mapstructure:"custom"`
+	}
+	Severity struct {
+		Mappings []SeverityMapping `mapstructure:"mappings"`
+	}
+	Decision struct {
+		Threshold        string            `mapstructure:"threshold"`
+		LinterThresholds map[string]string `mapstructure:"linterThresholds"`
+		RequiredLinters  []string          `mapstructure:"requiredLinters"`
+	}
+	Waivers struct {
+		File string `mapstructure:"file"`
+	}
+	Priority struct {
+		InteractiveWeight int              `mapstructure:"interactiveWeight"` // Interactive requests served per bulk request, whilst both are queued.
+		APIKeys           []PriorityAPIKey `mapstructure:"apiKeys"`
+	}
+	IssuerRegistry struct {
+		File string `mapstructure:"file"`
+	}
+	Data struct {
+		Directory      string        `mapstructure:"directory"`      // Load data snapshots (e.g., ccadb-20260820.193505.csv) from this directory.
+		ReloadInterval time.Duration `mapstructure:"reloadInterval"` // How often to check for newer data snapshots (0 = only at startup).
+		PublicKeyFile  string        `mapstructure:"publicKeyFile"`  // If set, data snapshots must be signed with the corresponding private key.
 	}
 	Logging struct {
 		IsDevelopment      bool   `mapstructure:"isDevelopment"`
@@ -90,18 +130,91 @@ type config struct {
 	}
 }
 
+// CustomProfile is an operator-defined profile, which is linted as its base profile.
+type CustomProfile struct {
+	Name            string   `mapstructure:"name"`
+	Description     string   `mapstructure:"description"`
+	BaseProfile     string   `mapstructure:"baseProfile"`     // Name of the built-in profile that determines backend behaviour.
+	EnabledLinters  []string `mapstructure:"enabledLinters"`  // If specified, only these linters are used.
+	DisabledLinters []string `mapstructure:"disabledLinters"` // These linters are not used.
+	Include         []string `mapstructure:"include"`         // Check codes/globs to include, unless the request specifies its own.
+	Exclude         []string `mapstructure:"exclude"`         // Check codes/globs to exclude, in addition to those that the request specifies.
+	Autodetect      struct {
+		// Certificates that match all of the specified criteria are autodetected as this profile.
+		IssuerSKIs []string `mapstructure:"issuerSKIs"` // Hex-encoded.  Matched against the Authority Key Identifier.
+		PolicyOIDs []string `mapstructure:"policyOIDs"` // Matched against the Certificate Policies.
+	} `mapstructure:"autodetect"`
+}
+
+// SeverityMapping overrides the severity level of the findings that it matches.
+type SeverityMapping struct {
+	// Match criteria.  Each criterion that is specified must match.
+	Linter  string `mapstructure:"linter"`
+	Code    string `mapstructure:"code"`    // Check code or glob pattern.
+	Finding string `mapstructure:"finding"` // Regular expression.
+	Profile string `mapstructure:"profile"` // Profile name or glob pattern (e.g., "tbr_*").
+	// The severity level to report instead.
+	Severity string `mapstructure:"severity"`
+}
+
+// Waiver is an approved exception for the findings that it matches, as loaded from the waiver file.
+type Waiver struct {
+	// Match criteria.  Each criterion that is specified must match.
+	Linter        string `mapstructure:"linter"`
+	Code          string `mapstructure:"code"`
+	Finding       string `mapstructure:"finding"`       // Regular expression.
+	Profile       string `mapstructure:"profile"`       // Profile name.
+	IssuerSKI     string `mapstructure:"issuerSKI"`     // Hex-encoded.
+	IssuerSubject string `mapstructure:"issuerSubject"` // Distinguished Name string.
+	// Approval details.
+	Justification string `mapstructure:"justification"`
+	Ticket        string `mapstructure:"ticket"`
+	Expires       string `mapstructure:"expires"`  // YYYY-MM-DD.  The waiver applies until the end of this day (UTC).
+	Severity      string `mapstructure:"severity"` // If specified, matching findings are downgraded to this severity level.
+}
+
+// PriorityAPIKey assigns a priority class to the requests that present an API key.
+type PriorityAPIKey struct {
+	Key      string `mapstructure:"key"`
+	Priority string `mapstructure:"priority"` // "interactive" or "bulk".
+}
+
+// RegisteredIssuer is a CA that is not (yet) disclosed in the CCADB, as loaded from the issuer registry file.
+type RegisteredIssuer struct {
+	// Identification.  At least one of these must be specified.
+	Certificate string `mapstructure:"certificate"` // PEM.
+	SKI         string `mapstructure:"ski"`         // Hex-encoded.  Required if the certificate has no Subject Key Identifier.
+	// Capabilities, as for the CCADB data.
+	Root               bool `mapstructure:"root"` // Implied if the certificate is self-issued.
+	TlsCapable         bool `mapstructure:"tlsCapable"`
+	TlsEvCapable       bool `mapstructure:"tlsEvCapable"`
+	SmimeCapable       bool `mapstructure:"smimeCapable"`
+	CodeSigningCapable bool `mapstructure:"codeSigningCapable"`
+	HasVMCAudit        bool `mapstructure:"hasVMCAudit"`
+	// The profile to autodetect for this CA's certificate and the documents that it issues, when its capabilities
+	// don't determine a more specific profile.  Only used for documents to which the profile applies.
+	DefaultProfile string `mapstructure:"defaultProfile"`
+}
+
 type ResponseFormat int
 
 const (
 	RESPONSEFORMAT_HTML ResponseFormat = iota
 	RESPONSEFORMAT_JSON
 	RESPONSEFORMAT_TEXT
+	RESPONSEFORMAT_SARIF
+	RESPONSEFORMAT_JUNIT
+	RESPONSEFORMAT_JSON2
+	RESPONSEFORMAT_NDJSON
+	RESPONSEFORMAT_SSE
 )
 
 var (
 	ApplicationName       string
 	ApplicationNamespace  string
 	Config                config
+	Waivers               []Waiver
+	RegisteredIssuers     []RegisteredIssuer
 	DefaultResponseFormat = RESPONSEFORMAT_JSON
 
 	// Automatically populated by the build system (see Makefile / Dockerfile).
@@ -131,6 +244,24 @@ func init() {
 		panic(fmt.Sprintf("Invalid default response format: %s", Config.Response.DefaultFormat))
 	}
 
+	// Load the waiver file, if configured.
+	if Config.Waivers.File != "" {
+		var err error
+		if Waivers, err = LoadWaivers(Config.Waivers.File); err != nil {
+			panic(fmt.Sprintf("Invalid waiver file: %v", err))
+		}
+		logger.Logger.Info("Loaded waivers", zap.String("file", Config.Waivers.File), zap.Int("num_waivers", len(Waivers)))
+	}
+
+	// Load the issuer registry file, if configured.
+	if Config.IssuerRegistry.File != "" {
+		var err error
+		if RegisteredIssuers, err = LoadIssuerRegistry(Config.IssuerRegistry.File); err != nil {
+			panic(fmt.Sprintf("Invalid issuer registry file: %v", err))
+		}
+		logger.Logger.Info("Loaded issuer registry", zap.String("file", Config.IssuerRegistry.File), zap.Int("num_issuers", len(RegisteredIssuers)))
+	}
+
 	// Log build information.
 	if bi, ok := debug.ReadBuildInfo(); ok {
 		for _, bs := range bi.Settings {
@@ -188,12 +319,17 @@ func initViper() error {
 	viper.SetDefault("server.idleTimeout", 30*time.Second)
 	viper.SetDefault("server.disableKeepalive", false)
 	viper.SetDefault("server.requestTimeout", 30*time.Second)
+	viper.SetDefault("server.batchRequestTimeout", 5*time.Minute)
+	viper.SetDefault("server.batchConcurrency", 16)
+	viper.SetDefault("server.matrixConcurrency", 4)
 	viper.SetDefault("server.livezTimeout", 500*time.Millisecond)
 	viper.SetDefault("server.readyzTimeout", 500*time.Millisecond)
 	viper.SetDefault("server.rememberBusyTimeout", 5*time.Second)
 	viper.SetDefault("server.metricsTimeout", 8*time.Second)
 	viper.SetDefault("linter.maxQueueSize", 8192)
+	viper.SetDefault("linter.maxQueueWait", 0)
 	viper.SetDefault("linter.backendTimeout", 30*time.Second)
+	viper.SetDefault("linter.pipelineDepth", 4)
 	viper.SetDefault("linter.badkeys.numProcesses", 1)
 	viper.SetDefault("linter.badkeys.pythonDir", "autodetect")
 	viper.SetDefault("linter.certlint.numProcesses", 1)
@@ -213,8 +349,23 @@ func initViper() error {
 	viper.SetDefault("linter.rocacheck.numGoroutines", 1)
 	viper.SetDefault("linter.x509lint.numGoroutines", 1)
 	viper.SetDefault("linter.zlint.numGoroutines", 1)
+	viper.SetDefault("linter.scaling.minInstances", map[string]int{})
+	viper.SetDefault("linter.scaling.maxInstances", map[string]int{})
+	viper.SetDefault("linter.scaling.scaleUpWait", 2*time.Second)
+	viper.SetDefault("linter.scaling.coolDown", 5*time.Minute)
+	viper.SetDefault("linter.scaling.interval", time.Second)
 	viper.SetDefault("response.defaultFormat", "json")
 	viper.SetDefault("response.jsonPrettyPrint", false)
+	viper.SetDefault("response.junitFailureSeverity", "error")
+	viper.SetDefault("decision.threshold", "error")
+	viper.SetDefault("decision.linterThresholds", map[string]string{})
+	viper.SetDefault("decision.requiredLinters", []string{})
+	viper.SetDefault("waivers.file", "")
+	viper.SetDefault("priority.interactiveWeight", 4)
+	viper.SetDefault("issuerRegistry.file", "")
+	viper.SetDefault("data.directory", "")
+	viper.SetDefault("data.reloadInterval", time.Hour)
+	viper.SetDefault("data.publicKeyFile", "")
 	viper.SetDefault("logging.isDevelopment", false)
 	viper.SetDefault("logging.level", "")
 	viper.SetDefault("logging.samplingInitial", math.MaxInt)    // When both of these are set to MaxInt, sampling is disabled.
@@ -225,6 +376,45 @@ func initViper() error {
 	return viper.Unmarshal(&Config)
 }
 
+// LoadWaivers reads the "waivers" list from a YAML or JSON waiver file.
+func LoadWaivers(file string) ([]Waiver, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	var waiverFile struct {
+		Waivers []Waiver `mapstructure:"waivers"`
+	}
+	// YAML parses unquoted dates as timestamps, so convert them back to YYYY-MM-DD strings.
+	dateHook := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if t, ok := data.(time.Time); ok && to.Kind() == reflect.String {
+			return t.Format(time.DateOnly), nil
+		}
+		return data, nil
+	}
+	if err := v.Unmarshal(&waiverFile, viper.DecodeHook(dateHook)); err != nil {
+		return nil, err
+	}
+	return waiverFile.Waivers, nil
+}
+
+// LoadIssuerRegistry reads the "issuers" list from a YAML or JSON issuer registry file.
+func LoadIssuerRegistry(file string) ([]RegisteredIssuer, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	var registryFile struct {
+		Issuers []RegisteredIssuer `mapstructure:"issuers"`
+	}
+	if err := v.Unmarshal(&registryFile); err != nil {
+		return nil, err
+	}
+	return registryFile.Issuers, nil
+}
+
 func ParseResponseFormat(format string) ResponseFormat {
 	switch strings.ToLower(format) {
 	case "html":
@@ -233,6 +423,16 @@ func ParseResponseFormat(format string) ResponseFormat {
 		return RESPONSEFORMAT_JSON
 	case "text":
 		return RESPONSEFORMAT_TEXT
+	case "sarif":
+		return RESPONSEFORMAT_SARIF
+	case "junit":
+		return RESPONSEFORMAT_JUNIT
+	case "json2":
+		return RESPONSEFORMAT_JSON2
+	case "ndjson":
+		return RESPONSEFORMAT_NDJSON
+	case "sse":
+		return RESPONSEFORMAT_SSE
 	default:
 		return -1
 	}
@@ -0,0 +1,81 @@
+package linter
+
+import (
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+)
+
+// RUNTIME_EWMA_WEIGHT is the inverse of the weight given to each new processing time in the moving average.
+const RUNTIME_EWMA_WEIGHT = 8
+
+// recordRuntime updates the moving average of the linter's processing time, which (unlike the processing_time
+// summary) reflects the linter's recent performance rather than its performance since startup.
+func (l *Linter) recordRuntime(runtime time.Duration) {
+	for {
+		old := l.recentRuntime.Load()
+		average := int64(runtime)
+		if old != 0 {
+			average = old + (int64(runtime)-old)/RUNTIME_EWMA_WEIGHT
+		}
+		if l.recentRuntime.CompareAndSwap(old, average) {
+			return
+		}
+	}
+}
+
+// QueueDepth returns the number of linting requests of the specified priority class that are waiting to be processed.
+func (l *Linter) QueueDepth(priority PriorityClass) int {
+	return len(l.Queue(priority))
+}
+
+// EstimatedWait estimates how long a new linting request of the specified priority class would be queued for, from the
+// number of requests ahead of it, the number of running instances, and the recent processing time.  Interactive requests only
+// wait behind other interactive requests, whereas bulk requests wait behind every queued request.
+func (l *Linter) EstimatedWait(priority PriorityClass) time.Duration {
+	if l.NumInstances <= 0 {
+		return 0
+	}
+	ahead := l.QueueDepth(priority)
+	if priority == PRIORITY_BULK && l.BulkReqChannel != nil {
+		ahead += l.QueueDepth(PRIORITY_INTERACTIVE)
+	}
+	return l.estimatedRuntime(ahead)
+}
+
+// estimatedRuntime estimates how long the running instances will take to process the specified number of requests.
+func (l *Linter) estimatedRuntime(requests int) time.Duration {
+	instances := l.runningInstances.Load()
+	if instances <= 0 {
+		instances = int64(l.NumInstances) // None have started yet.
+		if l.minInstances > 0 {
+			instances = int64(l.minInstances)
+		}
+	}
+	return time.Duration(int64(requests) * l.recentRuntime.Load() / instances)
+}
+
+// Admit determines whether or not the linter should accept the specified number of new linting requests (e.g., one
+// per profile in compliance matrix mode) of the specified priority class.  The requests are rejected if the queue is
+// full, or if the estimated wait for the last of them exceeds the configured maximum (by default, the request timeout,
+// because the client would give up first).  For rejected requests, the returned duration is how long the client
+// should wait before retrying.
+func (l *Linter) Admit(priority PriorityClass, requests int) (time.Duration, bool) {
+	maxWait := config.Config.Linter.MaxQueueWait
+	if maxWait <= 0 {
+		maxWait = time.Duration(config.Config.Server.RequestTimeout)
+	}
+	wait := l.EstimatedWait(priority)
+	if l.NumInstances > 0 {
+		wait += l.estimatedRuntime(requests - 1) // The last request also waits behind the others.
+	}
+	queue := l.Queue(priority)
+	if full := cap(queue) > 0 && len(queue) >= cap(queue); !full && wait <= maxWait {
+		return 0, true
+	}
+
+	if l.rejectedCounter != nil {
+		l.rejectedCounter.Inc()
+	}
+	return max(wait-maxWait, time.Second), false
+}
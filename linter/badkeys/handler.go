@@ -2,6 +2,7 @@ package badkeys
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkimetal/pkimetal/config"
 	"github.com/pkimetal/pkimetal/linter"
@@ -28,54 +29,70 @@ func init() {
 		Name:         "badkeys",
 		Version:      Version,
 		Url:          "https://github.com/badkeys/badkeys",
-		Unsupported:  linter.NonCertificateProfileIDs,
+		Unsupported:  linter.NonPublicKeyProfileIDs,
 		NumInstances: config.Config.Linter.Badkeys.NumProcesses,
 		Interface:    func() linter.LinterInterface { return &Badkeys{} },
+		Pipelined:    true,
+		Protocol:     linter.PROTOCOL_JSONLINES,
 	}).Register()
 }
 
 func (l *Badkeys) StartInstance() (useHandleRequest bool, directory, cmd string, args []string) {
 	// Start badkeys server and configure STDIN/STDOUT pipes.
-	// The printresults function is adapted from the _printresults function defined by https://github.com/badkeys/badkeys/blob/main/badkeys/runcli.py.
+	// The findings function is adapted from the _printresults function defined by https://github.com/badkeys/badkeys/blob/main/badkeys/runcli.py.
 	return false, config.Config.Linter.Badkeys.PythonDir, "python3",
 		[]string{"-c", `#!/usr/bin/python3
+import json
 from sys import stdin
 from badkeys.allkeys import urllookup
-from badkeys.checks import allchecks, checkcrt
+from badkeys.checks import allchecks, checkcrt, checkcsr, checkpubkey
 
-def printresults(key):
+def findings(key):
 	if key["type"] == "unsupported":
-		print(f"W: Unsupported key type")
+		return [{"severity": "warning", "message": "Unsupported key type"}]
 	elif key["type"] == "unparseable":
-		print(f"F: Unparseable input")
+		return [{"severity": "fatal", "message": "Unparseable input"}]
 	elif key["type"] == "notfound":
-		print(f"W: No key found")
-	else:
-		if key["results"] == {}:
-			print(f"I: Key ok")
+		return [{"severity": "warning", "message": "No key found"}]
+	elif key["results"] == {}:
+		return [{"severity": "info", "message": "Key ok"}]
+	results = []
 	for check, result in key["results"].items():
 		sub = ""
 		if "subtest" in result:
 			sub = f"/{result['subtest']}"
 		if sub.startswith(tuple(["/unusual_keysize", "/exponent_"])):
-			print(f"I: {check}{sub}")
+			results.append({"severity": "info", "message": f"{check}{sub}"})
 		else:
-			print(f"E: {check}{sub} vulnerability")
+			results.append({"severity": "error", "message": f"{check}{sub} vulnerability"})
+	return results
 
-profile_id = -1
-pem_data = ""
+pem_labels = {"certificate": "CERTIFICATE", "csr": "CERTIFICATE REQUEST", "publickey": "PUBLIC KEY"}
+
+def lint(request):
+	pem_label = pem_labels[request["type"]]
+	b64_data = request["der"]
+	pem_data = "-----BEGIN %s-----\n%s\n-----END %s-----\n" % (pem_label, "\n".join(b64_data[i:i + 64] for i in range(0, len(b64_data), 64)), pem_label)
+	if request["type"] == "csr":
+		return findings(checkcsr(pem_data, checks=allchecks))
+	elif request["type"] == "publickey":
+		return findings(checkpubkey(pem_data, checks=allchecks))
+	return findings(checkcrt(pem_data, checks=allchecks))
+
+protocol_version = ` + fmt.Sprint(linter.PROTOCOL_JSONLINES) + `
 try:
+	print(json.dumps({"v": protocol_version, "ready": True}), flush=True)
 	for line in stdin:
-		if profile_id == -1:
-			profile_id = int(line.strip())
-		else:
-			pem_data = pem_data + line.strip() + "\n"
-
-		if "END CERTIFICATE" in line:
-			printresults(checkcrt(pem_data, checks=allchecks))
-			print("` + linter.PKIMETAL_ENDOFRESULTS + `", flush=True)
-			profile_id = -1
-			pem_data = ""
+		request = json.loads(line)
+		response = {"v": protocol_version, "id": request["id"], "end": True}
+		try:
+			if request["v"] != protocol_version:
+				response["error"] = "Unsupported protocol version: " + str(request["v"])
+			else:
+				response["findings"] = lint(request)
+		except Exception as e:
+			response["error"] = "Exception: " + str(e)
+		print(json.dumps(response), flush=True)
 except KeyboardInterrupt:
 	pass
 `}
@@ -3,6 +3,7 @@ package certlint
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"os/exec"
 
 	"github.com/pkimetal/pkimetal/config"
@@ -36,6 +37,8 @@ func init() {
 		Unsupported:  linter.NonCertificateProfileIDs,
 		NumInstances: config.Config.Linter.Certlint.NumProcesses,
 		Interface:    func() linter.LinterInterface { return &Certlint{} },
+		Pipelined:    true,
+		Protocol:     linter.PROTOCOL_JSONLINES,
 	}).Register()
 }
 
@@ -72,34 +75,41 @@ func (l *Certlint) StartInstance() (useHandleRequest bool, directory, cmd string
 	return false, config.Config.Linter.Certlint.RubyDir, "ruby",
 		[]string{"-I", "lib:ext", "-e", `#!/usr/bin/ruby -Eutf-8:utf-8
 # encoding: UTF-8
+require 'json'
 require 'set'
 require 'certlint'
 
 $stdout.sync = true
 
-tbr_tevg_profile_ids = Set[` + linter.ProfileIDList(linter.TbrTevgCertificateProfileIDs) + `]
-profile_id = -1
-pem_cert = ""
+tbr_tevg_profile_ids = Set[` + linter.ProfileNameList(linter.TbrTevgCertificateProfileIDs) + `]
+protocol_version = ` + fmt.Sprint(linter.PROTOCOL_JSONLINES) + `
+severities = {"D" => "debug", "I" => "info", "N" => "notice", "W" => "warning", "E" => "error", "B" => "bug", "F" => "fatal"}
 
 begin
+	$stdout.puts(JSON.generate({"v" => protocol_version, "ready" => true}))
 	ARGF.each do |line|
-		if profile_id == -1
-			profile_id = Integer(line)
-		else
-			pem_cert << line
-		end
-		if line.include? "END CERTIFICATE"
-			m, der_cert = CertLint::PEMLint.lint(pem_cert, 'CERTIFICATE')
-			if tbr_tevg_profile_ids.include?(profile_id)
-				m << CertLint::CABLint.lint(der_cert)
+		request = JSON.parse(line)
+		response = {"v" => protocol_version, "id" => request["id"], "end" => true}
+		begin
+			if request["v"] != protocol_version
+				response["error"] = "Unsupported protocol version: #{request["v"]}"
 			else
-				m << CertLint.lint(der_cert)
+				pem_cert = "-----BEGIN CERTIFICATE-----\n" + request["der"].scan(/.{1,64}/).join("\n") + "\n-----END CERTIFICATE-----\n"
+				m, der_cert = CertLint::PEMLint.lint(pem_cert, 'CERTIFICATE')
+				if tbr_tevg_profile_ids.include?(request["profile"])
+					m << CertLint::CABLint.lint(der_cert)
+				else
+					m << CertLint.lint(der_cert)
+				end
+				response["findings"] = m.flatten.map do |s|
+					severity, message = s.split(": ", 2)
+					{"severity" => severities.fetch(severity), "message" => message}
+				end
 			end
-			m << "` + linter.PKIMETAL_ENDOFRESULTS + `"
-			$stdout.puts(m)
-			profile_id = -1
-			pem_cert = ""
+		rescue StandardError => e
+			response["error"] = "Exception: #{e.message}"
 		end
+		$stdout.puts(JSON.generate(response))
 	end
 rescue Interrupt
 end
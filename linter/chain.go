@@ -0,0 +1,148 @@
+package linter
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+// ChainFindings checks that the request's certificate is consistent with its
+// issuer certificate, that each certificate in the rest of the chain is
+// consistent with its own issuer, and that the certificate's names comply with
+// the name constraints of every CA certificate in the chain.
+func (lreq *LintingRequest) ChainFindings() []LintingResult {
+	if lreq.Cert == nil || lreq.Issuer == nil {
+		return nil
+	}
+
+	chain := append([]*x509.Certificate{lreq.Cert, lreq.Issuer}, lreq.Chain...)
+	var lres []LintingResult
+	for i := 0; i+1 < len(chain); i++ {
+		lres = append(lres, checkIssuedBy(chain[i], chain[i+1], chainFieldName(i))...)
+	}
+	for i := 1; i < len(chain); i++ {
+		lres = append(lres, checkNameConstraints(lreq.Cert, chain[i], chainFieldName(i))...)
+	}
+
+	return lreq.selectPkimetalResults(lres)
+}
+
+func chainFieldName(i int) string {
+	switch i {
+	case 0:
+		return "certificate"
+	case 1:
+		return "issuer"
+	default:
+		return fmt.Sprintf("chain[%d]", i-2)
+	}
+}
+
+func checkIssuedBy(cert, issuer *x509.Certificate, field string) []LintingResult {
+	var lres []LintingResult
+	if !bytes.Equal(cert.RawIssuer, issuer.RawSubject) {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_chain_issuer_dn_mismatch", Field: field, Finding: "Issuer DN does not byte-for-byte match the issuer certificate's Subject DN"})
+	}
+	if len(cert.AuthorityKeyId) > 0 && len(issuer.SubjectKeyId) > 0 && !bytes.Equal(cert.AuthorityKeyId, issuer.SubjectKeyId) {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_chain_aki_ski_mismatch", Field: field, Finding: "Authority Key Identifier does not match the issuer certificate's Subject Key Identifier"})
+	}
+	if !issuer.BasicConstraintsValid || !issuer.IsCA {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_chain_issuer_not_ca", Field: field, Finding: "Issuer certificate is not a CA certificate"})
+	}
+	if issuer.KeyUsage != 0 && issuer.KeyUsage&x509.KeyUsageCertSign == 0 {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_chain_issuer_no_keycertsign", Field: field, Finding: "Issuer certificate's Key Usage does not include keyCertSign"})
+	}
+	if err := issuer.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err == x509.ErrUnsupportedAlgorithm {
+		lres = append(lres, LintingResult{Severity: SEVERITY_NOTICE, Code: "n_chain_signature_unsupported", Field: field, Finding: "Signature could not be verified: " + err.Error()})
+	} else if err != nil {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_chain_signature_invalid", Field: field, Finding: "Signature does not verify with the issuer certificate's public key: " + err.Error()})
+	}
+	return lres
+}
+
+func checkNameConstraints(cert, ca *x509.Certificate, field string) []LintingResult {
+	var lres []LintingResult
+	for _, name := range cert.DNSNames {
+		if !isNamePermitted(name, ca.PermittedDNSNames, ca.ExcludedDNSNames, dnsNameMatchesConstraint) {
+			lres = append(lres, nameConstraintViolation(field, "DNS name", name))
+		}
+	}
+	for _, email := range cert.EmailAddresses {
+		if !isNamePermitted(email, ca.PermittedEmailAddresses, ca.ExcludedEmailAddresses, emailAddressMatchesConstraint) {
+			lres = append(lres, nameConstraintViolation(field, "Email address", email))
+		}
+	}
+	for _, ip := range cert.IPAddresses {
+		if !isIPAddressPermitted(ip, ca.PermittedIPAddresses, ca.ExcludedIPAddresses) {
+			lres = append(lres, nameConstraintViolation(field, "IP address", ip.String()))
+		}
+	}
+	return lres
+}
+
+func nameConstraintViolation(field, nameType, name string) LintingResult {
+	return LintingResult{Severity: SEVERITY_ERROR, Code: "e_chain_name_constraints_violated", Field: field, Finding: fmt.Sprintf("%s %q does not comply with the name constraints of the CA certificate", nameType, name)}
+}
+
+func isNamePermitted(name string, permitted, excluded []x509.GeneralSubtreeString, matches func(name, constraint string) bool) bool {
+	for _, e := range excluded {
+		if matches(name, e.Data) {
+			return false
+		}
+	}
+	if len(permitted) == 0 {
+		return true
+	}
+	for _, p := range permitted {
+		if matches(name, p.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIPAddressPermitted(ip net.IP, permitted, excluded []x509.GeneralSubtreeIP) bool {
+	for _, e := range excluded {
+		if e.Data.Contains(ip) {
+			return false
+		}
+	}
+	if len(permitted) == 0 {
+		return true
+	}
+	for _, p := range permitted {
+		if p.Data.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// dnsNameMatchesConstraint implements the dNSName matching rules of RFC 5280
+// section 4.2.1.10, also accepting the commonly used leading period form.
+func dnsNameMatchesConstraint(name, constraint string) bool {
+	name, constraint = strings.ToLower(strings.TrimSuffix(name, ".")), strings.ToLower(constraint)
+	if constraint == "" {
+		return true
+	} else if strings.HasPrefix(constraint, ".") {
+		return strings.HasSuffix(name, constraint)
+	}
+	return name == constraint || strings.HasSuffix(name, "."+constraint)
+}
+
+// emailAddressMatchesConstraint implements the rfc822Name matching rules of RFC
+// 5280 section 4.2.1.10.
+func emailAddressMatchesConstraint(email, constraint string) bool {
+	email, constraint = strings.ToLower(email), strings.ToLower(constraint)
+	if strings.Contains(constraint, "@") {
+		return email == constraint
+	}
+	_, host, _ := strings.Cut(email, "@")
+	if strings.HasPrefix(constraint, ".") {
+		return strings.HasSuffix(host, constraint)
+	}
+	return host == constraint
+}
@@ -0,0 +1,87 @@
+package linter
+
+import (
+	"path"
+	"strings"
+)
+
+// ParseCheckSelection splits a comma-separated list of check codes and/or glob
+// patterns (e.g. "w_ext_*"), and verifies that each pattern is well-formed.
+func ParseCheckSelection(s string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		} else if _, err := path.Match(p, ""); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+func matchesAnyCheck(patterns []string, code string) bool {
+	for _, p := range patterns {
+		if matched, _ := path.Match(p, code); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCheckSelection reports whether the request includes and/or excludes any checks.
+func (lreq *LintingRequest) HasCheckSelection() bool {
+	return len(lreq.ChecksAdded) > 0 || len(lreq.ChecksNarrowed) > 0 || len(lreq.ChecksDisabled) > 0
+}
+
+// IsCheckSelected reports whether the check with the specified code should be run
+// (or reported) for this request.  If any checks are included, only those are
+// selected, and narrowing the included checks can only deselect checks; excluded
+// checks are never selected.
+func (lreq *LintingRequest) IsCheckSelected(code string) bool {
+	if len(lreq.ChecksAdded) > 0 && !matchesAnyCheck(lreq.ChecksAdded, code) {
+		return false
+	} else if len(lreq.ChecksNarrowed) > 0 && !matchesAnyCheck(lreq.ChecksNarrowed, code) {
+		return false
+	}
+	return !matchesAnyCheck(lreq.ChecksDisabled, code)
+}
+
+// forwardedChecksAdded returns the included checks to forward to a backend that
+// applies the check selection itself.  A backend only accepts one list, so the
+// narrower list is forwarded; pkimetal still filters the backend's results against
+// both.
+func (lreq *LintingRequest) forwardedChecksAdded() []string {
+	if len(lreq.ChecksNarrowed) > 0 {
+		return lreq.ChecksNarrowed
+	}
+	return lreq.ChecksAdded
+}
+
+// isResultSelected post-filters a linting result according to the request's check
+// selection.  Results from linters that do not report codes are matched on their
+// finding text instead.  Code-less bug/fatal results report a failure of the
+// linter itself rather than the outcome of a check, so they are never filtered.
+func (lreq *LintingRequest) isResultSelected(lres LintingResult) bool {
+	if !lreq.HasCheckSelection() {
+		return true
+	} else if lres.Code != "" {
+		return lreq.IsCheckSelected(lres.Code)
+	} else if lres.Severity >= SEVERITY_BUG {
+		return true
+	}
+	return lreq.IsCheckSelected(lres.Finding)
+}
+
+// selectPkimetalResults attributes pkimetal's own findings to pkimetal, and
+// filters them according to the request's check selection.
+func (lreq *LintingRequest) selectPkimetalResults(lres []LintingResult) []LintingResult {
+	var selected []LintingResult
+	for _, lr := range lres {
+		lr.LinterName = PKIMETAL_NAME
+		if lreq.isResultSelected(lr) {
+			selected = append(selected, lr)
+		}
+	}
+	return selected
+}
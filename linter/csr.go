@@ -0,0 +1,51 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/zmap/zcrypto/encoding/asn1"
+	"github.com/zmap/zcrypto/x509"
+)
+
+var oidExtension_BasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+
+// CSRFindings checks the version, signature, public key, and requested
+// extensions of the request's CSR.
+func (lreq *LintingRequest) CSRFindings() []LintingResult {
+	if lreq.Csr == nil {
+		return nil
+	}
+
+	csr := lreq.Csr
+	var lres []LintingResult
+	if csr.Version != 0 {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_csr_invalid_version", Field: "version", Finding: fmt.Sprintf("Version is %d, but RFC2986 requires 0 (v1)", csr.Version)})
+	}
+	if err := csr.CheckSignature(); err == x509.ErrUnsupportedAlgorithm {
+		lres = append(lres, LintingResult{Severity: SEVERITY_NOTICE, Code: "n_csr_signature_unsupported", Field: "signature", Finding: "Signature could not be verified: " + err.Error()})
+	} else if err != nil {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_csr_signature_invalid", Field: "signature", Finding: "Signature does not verify with the CSR's public key: " + err.Error()})
+	}
+	lres = append(lres, publicKeyFindings(csr.PublicKey)...)
+
+	requested := make(map[string]bool)
+	for _, ext := range csr.Extensions {
+		if requested[ext.Id.String()] {
+			lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_csr_duplicate_extension", Field: "extensionRequest", Finding: fmt.Sprintf("Extension %s is requested more than once", ext.Id)})
+		}
+		requested[ext.Id.String()] = true
+
+		if ext.Id.Equal(oidExtension_BasicConstraints) {
+			var bc struct {
+				IsCA bool `asn1:"optional"`
+			}
+			if _, err := asn1.Unmarshal(ext.Value, &bc); err != nil {
+				lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_csr_invalid_basic_constraints", Field: "extensionRequest", Finding: "Requested Basic Constraints extension could not be decoded"})
+			} else if bc.IsCA {
+				lres = append(lres, LintingResult{Severity: SEVERITY_WARNING, Code: "w_csr_requests_ca", Field: "extensionRequest", Finding: "Requested Basic Constraints extension asserts cA"})
+			}
+		}
+	}
+
+	return lreq.selectPkimetalResults(lres)
+}
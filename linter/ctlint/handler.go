@@ -43,7 +43,12 @@ func init() {
 		Url:          "https://github.com/crtsh/ctlint",
 		Unsupported:  linter.NonCertificateProfileIDs,
 		NumInstances: config.Config.Linter.Ctlint.NumGoroutines,
-		Interface:    func() linter.LinterInterface { return &Ctlint{} },
+		// Log list snapshots cannot yet be loaded from the data directory, so the log lists compiled into ctloglists are
+		// always used.
+		DataSnapshots: func() []linter.DataSnapshot {
+			return []linter.DataSnapshot{{Name: "ctloglists", Source: linter.DATASOURCE_EMBEDDED, Date: ctloglists.OldestTimestampForLogListWithEnforcementCutOff()}}
+		},
+		Interface: func() linter.LinterInterface { return &Ctlint{} },
 	}).Register()
 }
 
@@ -62,15 +67,22 @@ func (l *Ctlint) HandleRequest(ctx context.Context, lin *linter.LinterInstance,
 		return []linter.LintingResult{{Severity: linter.SEVERITY_FATAL, Finding: "Failed to parse certificate: " + err.Error()}}
 	}
 
+	var issuer *x509.Certificate
+	if lreq.Issuer != nil {
+		if issuer, err = x509.ParseCertificate(lreq.Issuer.Raw); err != nil {
+			return []linter.LintingResult{{Severity: linter.SEVERITY_FATAL, Finding: "Failed to parse issuer certificate: " + err.Error()}}
+		}
+	}
+
 	var results []string
 	if slices.Contains(linter.PrecertificateProfileIDs, lreq.ProfileId) {
 		results = ctlint.CheckPrecertificate(cert)
 	} else if slices.Contains(linter.TbrTevgLeafProfileIDs, lreq.ProfileId) {
-		results = ctlint.CheckCertificate(cert, nil, ctlint.ServerAuthenticationCertificate)
+		results = ctlint.CheckCertificate(cert, issuer, ctlint.ServerAuthenticationCertificate)
 	} else if slices.Contains(linter.MarkCertificateProfileIDs, lreq.ProfileId) {
-		results = ctlint.CheckCertificate(cert, nil, ctlint.MarkCertificate)
+		results = ctlint.CheckCertificate(cert, issuer, ctlint.MarkCertificate)
 	} else {
-		results = ctlint.CheckCertificate(cert, nil)
+		results = ctlint.CheckCertificate(cert, issuer)
 	}
 
 	for _, result := range results {
@@ -2,6 +2,9 @@ package dwklint
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
 
@@ -38,7 +41,7 @@ func init() {
 		Name:         "dwklint",
 		Version:      linter.GetPackageVersion("github.com/CVE-2008-0166/dwklint/v2"),
 		Url:          "https://github.com/CVE-2008-0166/dwklint",
-		Unsupported:  linter.NonCertificateProfileIDs,
+		Unsupported:  linter.NonPublicKeyProfileIDs,
 		NumInstances: config.Config.Linter.Dwklint.NumGoroutines,
 		Interface:    func() linter.LinterInterface { return &Dwklint{} },
 	}).Register()
@@ -57,7 +60,7 @@ func (l *Dwklint) StopInstance(lin *linter.LinterInstance) {
 func (l *Dwklint) HandleRequest(ctx context.Context, lin *linter.LinterInstance, lreq *linter.LintingRequest) []linter.LintingResult {
 	var lres linter.LintingResult
 
-	if cert, err := x509.ParseCertificate(lreq.DecodedInput); err != nil {
+	if cert, err := subjectPublicKeyCertificate(lreq); err != nil {
 		lres.Severity = linter.SEVERITY_FATAL
 		lres.Finding = fmt.Sprintf("Could not parse certificate: %v", err)
 	} else {
@@ -90,3 +93,28 @@ func (l *Dwklint) HandleRequest(ctx context.Context, lin *linter.LinterInstance,
 func (l *Dwklint) ProcessResult(lresult linter.LintingResult) linter.LintingResult {
 	return lresult
 }
+
+// subjectPublicKeyCertificate returns the request's certificate or, for other
+// inputs that carry a public key (e.g., CSRs), a certificate that contains
+// only that public key, since dwklint only checks certificates.
+func subjectPublicKeyCertificate(lreq *linter.LintingRequest) (*x509.Certificate, error) {
+	if lreq.Cert != nil {
+		return x509.ParseCertificate(lreq.DecodedInput)
+	}
+
+	spki := lreq.RawSubjectPublicKeyInfo()
+	publicKey, err := x509.ParsePKIXPublicKey(spki)
+	if err != nil {
+		return nil, err
+	}
+	cert := &x509.Certificate{PublicKey: publicKey, RawSubjectPublicKeyInfo: spki}
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		cert.PublicKeyAlgorithm = x509.RSA
+	case *ecdsa.PublicKey:
+		cert.PublicKeyAlgorithm = x509.ECDSA
+	case ed25519.PublicKey:
+		cert.PublicKeyAlgorithm = x509.Ed25519
+	}
+	return cert, nil
+}
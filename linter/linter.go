@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkimetal/pkimetal/config"
@@ -38,30 +39,54 @@ type Linter struct {
 	Url                   string
 	Unsupported           []ProfileId
 	NumInstances          int
-	ReqChannel            chan LintingRequest
-	ReadySignal           string // If set, an external backend emits this line once it has finished initialising.
+	ReqChannel            chan LintingRequest   // Interactive linting requests.
+	BulkReqChannel        chan LintingRequest   // Bulk linting requests.  If nil, they share ReqChannel.
+	ReadySignal           string                // If set, a legacy protocol backend emits this line once it has finished initialising.
+	ForwardsChecks        bool                  // If set, an external backend is sent the request's check selection alongside the profile ID.
+	Pipelined             bool                  // If set, several requests can be in flight to an external backend at once.
+	Protocol              int                   // The protocol that an external backend speaks: PROTOCOL_LEGACY or PROTOCOL_JSONLINES.
+	DataSnapshots         func() []DataSnapshot // If set, reports the datasets that the linter depends on.
 	external              bool
 	useHandleRequest      bool
-	queueTimeSummary      prometheus.Summary
+	queueTimeSummaries    [NUM_PRIORITIES]prometheus.Summary
 	processingTimeSummary prometheus.Summary
+	rejectedCounter       prometheus.Counter
+	recentRuntime         atomic.Int64 // Moving average of the processing time (ns), for estimating queue waits.
+	minInstances          int          // NumInstances, or fewer if the linter is permitted to scale down.
+	maxInstances          int          // NumInstances, or more if the linter is permitted to scale up.
+	runningInstances      atomic.Int64
+	extraInstances        []*LinterInstance // Instances added by scaling.  Only accessed by scaleLoop.
 	Interface             func() LinterInterface
 }
 
+// DataSnapshot describes a dataset that a linter (or pkimetal itself) depends on.
+type DataSnapshot struct {
+	Name   string
+	Source string    // DATASOURCE_EMBEDDED, or the file that the dataset was loaded from.
+	Date   time.Time // When the dataset was published (zero if unknown).
+}
+
+const DATASOURCE_EMBEDDED = "embedded"
+
 type LinterSlice []*Linter
 
 type LinterInstance struct {
 	*Linter
-	instanceNumber int
-	command        *exec.Cmd
-	Mutex          *sync.Mutex
-	Stdin          io.WriteCloser
-	Stdout         *bufio.Scanner
-	stderr         *bufio.Scanner
-	stdinFile      *os.File // Underlying STDIN pipe, retained so that write deadlines can be set.
-	stdoutFile     *os.File // Underlying STDOUT pipe, retained so that read deadlines can be set.
-	directory      string   // Retained so that the backend can be restarted after a failure.
-	cmd            string
-	args           []string
+	instanceNumber    int
+	command           *exec.Cmd
+	Mutex             *sync.Mutex
+	Stdin             io.WriteCloser
+	Stdout            *bufio.Scanner
+	stderr            *bufio.Scanner
+	stdinFile         *os.File // Underlying STDIN pipe, retained so that write deadlines can be set.
+	stdoutFile        *os.File // Underlying STDOUT pipe, retained so that read deadlines can be set.
+	directory         string   // Retained so that the backend can be restarted after a failure.
+	cmd               string
+	args              []string
+	interactiveStreak int                // Number of interactive requests processed since the last bulk request.
+	retire            context.CancelFunc // Stops an instance that was added by scaling.
+	spare             bool               // A preconfigured instance above minInstances, which scaling replaces.
+	lastBusyAt        atomic.Int64       // When the instance last began or finished a request (Unix ns), for scaling.
 }
 
 type LintingRequest struct {
@@ -69,9 +94,17 @@ type LintingRequest struct {
 	B64Input       string
 	DecodedInput   []byte
 	Cert           *x509.Certificate
+	Issuer         *x509.Certificate   // The issuer of Cert, if provided.
+	Chain          []*x509.Certificate // The rest of the chain above Issuer (issuer's issuer first), if provided.
+	Csr            *x509.CertificateRequest
+	Key            any    // The parsed public key, for public key input.
+	RawKey         []byte // The DER-encoded SubjectPublicKeyInfo, for public key input.
 	ProfileId      ProfileId
+	AsOf           time.Time // Evaluate time-dependent requirements as of this time (if zero, as of now).
+	Priority       PriorityClass
 	QueuedAt       time.Time
 	ChecksAdded    []string
+	ChecksNarrowed []string // If set, only those ChecksAdded that are also selected by these (e.g., a request's include within a custom profile's).
 	ChecksDisabled []string
 	RespChannel    chan LintingResult
 }
@@ -82,6 +115,28 @@ type LintingResult struct {
 	Field      string
 	Code       string
 	Severity   SeverityLevel
+	Structured bool           // The Finding only describes meta information that structured response formats report as typed fields.
+	Timing     *LintingTiming // Set on each linter's "Queued: ...; Runtime: ...; Version: ..." meta result.
+	Waived     *Waived        // Set if an unexpired waiver matched this result.
+	Remapped   *SeverityLevel // Set to the original severity if a configured severity mapping changed it.
+	// Set (to the date, YYYY-MM-DD, from which the requirement is effective) if the result is only suppressed because
+	// the requirement is not yet effective as of the request's as-of date.
+	NotYetEffective string
+}
+
+// Waived describes the waiver that matched a result.
+type Waived struct {
+	Justification    string
+	Ticket           string `json:"Ticket,omitempty"`
+	Expires          string `json:"Expires,omitempty"`
+	OriginalSeverity string `json:"OriginalSeverity,omitempty"` // Set if the waiver downgraded the result's severity.
+}
+
+// LintingTiming holds the queue time and runtime of a linter's handling of a request.
+type LintingTiming struct {
+	QueuedFor time.Duration
+	Runtime   time.Duration
+	Failed    bool // The linter's backend crashed, desynced, or timed out whilst handling the request.
 }
 
 var (
@@ -103,26 +158,35 @@ func (l *Linter) Register() {
 	if l.NumInstances > 0 {
 		// Register this linter.
 		logger.Logger.Info("Registering Linter", zap.Int("nInstances", l.NumInstances), zap.String("name", l.Name), zap.String("version", l.Version))
-		registerLinterWithProfiles(l)
+		registerLinterWithProfiles(l, AUTODETECT)
 		l.ReqChannel = make(chan LintingRequest, config.Config.Linter.MaxQueueSize)
+		l.BulkReqChannel = make(chan LintingRequest, config.Config.Linter.MaxQueueSize)
+
+		// Determine the range of instances that scaling may run.  Without scaling, the minimum is NumInstances.
+		l.minInstances = l.NumInstances
+		if n, ok := config.Config.Linter.Scaling.MinInstances[l.Name]; ok && config.Config.Linter.Scaling.Interval > 0 {
+			l.minInstances = max(n, 1)
+		}
+		l.maxInstances = max(config.Config.Linter.Scaling.MaxInstances[l.Name], l.NumInstances, l.minInstances)
 
-		// Preconfigure this linter's instances.
+		// Preconfigure this linter's instances.  An external backend's instances above the minimum are not started.
 		baseInstanceNumber := len(linterInstances)
-		for i := 0; i < l.NumInstances; i++ {
+		for i := 0; i < max(l.NumInstances, l.minInstances); i++ {
 			linterInstances = append(linterInstances, &LinterInstance{
 				Linter:         l,
 				instanceNumber: baseInstanceNumber + i,
 				Mutex:          &sync.Mutex{},
+				spare:          i >= l.minInstances,
 			})
 		}
 
-		l.queueTimeSummary = promauto.NewSummary(prometheus.SummaryOpts{
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{
 			Namespace:   config.ApplicationNamespace,
 			Subsystem:   "linter",
-			Name:        "queue_time",
-			Help:        "Number of seconds before processing a linting request.",
+			Name:        "instances",
+			Help:        "Number of running linter instances.",
 			ConstLabels: map[string]string{"linter_name": l.Name},
-		})
+		}, func() float64 { return float64(l.runningInstances.Load()) })
 		l.processingTimeSummary = promauto.NewSummary(prometheus.SummaryOpts{
 			Namespace:   config.ApplicationNamespace,
 			Subsystem:   "linter",
@@ -130,6 +194,37 @@ func (l *Linter) Register() {
 			Help:        "Number of seconds to process a linting request.",
 			ConstLabels: map[string]string{"linter_name": l.Name},
 		})
+		for priority := range NUM_PRIORITIES {
+			labels := map[string]string{"linter_name": l.Name, "priority": PriorityString[priority]}
+			l.queueTimeSummaries[priority] = promauto.NewSummary(prometheus.SummaryOpts{
+				Namespace:   config.ApplicationNamespace,
+				Subsystem:   "linter",
+				Name:        "queue_time",
+				Help:        "Number of seconds before processing a linting request.",
+				ConstLabels: labels,
+			})
+			promauto.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace:   config.ApplicationNamespace,
+				Subsystem:   "linter",
+				Name:        "queue_depth",
+				Help:        "Number of linting requests waiting to be processed.",
+				ConstLabels: labels,
+			}, func() float64 { return float64(l.QueueDepth(priority)) })
+			promauto.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace:   config.ApplicationNamespace,
+				Subsystem:   "linter",
+				Name:        "estimated_wait",
+				Help:        "Estimated number of seconds that a new linting request would be queued for.",
+				ConstLabels: labels,
+			}, func() float64 { return l.EstimatedWait(priority).Seconds() })
+		}
+		l.rejectedCounter = promauto.NewCounter(prometheus.CounterOpts{
+			Namespace:   config.ApplicationNamespace,
+			Subsystem:   "linter",
+			Name:        "rejected_requests_total",
+			Help:        "Number of linting requests rejected because the queue was full or the estimated wait was too long.",
+			ConstLabels: map[string]string{"linter_name": l.Name},
+		})
 	} else {
 		logger.Logger.Info("Unused Linter", zap.String("name", l.Name))
 	}
@@ -149,30 +244,49 @@ func (slice LinterSlice) Swap(i, j int) {
 
 func StartLinters(ctx context.Context) {
 	generateOrderedListOfProfiles()
+	if err := checkCustomProfileLinters(); err != nil {
+		logger.Logger.Fatal("Invalid custom profile", zap.Error(err))
+	}
 
 	// Sort the linters by name.
 	sort.Sort(Linters)
 
 	for _, lin := range linterInstances {
-		if lif := lin.Interface(); lif != nil {
-			logger.Logger.Info("Starting Linter", zap.Int("instance#", lin.instanceNumber), zap.String("name", lin.Name))
-
-			// Determine whether this backend runs as an external process; if so, it is
-			// started (and warmed up) by the server loop, serialised across all backends.
-			var directory, cmd string
-			var args []string
-			if lin.useHandleRequest, directory, cmd, args = lif.StartInstance(); len(cmd) > 0 {
-				lin.external = true
-				lin.directory, lin.cmd, lin.args = directory, cmd, args
-			}
+		lin.start(ctx)
+	}
 
-			// Run the linter server loop.
+	// Scale the linters whose backends run as external processes, if they are permitted more instances.
+	for _, l := range Linters {
+		if l.external && l.maxInstances > l.minInstances && config.Config.Linter.Scaling.Interval > 0 {
 			ShutdownWG.Add(1)
-			go lin.serverLoop(ctx, lif)
+			go l.scaleLoop(ctx)
 		}
 	}
 }
 
+func (lin *LinterInstance) start(ctx context.Context) {
+	if lif := lin.Interface(); lif != nil {
+		logger.Logger.Info("Starting Linter", zap.Int("instance#", lin.instanceNumber), zap.String("name", lin.Name))
+
+		// Determine whether this backend runs as an external process; if so, it is
+		// started (and warmed up) by the server loop, serialised across all backends.
+		var directory, cmd string
+		var args []string
+		if lin.useHandleRequest, directory, cmd, args = lif.StartInstance(); len(cmd) > 0 {
+			lin.external = true
+			lin.directory, lin.cmd, lin.args = directory, cmd, args
+			if lin.spare {
+				logger.Logger.Info("Not starting spare Linter", zap.Int("instance#", lin.instanceNumber), zap.String("name", lin.Name))
+				return // Scaling adds instances above the minimum when they are needed.
+			}
+		}
+
+		// Run the linter server loop.
+		ShutdownWG.Add(1)
+		go lin.serverLoop(ctx, lif)
+	}
+}
+
 func (lin *LinterInstance) startInstance_external(directory, cmd string, arg ...string) {
 	// Retain the start parameters so that the backend can be restarted after a failure.
 	lin.directory, lin.cmd, lin.args = directory, cmd, arg
@@ -252,9 +366,10 @@ func (lin *LinterInstance) restartInstance_external(reason error) {
 // warmUp waits for an external backend that advertises a readiness signal to
 // finish its (potentially slow) initialisation before it is sent any requests,
 // so that start-up cost is not charged against a request's backend timeout.  It
-// is a no-op for in-process backends and for backends with no ReadySignal.
+// is a no-op for in-process backends and for legacy protocol backends with no
+// ReadySignal.  JSON-lines protocol backends always send a ready envelope.
 func (lin *LinterInstance) warmUp() {
-	if lin.ReadySignal == "" || lin.stdoutFile == nil {
+	if (lin.ReadySignal == "" && lin.Protocol == PROTOCOL_LEGACY) || lin.stdoutFile == nil {
 		return
 	}
 	logger.Logger.Info("Warming up Linter backend", zap.Int("instance#", lin.instanceNumber), zap.String("name", lin.Name))
@@ -264,7 +379,7 @@ func (lin *LinterInstance) warmUp() {
 	// restarted, re-initialised, and time out again in a cascade.  A backend that
 	// crashes during init closes its STDOUT, which ends the scan.
 	for lin.Stdout.Scan() {
-		if lin.Stdout.Text() == lin.ReadySignal {
+		if lin.isReady(lin.Stdout.Text()) {
 			logger.Logger.Info("Linter backend ready", zap.Int("instance#", lin.instanceNumber), zap.String("name", lin.Name))
 			return
 		}
@@ -365,6 +480,17 @@ func parseResultToken(linterName, token string) (results []LintingResult, end bo
 	return
 }
 
+// requestHeader returns the line that precedes the input in a request sent to an
+// external backend: the profile name, followed (for backends that apply the check
+// selection themselves) by tab-separated lists of included and excluded checks.
+// Profile names, unlike profile IDs, are stable across releases.
+func (lin *LinterInstance) requestHeader(lreq *LintingRequest) string {
+	if !lin.ForwardsChecks {
+		return lreq.ProfileId.String()
+	}
+	return fmt.Sprintf("%s\t%s\t%s", lreq.ProfileId, strings.Join(lreq.forwardedChecksAdded(), ","), strings.Join(lreq.ChecksDisabled, ","))
+}
+
 func (lin *LinterInstance) serverLoop(ctx context.Context, lif LinterInterface) {
 	defer ShutdownWG.Done()
 
@@ -388,128 +514,167 @@ func (lin *LinterInstance) serverLoop(ctx context.Context, lif LinterInterface)
 		lin.warmUp()
 	}
 
+	// Count this instance as running, for scaling and for estimating queue waits.
+	lin.runningInstances.Add(1)
+	defer lin.runningInstances.Add(-1)
+
+	if lin.Pipelined && !lin.useHandleRequest {
+		lin.servePipelined(ctx, lif)
+		return
+	}
+
 	for {
-		select {
-		case lreq := <-lin.ReqChannel: // Multiple backends can share the same request channel, but only one backend will receive each request.
-			// Acquire mutex.  Each internal or external backend will only process one linting request at a time.
-			lin.Mutex.Lock()
-
-			// Skip requests whose deadline has already passed (e.g. whilst queued);
-			// the client has stopped waiting, so there is no point processing them.
-			if lreq.Ctx.Err() != nil {
-				lin.Mutex.Unlock()
-				continue
-			}
+		// Multiple backends share the same request channels, but only one backend will receive each request.
+		lreq, ok := lin.nextRequest(ctx)
+		if !ok {
+			return // Respond to graceful shutdown requests.
+		}
+		lin.lastBusyAt.Store(time.Now().UnixNano())
 
-			// Record how long this linting request was queued for.
-			queuedFor := time.Since(lreq.QueuedAt)
-			start := time.Now()
+		// Acquire mutex.  Each internal or external backend will only process one linting request at a time.
+		lin.Mutex.Lock()
 
-			if lin.useHandleRequest {
-				// Process this linting request in-process, bounded by the request's deadline.
-				for _, lres := range lif.HandleRequest(lreq.Ctx, lin, &lreq) {
-					lres.LinterName = lin.Name
-					if !lin.sendResult(&lreq, lres) {
-						break
-					}
-				}
+		// Skip requests whose deadline has already passed (e.g. whilst queued);
+		// the client has stopped waiting, so there is no point processing them.
+		if lreq.Ctx.Err() != nil {
+			lin.Mutex.Unlock()
+			continue
+		}
+
+		// Record how long this linting request was queued for.
+		queuedFor := time.Since(lreq.QueuedAt)
+		start := time.Now()
+		failed := false
 
-			} else {
-				// Bound the subprocess I/O by a backend timeout measured from now, so
-				// that time spent queued does not count against the backend and a
-				// slow-but-healthy backend is not restarted just because the client
-				// gave up.
-				backendDeadline := time.Now().Add(config.Config.Linter.BackendTimeout)
-				if lin.stdinFile != nil {
-					_ = lin.stdinFile.SetWriteDeadline(backendDeadline)
+		if lin.useHandleRequest {
+			// Process this linting request in-process, bounded by the request's deadline.
+			for _, lres := range lif.HandleRequest(lreq.Ctx, lin, &lreq) {
+				if !lreq.isResultSelected(lres) {
+					continue
 				}
-				if lin.stdoutFile != nil {
-					_ = lin.stdoutFile.SetReadDeadline(backendDeadline)
+				lres.LinterName = lin.Name
+				if !lin.sendResult(&lreq, lres) {
+					break
 				}
+			}
 
-				var err error
-				clientGone := false
-			label_forloop:
-				// Write the request to the linter backend's STDIN.
-				for _, err = lin.Stdin.Write(utils.S2B(fmt.Sprintf("%d\n%s\n", lreq.ProfileId, strings.TrimSpace(lreq.B64Input)))); err == nil; {
-					// Scan the next token from the linter backend's STDOUT.
-					if !lin.Stdout.Scan() {
-						if err = lin.Stdout.Err(); err == nil {
-							err = fmt.Errorf("stdout.Scan() => false")
-						}
-						break label_forloop
+		} else {
+			// Bound the subprocess I/O by a backend timeout measured from now, so
+			// that time spent queued does not count against the backend and a
+			// slow-but-healthy backend is not restarted just because the client
+			// gave up.
+			backendDeadline := time.Now().Add(config.Config.Linter.BackendTimeout)
+			if lin.stdinFile != nil {
+				_ = lin.stdinFile.SetWriteDeadline(backendDeadline)
+			}
+			if lin.stdoutFile != nil {
+				_ = lin.stdoutFile.SetReadDeadline(backendDeadline)
+			}
+
+			// Write the request to the linter backend's STDIN.
+			request, err := lin.encodeRequest(0, &lreq)
+			if err == nil {
+				_, err = lin.Stdin.Write(request)
+			}
+			clientGone := false
+		label_forloop:
+			for err == nil {
+				// Scan the next token from the linter backend's STDOUT.
+				if !lin.Stdout.Scan() {
+					if err = lin.Stdout.Err(); err == nil {
+						err = fmt.Errorf("stdout.Scan() => false")
 					}
+					break label_forloop
+				}
 
-					// Parse the response token from the linter backend's STDOUT into linting result(s).
-					var results []LintingResult
-					var end bool
-					if results, end, err = parseResultToken(lin.Name, lin.Stdout.Text()); err != nil || end {
-						break label_forloop
+				// Parse the response from the linter backend's STDOUT into linting result(s).
+				var id uint64
+				var results []LintingResult
+				var end bool
+				if id, results, end, err = lin.parseResponse(lin.Stdout.Text()); err == nil && id != 0 {
+					err = fmt.Errorf("unknown request ID: %d", id)
+				}
+				if err != nil {
+					break label_forloop
+				}
+				for _, lresult := range results {
+					// Deliver results whilst the client is still waiting.  Once it
+					// has given up, keep reading the backend to completion so that
+					// the backend stays in sync and warm, but stop delivering.
+					if clientGone || !lreq.isResultSelected(lresult) {
+						continue
 					}
-					for _, lresult := range results {
-						// Deliver results whilst the client is still waiting.  Once it
-						// has given up, keep reading the backend to completion so that
-						// the backend stays in sync and warm, but stop delivering.
-						if clientGone {
-							continue
-						}
-						if !lin.sendResult(&lreq, lif.ProcessResult(lresult)) {
-							clientGone = true
-						}
+					if !lin.sendResult(&lreq, lif.ProcessResult(lresult)) {
+						clientGone = true
 					}
 				}
-				// Clear the subprocess I/O deadlines.
-				if lin.stdinFile != nil {
-					_ = lin.stdinFile.SetWriteDeadline(time.Time{})
-				}
-				if lin.stdoutFile != nil {
-					_ = lin.stdoutFile.SetReadDeadline(time.Time{})
+				if end {
+					break label_forloop
 				}
+			}
+			// Clear the subprocess I/O deadlines.
+			if lin.stdinFile != nil {
+				_ = lin.stdinFile.SetWriteDeadline(time.Time{})
+			}
+			if lin.stdoutFile != nil {
+				_ = lin.stdoutFile.SetReadDeadline(time.Time{})
+			}
 
-				// A non-nil error means the backend crashed, desynced, or exceeded the
-				// backend timeout: report it (if the client is still waiting) and
-				// restart the backend so that subsequent requests are unaffected.  A
-				// client that merely gave up whilst the backend was healthy does not
-				// trigger a restart.
-				if err != nil {
-					if !clientGone {
-						finding := fmt.Sprintf("%s: %v", lin.Name, err)
-						if os.IsTimeout(err) {
-							finding = fmt.Sprintf("%s: linting backend timed out", lin.Name)
-						}
-						lin.sendResult(&lreq, LintingResult{
-							LinterName: PKIMETAL_NAME,
-							Severity:   SEVERITY_FATAL,
-							Finding:    finding,
-						})
-					}
-					lin.restartInstance_external(err)
+			// A non-nil error means the backend crashed, desynced, or exceeded the
+			// backend timeout: report it (if the client is still waiting) and
+			// restart the backend so that subsequent requests are unaffected.  A
+			// client that merely gave up whilst the backend was healthy does not
+			// trigger a restart.
+			if err != nil {
+				failed = true
+				if !clientGone {
+					lin.sendBackendFailure(&lreq, err)
 				}
+				lin.restartInstance_external(err)
 			}
-			// Record meta information.
-			runtime := time.Since(start)
-			lin.sendResult(&lreq, LintingResult{
-				LinterName: lin.Name,
-				Severity:   SEVERITY_META,
-				Finding:    fmt.Sprintf("Queued: %v; Runtime: %v; Version: %s", queuedFor, runtime, VersionString(lin.Version)),
-			})
-			lin.queueTimeSummary.Observe(float64(queuedFor) / float64(time.Second))
-			lin.processingTimeSummary.Observe(float64(runtime) / float64(time.Second))
-
-			// Add a dummy linting result to signal the end of the results.
-			lin.sendResult(&lreq, LintingResult{
-				LinterName: PKIMETAL_NAME,
-				Severity:   SEVERITY_META,
-				Finding:    PKIMETAL_ENDOFRESULTS,
-			})
+		}
+		lin.finishRequest(&lreq, queuedFor, time.Since(start), failed)
 
-			lin.Mutex.Unlock()
+		lin.Mutex.Unlock()
+	}
+}
 
-		// Respond to graceful shutdown requests.
-		case <-ctx.Done():
-			return
-		}
+// sendBackendFailure reports that the backend crashed, desynced, or exceeded the backend timeout whilst handling the
+// request.
+func (lin *LinterInstance) sendBackendFailure(lreq *LintingRequest, err error) {
+	finding := fmt.Sprintf("%s: %v", lin.Name, err)
+	if os.IsTimeout(err) {
+		finding = fmt.Sprintf("%s: linting backend timed out", lin.Name)
 	}
+	lin.sendResult(lreq, LintingResult{
+		LinterName: PKIMETAL_NAME,
+		Severity:   SEVERITY_FATAL,
+		Finding:    finding,
+	})
+}
+
+// finishRequest records the request's meta information and metrics, then signals the end of its results.
+func (lin *LinterInstance) finishRequest(lreq *LintingRequest, queuedFor, runtime time.Duration, failed bool) {
+	lin.lastBusyAt.Store(time.Now().UnixNano())
+
+	// Record meta information.
+	lin.sendResult(lreq, LintingResult{
+		LinterName: lin.Name,
+		Severity:   SEVERITY_META,
+		Finding:    fmt.Sprintf("Queued: %v; Runtime: %v; Version: %s", queuedFor, runtime, VersionString(lin.Version)),
+		Structured: true,
+		Timing:     &LintingTiming{QueuedFor: queuedFor, Runtime: runtime, Failed: failed},
+	})
+	lin.queueTimeSummaries[lreq.Priority].Observe(float64(queuedFor) / float64(time.Second))
+	lin.processingTimeSummary.Observe(float64(runtime) / float64(time.Second))
+	lin.recordRuntime(runtime)
+
+	// Add a dummy linting result to signal the end of the results.
+	lin.sendResult(lreq, LintingResult{
+		LinterName: PKIMETAL_NAME,
+		Severity:   SEVERITY_META,
+		Finding:    PKIMETAL_ENDOFRESULTS,
+	})
 }
 
 func GetPackageVersion(packageNamePrefix string) string {
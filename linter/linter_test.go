@@ -2,8 +2,20 @@ package linter
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
 	"slices"
 	"strings"
@@ -14,6 +26,9 @@ import (
 	"github.com/pkimetal/pkimetal/config"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zmap/zcrypto/encoding/asn1"
+	zrsa "github.com/zmap/zcrypto/rsa"
+	"github.com/zmap/zcrypto/x509"
 )
 
 // --- parseResultToken ---
@@ -126,6 +141,605 @@ func TestParseResultToken_Malformed(t *testing.T) {
 	}
 }
 
+// --- check selection ---
+
+func TestParseCheckSelection(t *testing.T) {
+	patterns, err := ParseCheckSelection(" w_ext_*, ,e_some_lint ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(patterns, []string{"w_ext_*", "e_some_lint"}) {
+		t.Errorf("got %q", patterns)
+	}
+	if patterns, err = ParseCheckSelection(""); err != nil || patterns != nil {
+		t.Errorf("empty selection: got %q, %v", patterns, err)
+	}
+	if _, err = ParseCheckSelection("w_[ext"); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
+
+func TestIsCheckSelected(t *testing.T) {
+	lreq := LintingRequest{ChecksAdded: []string{"w_ext_*", "e_a"}, ChecksDisabled: []string{"w_ext_b"}}
+	cases := map[string]bool{
+		"w_ext_a": true,
+		"w_ext_b": false, // Excluded takes precedence over included.
+		"e_a":     true,
+		"e_b":     false, // Not included.
+	}
+	for code, want := range cases {
+		if got := lreq.IsCheckSelected(code); got != want {
+			t.Errorf("%q: got %v, want %v", code, got, want)
+		}
+	}
+
+	if !(&LintingRequest{}).IsCheckSelected("anything") {
+		t.Error("all checks should be selected when there is no check selection")
+	}
+}
+
+func TestIsCheckSelected_Narrowed(t *testing.T) {
+	// A request's include can narrow a custom profile's, but cannot widen it.
+	lreq := LintingRequest{ChecksAdded: []string{"e_*"}, ChecksNarrowed: []string{"*"}, ChecksDisabled: []string{"e_ext_*"}}
+	for code, want := range map[string]bool{"e_a": true, "w_a": false, "e_ext_a": false} {
+		if got := lreq.IsCheckSelected(code); got != want {
+			t.Errorf("%q: got %v, want %v", code, got, want)
+		}
+	}
+	lreq.ChecksNarrowed = []string{"e_a", "w_a"}
+	for code, want := range map[string]bool{"e_a": true, "e_b": false, "w_a": false} {
+		if got := lreq.IsCheckSelected(code); got != want {
+			t.Errorf("%q: got %v, want %v", code, got, want)
+		}
+	}
+	if lreq = (LintingRequest{ChecksNarrowed: []string{"e_a"}}); !lreq.HasCheckSelection() {
+		t.Error("expected a check selection")
+	}
+
+	// Backends that apply the check selection themselves are sent the narrower list.
+	lin := LinterInstance{Linter: &Linter{ForwardsChecks: true}}
+	lreq = LintingRequest{ProfileId: RFC5280_LEAF, ChecksAdded: []string{"e_*"}, ChecksNarrowed: []string{"e_a"}}
+	if got := lin.requestHeader(&lreq); got != "rfc5280_leaf\te_a\t" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestIsResultSelected(t *testing.T) {
+	lreq := LintingRequest{ChecksAdded: []string{"cabf.*", "*weak key*"}}
+	cases := []struct {
+		lres LintingResult
+		want bool
+	}{
+		{LintingResult{Code: "cabf.some_finding", Severity: SEVERITY_ERROR}, true},
+		{LintingResult{Code: "pkix.some_finding", Severity: SEVERITY_ERROR}, false},
+		{LintingResult{Finding: "Public Key is a Debian weak key", Severity: SEVERITY_ERROR}, true},
+		{LintingResult{Finding: "Unrelated finding", Severity: SEVERITY_WARNING}, false},
+		{LintingResult{Finding: "Exception: boom", Severity: SEVERITY_FATAL}, true}, // Linter failures are never filtered.
+	}
+	for _, c := range cases {
+		if got := lreq.isResultSelected(c.lres); got != c.want {
+			t.Errorf("%+v: got %v, want %v", c.lres, got, c.want)
+		}
+	}
+}
+
+func TestRequestHeader(t *testing.T) {
+	lreq := LintingRequest{ProfileId: RFC5280_LEAF, ChecksAdded: []string{"a*", "b"}, ChecksDisabled: []string{"c"}}
+	lin := LinterInstance{Linter: &Linter{}}
+	if got := lin.requestHeader(&lreq); got != "rfc5280_leaf" {
+		t.Errorf("got %q, want %q", got, "rfc5280_leaf")
+	}
+	lin.ForwardsChecks = true
+	if got := lin.requestHeader(&lreq); got != "rfc5280_leaf\ta*,b\tc" {
+		t.Errorf("got %q, want %q", got, "rfc5280_leaf\ta*,b\tc")
+	}
+}
+
+func TestEncodeRequest(t *testing.T) {
+	lreq := LintingRequest{
+		B64Input:       "-----BEGIN CERTIFICATE-----\nAQI=\n-----END CERTIFICATE-----\n",
+		DecodedInput:   []byte{1, 2},
+		ProfileId:      RFC5280_LEAF,
+		AsOf:           time.Date(2025, 3, 15, 12, 0, 0, 0, time.FixedZone("", 3600)),
+		ChecksAdded:    []string{"a*"},
+		ChecksDisabled: []string{"c"},
+	}
+	lin := LinterInstance{Linter: &Linter{}}
+	cases := []struct {
+		protocol       int
+		pipelined      bool
+		forwardsChecks bool
+		want           string
+	}{
+		{PROTOCOL_LEGACY, false, false, "rfc5280_leaf\n-----BEGIN CERTIFICATE-----\nAQI=\n-----END CERTIFICATE-----\n"},
+		{PROTOCOL_LEGACY, true, false, "7\trfc5280_leaf\n-----BEGIN CERTIFICATE-----\nAQI=\n-----END CERTIFICATE-----\n"},
+		{PROTOCOL_JSONLINES, false, false, `{"v":1,"id":7,"profile":"rfc5280_leaf","type":"certificate","der":"AQI=","options":{"asof":"2025-03-15T11:00:00Z"}}` + "\n"},
+		{PROTOCOL_JSONLINES, true, true, `{"v":1,"id":7,"profile":"rfc5280_leaf","type":"certificate","der":"AQI=","options":{"include":["a*"],"exclude":["c"],"asof":"2025-03-15T11:00:00Z"}}` + "\n"},
+	}
+	for _, c := range cases {
+		lin.Protocol, lin.Pipelined, lin.ForwardsChecks = c.protocol, c.pipelined, c.forwardsChecks
+		got, err := lin.encodeRequest(7, &lreq)
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", c, err)
+		} else if string(got) != c.want {
+			t.Errorf("%+v: got %q, want %q", c, got, c.want)
+		}
+	}
+}
+
+func TestParseResponse_JSONLines(t *testing.T) {
+	lin := LinterInstance{Linter: &Linter{Name: "stub", Protocol: PROTOCOL_JSONLINES}}
+
+	id, results, end, err := lin.parseResponse(`{"v":1,"id":3,"findings":[{"severity":"WARNING","code":"w_code","field":"tbsCertificate","message":"Warning message"},{"severity":"error","code":"e_code"}]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if id != 3 || end {
+		t.Errorf("got id %d, end %v; want 3, false", id, end)
+	}
+	want := []LintingResult{
+		{LinterName: "stub", Finding: "Warning message", Field: "tbsCertificate", Code: "w_code", Severity: SEVERITY_WARNING},
+		{LinterName: "stub", Finding: "e_code", Code: "e_code", Severity: SEVERITY_ERROR},
+	}
+	if !slices.Equal(results, want) {
+		t.Errorf("got %+v, want %+v", results, want)
+	}
+
+	// A backend error is reported as a fatal finding, and may share an envelope with the end marker.
+	id, results, end, err = lin.parseResponse(`{"v":1,"id":4,"error":"unparseable input","end":true}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if id != 4 || !end {
+		t.Errorf("got id %d, end %v; want 4, true", id, end)
+	}
+	if len(results) != 1 || results[0].Severity != SEVERITY_FATAL || results[0].Finding != "unparseable input" {
+		t.Errorf("got %+v, want a single fatal result", results)
+	}
+
+	for _, line := range []string{
+		`E: legacy token`,
+		`{"v":2,"id":1,"end":true}`,
+		`{"id":1,"end":true}`,
+		`{"v":1,"id":1,"findings":[{"severity":"catastrophic","code":"x"}]}`,
+		`{"v":1,"ready":true}`,
+	} {
+		if _, _, _, err := lin.parseResponse(line); err == nil {
+			t.Errorf("%q: expected an error, got nil", line)
+		}
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	lin := LinterInstance{Linter: &Linter{ReadySignal: PKIMETAL_READY}}
+	if !lin.isReady(PKIMETAL_READY) || lin.isReady(`{"v":1,"ready":true}`) {
+		t.Error("legacy protocol: wrong readiness")
+	}
+	lin.Protocol = PROTOCOL_JSONLINES
+	if !lin.isReady(`{"v":1,"ready":true}`) || lin.isReady(PKIMETAL_READY) || lin.isReady(`{"v":2,"ready":true}`) || lin.isReady(`{"v":1,"id":1,"end":true}`) {
+		t.Error("JSON-lines protocol: wrong readiness")
+	}
+}
+
+func TestProfileNameList(t *testing.T) {
+	if got, want := ProfileNameList([]ProfileId{TBR_CRL, TBR_ARL}), `"tbr_crl","tbr_arl"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// --- chain findings ---
+
+// testChainCert creates a certificate from the template, signed by the parent
+// (or self-signed if parent is nil), and parses it with zcrypto.
+func testChainCert(t *testing.T, template *stdx509.Certificate, key *ecdsa.PrivateKey, parent *stdx509.Certificate, parentKey *ecdsa.PrivateKey) (*stdx509.Certificate, *x509.Certificate) {
+	t.Helper()
+	template.SerialNumber = big.NewInt(1)
+	template.NotBefore, template.NotAfter = time.Now(), time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := stdx509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	std, _ := stdx509.ParseCertificate(der)
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+	return std, cert
+}
+
+func testChainKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return key
+}
+
+func chainFindingCodes(lres []LintingResult) []string {
+	var codes []string
+	for _, lr := range lres {
+		codes = append(codes, lr.Field+":"+lr.Code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+func TestChainFindings_NoIssuer(t *testing.T) {
+	if lres := (&LintingRequest{Cert: &x509.Certificate{}}).ChainFindings(); lres != nil {
+		t.Errorf("got %v, want no findings", lres)
+	}
+}
+
+func TestChainFindings(t *testing.T) {
+	rootKey, caKey, leafKey := testChainKey(t), testChainKey(t), testChainKey(t)
+	rootStd, root := testChainCert(t, &stdx509.Certificate{Subject: pkix.Name{CommonName: "Root"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: stdx509.KeyUsageCertSign, SubjectKeyId: []byte{1}}, rootKey, nil, nil)
+	caStd, ca := testChainCert(t, &stdx509.Certificate{Subject: pkix.Name{CommonName: "CA"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: stdx509.KeyUsageCertSign, SubjectKeyId: []byte{2},
+		PermittedDNSDomains: []string{"example.com"}, ExcludedIPRanges: []*net.IPNet{{IP: net.IP{192, 0, 2, 0}, Mask: net.CIDRMask(24, 32)}}}, caKey, rootStd, rootKey)
+	_, leaf := testChainCert(t, &stdx509.Certificate{Subject: pkix.Name{CommonName: "Leaf"}, DNSNames: []string{"www.example.com"}}, leafKey, caStd, caKey)
+	_, badLeaf := testChainCert(t, &stdx509.Certificate{Subject: pkix.Name{CommonName: "Bad Leaf"}, DNSNames: []string{"www.example.org"}, IPAddresses: []net.IP{{192, 0, 2, 1}}}, leafKey, caStd, caKey)
+	_, notCA := testChainCert(t, &stdx509.Certificate{Subject: pkix.Name{CommonName: "CA"}, BasicConstraintsValid: true, KeyUsage: stdx509.KeyUsageDigitalSignature, SubjectKeyId: []byte{3}}, leafKey, rootStd, rootKey)
+
+	cases := []struct {
+		name string
+		lreq LintingRequest
+		want []string
+	}{
+		{"valid chain", LintingRequest{Cert: leaf, Issuer: ca, Chain: []*x509.Certificate{root}}, nil},
+		{"name constraints", LintingRequest{Cert: badLeaf, Issuer: ca}, []string{"issuer:e_chain_name_constraints_violated", "issuer:e_chain_name_constraints_violated"}},
+		{"wrong issuer", LintingRequest{Cert: leaf, Issuer: root}, []string{"certificate:e_chain_aki_ski_mismatch", "certificate:e_chain_issuer_dn_mismatch", "certificate:e_chain_signature_invalid"}},
+		{"issuer not a CA", LintingRequest{Cert: leaf, Issuer: notCA}, []string{"certificate:e_chain_aki_ski_mismatch", "certificate:e_chain_issuer_no_keycertsign", "certificate:e_chain_issuer_not_ca", "certificate:e_chain_signature_invalid"}},
+		{"broken chain", LintingRequest{Cert: leaf, Issuer: ca, Chain: []*x509.Certificate{ca}}, []string{"issuer:e_chain_aki_ski_mismatch", "issuer:e_chain_issuer_dn_mismatch", "issuer:e_chain_signature_invalid"}},
+		{"excluded check", LintingRequest{Cert: leaf, Issuer: root, ChecksDisabled: []string{"e_chain_*_mismatch"}}, []string{"certificate:e_chain_signature_invalid"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lres := c.lreq.ChainFindings()
+			if got := chainFindingCodes(lres); !slices.Equal(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+			for _, lr := range lres {
+				if lr.LinterName != PKIMETAL_NAME {
+					t.Errorf("got linter name %q, want %q", lr.LinterName, PKIMETAL_NAME)
+				}
+			}
+		})
+	}
+}
+
+func TestDNSNameMatchesConstraint(t *testing.T) {
+	cases := []struct {
+		name, constraint string
+		want             bool
+	}{
+		{"example.com", "example.com", true},
+		{"www.EXAMPLE.com", "example.com", true},
+		{"badexample.com", "example.com", false},
+		{"example.com", ".example.com", false},
+		{"www.example.com", ".example.com", true},
+		{"anything.test", "", true},
+	}
+	for _, c := range cases {
+		if got := dnsNameMatchesConstraint(c.name, c.constraint); got != c.want {
+			t.Errorf("%q vs %q: got %v, want %v", c.name, c.constraint, got, c.want)
+		}
+	}
+}
+
+func TestEmailAddressMatchesConstraint(t *testing.T) {
+	cases := []struct {
+		email, constraint string
+		want              bool
+	}{
+		{"user@example.com", "user@example.com", true},
+		{"other@example.com", "user@example.com", false},
+		{"user@example.com", "example.com", true},
+		{"user@mail.example.com", "example.com", false},
+		{"user@mail.example.com", ".example.com", true},
+	}
+	for _, c := range cases {
+		if got := emailAddressMatchesConstraint(c.email, c.constraint); got != c.want {
+			t.Errorf("%q vs %q: got %v, want %v", c.email, c.constraint, got, c.want)
+		}
+	}
+}
+
+// --- CSR and public key findings ---
+
+func testCSR(t *testing.T, template *stdx509.CertificateRequest, key crypto.Signer) *x509.CertificateRequest {
+	t.Helper()
+	der, err := stdx509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatalf("creating CSR: %v", err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		t.Fatalf("parsing CSR: %v", err)
+	}
+	return csr
+}
+
+func TestCSRFindings(t *testing.T) {
+	key := testChainKey(t)
+	caExt, _ := asn1.Marshal(struct{ IsCA bool }{true})
+	good := testCSR(t, &stdx509.CertificateRequest{Subject: pkix.Name{CommonName: "example.com"}}, key)
+	ca := testCSR(t, &stdx509.CertificateRequest{Subject: pkix.Name{CommonName: "CA"}, ExtraExtensions: []pkix.Extension{{Id: []int(oidExtension_BasicConstraints), Critical: true, Value: caExt}}}, key)
+	badSignature := testCSR(t, &stdx509.CertificateRequest{Subject: pkix.Name{CommonName: "example.com"}}, key)
+	badSignature.Signature[len(badSignature.Signature)-1] ^= 0xff
+
+	cases := []struct {
+		name string
+		lreq LintingRequest
+		want []string
+	}{
+		{"no CSR", LintingRequest{}, nil},
+		{"valid CSR", LintingRequest{Csr: good}, []string{"subjectPublicKeyInfo:"}},
+		{"CA requested", LintingRequest{Csr: ca}, []string{"extensionRequest:w_csr_requests_ca", "subjectPublicKeyInfo:"}},
+		{"bad signature", LintingRequest{Csr: badSignature}, []string{"signature:e_csr_signature_invalid", "subjectPublicKeyInfo:"}},
+		{"excluded check", LintingRequest{Csr: badSignature, ChecksDisabled: []string{"e_csr_*"}}, []string{"subjectPublicKeyInfo:"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := chainFindingCodes(c.lreq.CSRFindings()); !slices.Equal(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPublicKeyFindings(t *testing.T) {
+	rsa1024, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	ed, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	cases := []struct {
+		name      string
+		publicKey any
+		want      []string
+	}{
+		{"RSA-1024", &rsa1024.PublicKey, []string{"subjectPublicKeyInfo:", "subjectPublicKeyInfo:e_key_rsa_too_small"}},
+		{"RSA even exponent", &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 2047), E: 4}, []string{"subjectPublicKeyInfo:", "subjectPublicKeyInfo:e_key_rsa_invalid_exponent"}},
+		{"RSA small exponent", &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 2047), E: 3}, []string{"subjectPublicKeyInfo:", "subjectPublicKeyInfo:w_key_rsa_small_exponent"}},
+		{"zcrypto RSA", &zrsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 2047), E: big.NewInt(65537)}, []string{"subjectPublicKeyInfo:"}},
+		{"zcrypto RSA even exponent", &zrsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 2047), E: big.NewInt(65536)}, []string{"subjectPublicKeyInfo:", "subjectPublicKeyInfo:e_key_rsa_invalid_exponent"}},
+		{"ECDSA P-256", &x509.AugmentedECDSA{Pub: &testChainKey(t).PublicKey}, []string{"subjectPublicKeyInfo:"}},
+		{"ECDSA P-224", &p224.PublicKey, []string{"subjectPublicKeyInfo:e_key_ecdsa_unsupported_curve"}},
+		{"Ed25519", ed, []string{"subjectPublicKeyInfo:"}},
+		{"unsupported", "not a key", []string{"subjectPublicKeyInfo:w_key_unsupported_type"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := chainFindingCodes(publicKeyFindings(c.publicKey)); !slices.Equal(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPublicKeyFindings_Request(t *testing.T) {
+	key := testChainKey(t)
+	spki, err := stdx509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshalling key: %v", err)
+	}
+	if lres := (&LintingRequest{Csr: testCSR(t, &stdx509.CertificateRequest{}, key)}).PublicKeyFindings(); lres != nil {
+		t.Errorf("CSR: got %v, want no findings", lres)
+	}
+	lreq := LintingRequest{Key: &key.PublicKey, RawKey: spki}
+	if got := chainFindingCodes(lreq.PublicKeyFindings()); !slices.Equal(got, []string{"subjectPublicKeyInfo:"}) {
+		t.Errorf("public key: got %v", got)
+	}
+	if got := lreq.RawSubjectPublicKeyInfo(); !bytes.Equal(got, spki) {
+		t.Error("public key: RawSubjectPublicKeyInfo does not return RawKey")
+	}
+}
+
+func TestPublicKeyProfileIDs(t *testing.T) {
+	if !slices.Equal(PublicKeyProfileIDs, []ProfileId{RFC5280_PUBLICKEY}) {
+		t.Errorf("got PublicKeyProfileIDs %v", PublicKeyProfileIDs)
+	}
+	if !slices.Contains(NonCertificateProfileIDs, RFC5280_PUBLICKEY) || !slices.Contains(CsrOrPublicKeyProfileIDs, RFC5280_PUBLICKEY) {
+		t.Error("RFC5280_PUBLICKEY should be a non-certificate profile")
+	}
+	if slices.Contains(NonPublicKeyProfileIDs, RFC5280_PUBLICKEY) || slices.Contains(TbrTevgCertificateProfileIDs, RFC5280_PUBLICKEY) {
+		t.Error("RFC5280_PUBLICKEY should only be supported by the key-quality linters")
+	}
+}
+
+func TestCsrProfileIDs(t *testing.T) {
+	if !slices.Equal(CsrProfileIDs, []ProfileId{RFC2986_CSR}) {
+		t.Errorf("got CsrProfileIDs %v", CsrProfileIDs)
+	}
+	if !slices.Contains(NonCertificateProfileIDs, RFC2986_CSR) {
+		t.Error("RFC2986_CSR should be a non-certificate profile")
+	}
+	if slices.Contains(NonPublicKeyProfileIDs, RFC2986_CSR) {
+		t.Error("RFC2986_CSR should not be a non-public key profile")
+	}
+	for _, id := range slices.Concat(CrlProfileIDs, OcspProfileIDs) {
+		if !slices.Contains(NonCertificateProfileIDs, id) || !slices.Contains(NonPublicKeyProfileIDs, id) {
+			t.Errorf("profile %d should be both a non-certificate and a non-public key profile", id)
+		}
+	}
+}
+
+// --- profile attributes ---
+
+func TestValidateProfiles(t *testing.T) {
+	if err := validateProfiles(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for id, want := range map[ProfileId]string{
+		TBR_LEAF_TLSSERVER_DV_PRECERTIFICATE: "tbr_leaf_tlsserver_dv",
+		TBR_LEAF_TLSSERVER_DV:                "rfc5280_leaf_tlsserver",
+		ETSI_LEAF_TLSSERVER_QEVCPWEIDAS:      "etsi_leaf_tlsserver_evcp",
+		RFC5280_ROOT:                         "",
+	} {
+		if got := AllProfiles[id].Parent; got != want {
+			t.Errorf("%s: got parent %q, want %q", AllProfiles[id].Name, got, want)
+		}
+	}
+}
+
+func TestValidateProfiles_Invalid(t *testing.T) {
+	for name, modify := range map[string]func(*Profile){
+		"no type":              func(p *Profile) { p.Type = "" },
+		"no standard":          func(p *Profile) { p.Standard = "" },
+		"no hierarchy":         func(p *Profile) { p.Hierarchy = "" },
+		"crl with hierarchy":   func(p *Profile) { p.Type = DOCUMENTTYPE_CRL },
+		"later parent":         func(p *Profile) { p.parent = TBR_LEAF_TLSSERVER_OV },
+		"parent of other type": func(p *Profile) { p.parent = RFC5280_CRL },
+		"bad effective date":   func(p *Profile) { p.EffectiveUntil = "2025-13-01" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			saved := AllProfiles[TBR_LEAF_TLSSERVER_DV]
+			t.Cleanup(func() { AllProfiles[TBR_LEAF_TLSSERVER_DV] = saved })
+			profile := saved
+			modify(&profile)
+			AllProfiles[TBR_LEAF_TLSSERVER_DV] = profile
+			if err := validateProfiles(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDerivedProfileIDs(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		list []ProfileId
+		in   []ProfileId
+		out  []ProfileId
+	}{
+		{"crl", CrlProfileIDs, []ProfileId{RFC5280_CRL, TBR_ARL}, []ProfileId{AUTODETECT, RFC6960_OCSPRESPONSE}},
+		{"subordinate", SubordinateProfileIDs, []ProfileId{TBR_CROSS_INTERNAL, SBR_SUBORDINATE_SMIME}, []ProfileId{TBR_ROOT_TLSSERVER}},
+		{"tbr/tevg leaf", TbrTevgLeafProfileIDs, []ProfileId{TBR_LEAF_OCSPSIGNING, TEVG_LEAF_TLSSERVER_EV_PRECERTIFICATE, ETSI_LEAF_TLSSERVER_DVCP, ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER}, []ProfileId{ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON, ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONEIDAS}},
+		{"tbr/tevg certificate", TbrTevgCertificateProfileIDs, []ProfileId{TBR_ROOT_TLSSERVER, TEVG_SUBORDINATE_TLSSERVER}, []ProfileId{TBR_CRL, ETSI_LEAF_TLSSERVER_DVCP}},
+		{"non-cabforum", NonCabforumProfileIDs, []ProfileId{AUTODETECT, ETSI_LEAF_NCPLEGALPERSON, BIMIGROUP_ROOT_BIMI}, []ProfileId{CSBR_LEAF_TIMESTAMPING}},
+		{"mark", MarkCertificateProfileIDs, []ProfileId{BIMIGROUP_LEAF_VERIFIEDMARK_PRECERTIFICATE}, []ProfileId{BIMIGROUP_SUBORDINATE_BIMI}},
+		{"etsi non-browser", EtsiNonBrowserCertificateProfileIDs, []ProfileId{ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER_PRECERTIFICATE}, []ProfileId{ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDAS}},
+		{"precertificate", PrecertificateProfileIDs, []ProfileId{ETSI_LEAF_TLSSERVER_EVCP_PRECERTIFICATE}, []ProfileId{ETSI_LEAF_TLSSERVER_EVCP}},
+	} {
+		for _, id := range tc.in {
+			if !slices.Contains(tc.list, id) {
+				t.Errorf("%s: expected %s to be included", tc.name, AllProfiles[id].Name)
+			}
+		}
+		for _, id := range tc.out {
+			if slices.Contains(tc.list, id) {
+				t.Errorf("%s: expected %s to be excluded", tc.name, AllProfiles[id].Name)
+			}
+		}
+	}
+}
+
+// --- custom profiles ---
+
+func TestRegisterCustomProfiles(t *testing.T) {
+	t.Cleanup(func() { _ = RegisterCustomProfiles(config.Config.Profiles.Custom) })
+	if err := RegisterCustomProfiles([]config.CustomProfile{{
+		Name:            "internal_tls",
+		BaseProfile:     "rfc5280_leaf_tlsserver",
+		DisabledLinters: []string{"certlint"},
+		Include:         []string{"e_*"},
+		Exclude:         []string{"e_ext_*"},
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	id, ok := GetProfileId("internal_tls")
+	if !ok || id != FIRST_CUSTOM_PROFILE {
+		t.Fatalf("got %d, %t", id, ok)
+	}
+	profile := AllProfiles[id]
+	if profile.Source != CUSTOM_PROFILE_SOURCE || profile.BaseProfile != "rfc5280_leaf_tlsserver" || profile.Description != "TLS Server Certificate" {
+		t.Errorf("got %+v", profile)
+	} else if profile.Type != DOCUMENTTYPE_CERTIFICATE || profile.Hierarchy != HIERARCHY_LEAF || profile.Standard != STANDARD_RFC5280 || profile.Parent != "rfc5280_leaf_tlsserver" {
+		t.Errorf("got attributes %+v, want those of the base profile", profile)
+	}
+	if base := BaseProfileId(id); base != RFC5280_LEAF_TLSSERVER {
+		t.Errorf("got base profile %d, want %d", base, RFC5280_LEAF_TLSSERVER)
+	} else if base = BaseProfileId(RFC5280_LEAF); base != RFC5280_LEAF {
+		t.Errorf("got base profile %d for a built-in profile", base)
+	}
+	if added, disabled := ProfileChecks(id); !slices.Equal(added, []string{"e_*"}) || !slices.Equal(disabled, []string{"e_ext_*"}) {
+		t.Errorf("got checks %v, %v", added, disabled)
+	}
+}
+
+func TestRegisterCustomProfiles_Linters(t *testing.T) {
+	t.Cleanup(func() { _ = RegisterCustomProfiles(config.Config.Profiles.Custom) })
+	saved := Linters
+	t.Cleanup(func() { Linters = saved })
+	Linters = LinterSlice{{Name: "zlint", NumInstances: 1}, {Name: "certlint", NumInstances: 1}, {Name: "unavailable"}}
+
+	// Re-registering custom profiles lists the linters that were already registered, once each.
+	cps := []config.CustomProfile{{Name: "internal_tls", BaseProfile: "rfc5280_leaf_tlsserver", DisabledLinters: []string{"certlint"}}}
+	for range 2 {
+		if err := RegisterCustomProfiles(cps); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var names []string
+	for _, name := range AllProfiles[FIRST_CUSTOM_PROFILE].Linters {
+		names = append(names, *name)
+	}
+	if !slices.Equal(names, []string{"zlint"}) {
+		t.Errorf("got %v, want [zlint]", names)
+	}
+}
+
+func TestRegisterCustomProfiles_Invalid(t *testing.T) {
+	t.Cleanup(func() { _ = RegisterCustomProfiles(config.Config.Profiles.Custom) })
+	for _, tc := range []struct {
+		name string
+		cps  []config.CustomProfile
+	}{
+		{"no name", []config.CustomProfile{{BaseProfile: "rfc5280_leaf"}}},
+		{"duplicate name", []config.CustomProfile{{Name: "rfc5280_leaf", BaseProfile: "rfc5280_leaf"}}},
+		{"duplicate custom name", []config.CustomProfile{{Name: "custom", BaseProfile: "rfc5280_leaf"}, {Name: "custom", BaseProfile: "rfc5280_leaf"}}},
+		{"no base", []config.CustomProfile{{Name: "custom"}}},
+		{"autodetect", []config.CustomProfile{{Name: "custom", BaseProfile: "autodetect"}}},
+		{"bad include", []config.CustomProfile{{Name: "custom", BaseProfile: "rfc5280_leaf", Include: []string{"["}}}},
+	} {
+		if err := RegisterCustomProfiles(tc.cps); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestLinterIsApplicable_CustomProfile(t *testing.T) {
+	t.Cleanup(func() { _ = RegisterCustomProfiles(config.Config.Profiles.Custom) })
+	if err := RegisterCustomProfiles([]config.CustomProfile{
+		{Name: "only_zlint", BaseProfile: "rfc5280_leaf", EnabledLinters: []string{"zlint"}},
+		{Name: "no_zlint_crl", BaseProfile: "rfc5280_crl", DisabledLinters: []string{"zlint"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zlint := &Linter{Name: "zlint"}
+	certOnly := &Linter{Name: "certlint", Unsupported: NonCertificateProfileIDs}
+
+	for _, tc := range []struct {
+		linter *Linter
+		id     ProfileId
+		want   bool
+	}{
+		{zlint, FIRST_CUSTOM_PROFILE, true},
+		{certOnly, FIRST_CUSTOM_PROFILE, false},     // Not enabled.
+		{zlint, FIRST_CUSTOM_PROFILE + 1, false},    // Disabled.
+		{certOnly, FIRST_CUSTOM_PROFILE + 1, false}, // Unsupported by the base profile.
+		{certOnly, RFC5280_LEAF, true},
+	} {
+		if got := tc.linter.IsApplicable(tc.id); got != tc.want {
+			t.Errorf("%s.IsApplicable(%s) = %t, want %t", tc.linter.Name, AllProfiles[tc.id].Name, got, tc.want)
+		}
+	}
+}
+
 // --- sendResult ---
 
 func TestSendResult_DeadlineExceeded(t *testing.T) {
@@ -167,19 +781,28 @@ const helperArg = "pkimetal-backend-helper"
 // linter backend.  It only activates when invoked with the helperArg sentinel,
 // so during a normal `go test` run it is a no-op.
 //
-// Protocol: for each request it reads a profile-id line and an input line, then
+// Protocol: for each request it reads a profile-name line and an input line, then
 // emits result token(s) terminated by the end-of-results sentinel.  The input
-// line doubles as a behaviour marker.
+// line doubles as a behaviour marker.  In pipelined mode, the profile-name line
+// and each result token are prefixed by the request ID.  In jsonlines mode, the
+// JSON-lines protocol is spoken instead (see helperJSONLines).
 func TestHelperProcess(t *testing.T) {
 	if !slices.Contains(os.Args, helperArg) {
 		return
+	} else if slices.Contains(os.Args, "jsonlines") {
+		helperJSONLines()
 	}
 	if slices.Contains(os.Args, "warmup") {
 		time.Sleep(400 * time.Millisecond) // Simulate slow initialisation.
 		fmt.Println(PKIMETAL_READY)
 	}
 	in := bufio.NewScanner(os.Stdin)
-	for in.Scan() { // Profile-id line.
+	for in.Scan() { // Profile-name line.
+		prefix := ""
+		if slices.Contains(os.Args, "pipelined") {
+			id, _, _ := strings.Cut(in.Text(), "\t")
+			prefix = id + "\t"
+		}
 		if !in.Scan() { // Input line.
 			break
 		}
@@ -190,9 +813,42 @@ func TestHelperProcess(t *testing.T) {
 			time.Sleep(2 * time.Second)
 		case "SLOWOK": // Respond, but only after the client is likely to have given up.
 			time.Sleep(150 * time.Millisecond)
+		case "BADID": // Respond with an unknown request ID (simulates a desync).
+			prefix = "999999\t"
 		}
-		fmt.Println("E: ok")
-		fmt.Println(PKIMETAL_ENDOFRESULTS)
+		fmt.Println(prefix + "E: ok")
+		fmt.Println(prefix + PKIMETAL_ENDOFRESULTS)
+	}
+	os.Exit(0)
+}
+
+// helperJSONLines is the stub backend's JSON-lines protocol loop.  The DER
+// doubles as a behaviour marker.
+func helperJSONLines() {
+	out := json.NewEncoder(os.Stdout)
+	_ = out.Encode(backendResponse{Version: PROTOCOL_JSONLINES, Ready: true})
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var breq backendRequest
+		if json.Unmarshal(in.Bytes(), &breq) != nil {
+			os.Exit(1)
+		}
+		bresp := backendResponse{Version: PROTOCOL_JSONLINES, Id: breq.Id}
+		switch string(breq.DER) {
+		case "CRASH":
+			os.Exit(1)
+		case "SLOWOK":
+			time.Sleep(150 * time.Millisecond)
+		case "BADID":
+			bresp.Id = 999999
+		case "UNPARSEABLE": // Report an error, and end the request in the same envelope.
+			bresp.Error, bresp.End = "unparseable input", true
+			_ = out.Encode(bresp)
+			continue
+		}
+		bresp.Findings = []backendFinding{{Severity: "error", Code: "stub_ok", Field: breq.Profile, Message: "ok"}}
+		_ = out.Encode(bresp)
+		_ = out.Encode(backendResponse{Version: PROTOCOL_JSONLINES, Id: breq.Id, End: true})
 	}
 	os.Exit(0)
 }
@@ -207,7 +863,9 @@ func (stubBackend) HandleRequest(context.Context, *LinterInstance, *LintingReque
 func (stubBackend) ProcessResult(r LintingResult) LintingResult { return r }
 
 // startStubBackend spawns the stub backend, runs serverLoop against it, and
-// returns a stop function that tears both down.
+// returns a stop function that tears both down.  The "pipelined" extra argument
+// selects pipelining, and the "jsonlines" extra argument selects the JSON-lines
+// protocol.
 func startStubBackend(t *testing.T, readySignal string, extraArgs ...string) (lin *LinterInstance, stop func()) {
 	t.Helper()
 	lin = &LinterInstance{
@@ -216,11 +874,15 @@ func startStubBackend(t *testing.T, readySignal string, extraArgs ...string) (li
 			Version:               "v0",
 			ReqChannel:            make(chan LintingRequest, 8),
 			ReadySignal:           readySignal,
-			queueTimeSummary:      prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_queue"}),
+			Pipelined:             slices.Contains(extraArgs, "pipelined"),
+			queueTimeSummaries:    [NUM_PRIORITIES]prometheus.Summary{prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_queue"}), prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_bulk_queue"})},
 			processingTimeSummary: prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_processing"}),
 		},
 		Mutex: &sync.Mutex{},
 	}
+	if slices.Contains(extraArgs, "jsonlines") {
+		lin.Protocol = PROTOCOL_JSONLINES
+	}
 	args := append([]string{"-test.run=TestHelperProcess", helperArg}, extraArgs...)
 	lin.startInstance_external(".", os.Args[0], args...)
 
@@ -253,7 +915,7 @@ func startExternalStub(t *testing.T, readySignal string, extraArgs ...string) (l
 			Version:               "v0",
 			ReqChannel:            make(chan LintingRequest, 8),
 			ReadySignal:           readySignal,
-			queueTimeSummary:      prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_queue"}),
+			queueTimeSummaries:    [NUM_PRIORITIES]prometheus.Summary{prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_queue"}), prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_bulk_queue"})},
 			processingTimeSummary: prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_processing"}),
 		},
 		Mutex: &sync.Mutex{},
@@ -281,11 +943,12 @@ func startExternalStub(t *testing.T, readySignal string, extraArgs ...string) (l
 // sentinel arrives or the request context is done.
 func runLint(lin *LinterInstance, ctx context.Context, input string) []LintingResult {
 	lreq := LintingRequest{
-		Ctx:         ctx,
-		B64Input:    input,
-		ProfileId:   0,
-		QueuedAt:    time.Now(),
-		RespChannel: make(chan LintingResult),
+		Ctx:          ctx,
+		B64Input:     input,
+		DecodedInput: []byte(input),
+		ProfileId:    0,
+		QueuedAt:     time.Now(),
+		RespChannel:  make(chan LintingResult),
 	}
 	lin.ReqChannel <- lreq
 
@@ -470,3 +1133,375 @@ func TestBackend_InitialisationIsSerialised(t *testing.T) {
 		t.Errorf("initialisation does not appear serialised: both backends ready in %v (expected >= ~800ms)", elapsed)
 	}
 }
+
+func TestAdmit(t *testing.T) {
+	saved := config.Config.Linter.MaxQueueWait
+	t.Cleanup(func() { config.Config.Linter.MaxQueueWait = saved })
+	config.Config.Linter.MaxQueueWait = 5 * time.Second
+
+	l := &Linter{Name: "stub", NumInstances: 2, ReqChannel: make(chan LintingRequest, 4)}
+	l.recordRuntime(2 * time.Second)
+	l.recordRuntime(10 * time.Second)
+	if got := time.Duration(l.recentRuntime.Load()); got != 3*time.Second {
+		t.Fatalf("got moving average %v, want 3s", got)
+	}
+
+	// An empty queue admits requests.
+	if _, ok := l.Admit(PRIORITY_INTERACTIVE, 1); !ok {
+		t.Error("expected an empty queue to admit the request")
+	}
+
+	// 3 queued requests, each taking 3s, across 2 instances: an estimated wait of 4.5s.
+	for range 3 {
+		l.ReqChannel <- LintingRequest{}
+	}
+	if wait := l.EstimatedWait(PRIORITY_INTERACTIVE); wait != 4500*time.Millisecond {
+		t.Errorf("got estimated wait %v, want 4.5s", wait)
+	} else if _, ok := l.Admit(PRIORITY_INTERACTIVE, 1); !ok {
+		t.Error("expected a 4.5s estimated wait to be admitted")
+	} else if retryAfter, ok := l.Admit(PRIORITY_INTERACTIVE, 3); ok || retryAfter != 2500*time.Millisecond {
+		t.Errorf("got %v, %v; want 3 requests (an estimated wait of 7.5s for the last) to be rejected", retryAfter, ok)
+	}
+
+	// 4 queued requests: an estimated wait of 6s, which exceeds the maximum (and fills the queue).
+	l.ReqChannel <- LintingRequest{}
+	if retryAfter, ok := l.Admit(PRIORITY_INTERACTIVE, 1); ok {
+		t.Error("expected a full queue to reject the request")
+	} else if retryAfter != time.Second {
+		t.Errorf("got retry after %v, want 1s", retryAfter)
+	}
+
+	// A long estimated wait is rejected even if the queue is not full.
+	l = &Linter{Name: "stub", NumInstances: 1, ReqChannel: make(chan LintingRequest, 4)}
+	l.recordRuntime(4 * time.Second)
+	l.ReqChannel <- LintingRequest{}
+	l.ReqChannel <- LintingRequest{}
+	if retryAfter, ok := l.Admit(PRIORITY_INTERACTIVE, 1); ok {
+		t.Error("expected an 8s estimated wait to be rejected")
+	} else if retryAfter != 3*time.Second {
+		t.Errorf("got retry after %v, want 3s", retryAfter)
+	}
+}
+
+func TestAdmit_Bulk(t *testing.T) {
+	saved := config.Config.Linter.MaxQueueWait
+	t.Cleanup(func() { config.Config.Linter.MaxQueueWait = saved })
+	config.Config.Linter.MaxQueueWait = 5 * time.Second
+
+	l := &Linter{Name: "stub", NumInstances: 1, ReqChannel: make(chan LintingRequest, 4), BulkReqChannel: make(chan LintingRequest, 4)}
+	l.recordRuntime(2 * time.Second)
+	l.ReqChannel <- LintingRequest{}
+	l.BulkReqChannel <- LintingRequest{}
+	l.BulkReqChannel <- LintingRequest{}
+
+	// Interactive requests only wait behind the 1 queued interactive request; bulk requests wait behind all 3.
+	if wait := l.EstimatedWait(PRIORITY_INTERACTIVE); wait != 2*time.Second {
+		t.Errorf("got interactive estimated wait %v, want 2s", wait)
+	} else if wait = l.EstimatedWait(PRIORITY_BULK); wait != 6*time.Second {
+		t.Errorf("got bulk estimated wait %v, want 6s", wait)
+	} else if _, ok := l.Admit(PRIORITY_INTERACTIVE, 1); !ok {
+		t.Error("expected an interactive request to be admitted")
+	} else if _, ok = l.Admit(PRIORITY_BULK, 1); ok {
+		t.Error("expected a bulk request to be rejected")
+	}
+
+	// Without a bulk queue, bulk requests share the interactive queue.
+	l.BulkReqChannel = nil
+	if l.Queue(PRIORITY_BULK) != l.ReqChannel || l.EstimatedWait(PRIORITY_BULK) != 2*time.Second {
+		t.Error("expected bulk requests to share the interactive queue")
+	}
+}
+
+func TestNextRequest(t *testing.T) {
+	saved := config.Config.Priority.InteractiveWeight
+	t.Cleanup(func() { config.Config.Priority.InteractiveWeight = saved })
+	config.Config.Priority.InteractiveWeight = 2
+
+	lin := &LinterInstance{Linter: &Linter{Name: "stub", ReqChannel: make(chan LintingRequest, 8), BulkReqChannel: make(chan LintingRequest, 8)}}
+	for range 5 {
+		lin.ReqChannel <- LintingRequest{Priority: PRIORITY_INTERACTIVE}
+	}
+	for range 3 {
+		lin.BulkReqChannel <- LintingRequest{Priority: PRIORITY_BULK}
+	}
+
+	// Two interactive requests are processed for each bulk request, until the interactive queue is empty.
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	for range 8 {
+		lreq, ok := lin.nextRequest(ctx)
+		if !ok {
+			t.Fatal("expected a request")
+		}
+		got = append(got, PriorityString[lreq.Priority][:1])
+	}
+	if order := strings.Join(got, ""); order != "iibiibib" {
+		t.Errorf("got order %s, want iibiibib", order)
+	}
+
+	// Shutdown is honoured even whilst requests are waiting.
+	lin.ReqChannel <- LintingRequest{}
+	cancel()
+	if _, ok := lin.nextRequest(ctx); ok {
+		t.Error("expected shutdown to be honoured")
+	}
+}
+
+// helperBackend runs TestHelperProcess as an external backend.
+type helperBackend struct{ stubBackend }
+
+func (helperBackend) StartInstance() (bool, string, string, []string) {
+	return false, ".", os.Args[0], []string{"-test.run=TestHelperProcess", helperArg}
+}
+
+func TestScale(t *testing.T) {
+	saved := config.Config.Linter.Scaling
+	t.Cleanup(func() { config.Config.Linter.Scaling = saved })
+	config.Config.Linter.Scaling.ScaleUpWait = time.Second
+	config.Config.Linter.Scaling.CoolDown = time.Minute
+
+	l := &Linter{
+		Name:                  "stub",
+		Version:               "v0",
+		NumInstances:          1,
+		minInstances:          1,
+		maxInstances:          3,
+		ReqChannel:            make(chan LintingRequest, 8),
+		BulkReqChannel:        make(chan LintingRequest, 8),
+		queueTimeSummaries:    [NUM_PRIORITIES]prometheus.Summary{prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_queue"}), prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_bulk_queue"})},
+		processingTimeSummary: prometheus.NewSummary(prometheus.SummaryOpts{Name: "stub_processing"}),
+		Interface:             func() LinterInterface { return helperBackend{} },
+	}
+	l.runningInstances.Store(1) // The preconfigured instance.
+	waitForInstances := func(want int64) {
+		t.Helper()
+		for deadline := time.Now().Add(10 * time.Second); l.runningInstances.Load() != want; time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("got %d running instances, want %d", l.runningInstances.Load(), want)
+			}
+		}
+	}
+
+	// 2 queued requests, each taking 1s: an estimated wait of 2s, which exceeds the threshold.  The requests have
+	// already expired, so the added instance will skip them.
+	expired, cancelExpired := context.WithCancel(context.Background())
+	cancelExpired()
+	l.recordRuntime(time.Second)
+	l.ReqChannel <- LintingRequest{Ctx: expired}
+	l.ReqChannel <- LintingRequest{Ctx: expired}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Now()
+	l.scale(ctx, now)
+	if len(l.extraInstances) != 1 {
+		t.Fatalf("got %d extra instances, want 1", len(l.extraInstances))
+	}
+	waitForInstances(2)
+	if !l.external {
+		t.Error("expected the added instance to run an external backend")
+	}
+
+	// The maximum number of instances is never exceeded.
+	for range 6 {
+		l.ReqChannel <- LintingRequest{Ctx: expired}
+	}
+	l.scale(ctx, now)
+	waitForInstances(3)
+	l.scale(ctx, now)
+	if len(l.extraInstances) != 2 {
+		t.Errorf("got %d extra instances, want 2", len(l.extraInstances))
+	}
+	for len(l.ReqChannel) > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Only an instance that has been idle for the whole cool-down period is retired, even if the other was added first.
+	first, second := l.extraInstances[0], l.extraInstances[1]
+	first.lastBusyAt.Store(now.Add(time.Minute).UnixNano())
+	second.lastBusyAt.Store(now.UnixNano())
+	l.scale(ctx, now.Add(30*time.Second))
+	if len(l.extraInstances) != 2 {
+		t.Errorf("got %d extra instances, want 2 during the cool-down", len(l.extraInstances))
+	}
+	l.scale(ctx, now.Add(90*time.Second))
+	if len(l.extraInstances) != 1 || l.extraInstances[0] != first {
+		t.Errorf("got extra instances %v, want only the busy instance", l.extraInstances)
+	}
+	waitForInstances(2)
+
+	// Instances are retired down to the minimum.
+	l.scale(ctx, now.Add(5*time.Minute))
+	l.scale(ctx, now.Add(5*time.Minute))
+	if len(l.extraInstances) != 0 {
+		t.Errorf("got %d extra instances, want 0 after the cool-down", len(l.extraInstances))
+	}
+	waitForInstances(1)
+	if l.RunningInstances() != 1 {
+		t.Errorf("got %d running instances, want 1", l.RunningInstances())
+	}
+}
+
+func TestStart_SpareInstance(t *testing.T) {
+	l := &Linter{Name: "stub", ReqChannel: make(chan LintingRequest), Interface: func() LinterInterface { return helperBackend{} }}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The spare instance is started first, so it would be the first to finish initialising if it were started.
+	spare := &LinterInstance{Linter: l, Mutex: &sync.Mutex{}, spare: true}
+	spare.start(ctx)
+	(&LinterInstance{Linter: l, Mutex: &sync.Mutex{}}).start(ctx)
+	for deadline := time.Now().Add(10 * time.Second); l.RunningInstances() == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected an instance to start")
+		}
+	}
+	if spare.command != nil || l.RunningInstances() != 1 {
+		t.Error("expected the spare external instance not to be started")
+	}
+}
+
+func TestPipelinedBackend_ConcurrentRequests(t *testing.T) {
+	lin, stop := startStubBackend(t, "", "pipelined")
+	defer stop()
+	pid := backendPID(lin)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Each request's results are matched back to it, whilst several are in flight.
+	var wg sync.WaitGroup
+	results := make([][]LintingResult, 6)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runLint(lin, ctx, "SLOWOK")
+		}()
+	}
+	wg.Wait()
+	for i, r := range results {
+		if !hasResult(r, SEVERITY_ERROR, "ok") {
+			t.Errorf("request %d: expected an 'ok' result, got %+v", i, r)
+		}
+	}
+	if backendPID(lin) != pid {
+		t.Error("backend should not have restarted on clean requests")
+	}
+}
+
+func TestPipelinedBackend_RestartsOnCrash(t *testing.T) {
+	for _, input := range []string{"CRASH", "BADID"} {
+		t.Run(input, func(t *testing.T) {
+			lin, stop := startStubBackend(t, "", "pipelined")
+			defer stop()
+			pid := backendPID(lin)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			results := runLint(lin, ctx, input)
+			if !hasResult(results, SEVERITY_FATAL, "stub") {
+				t.Errorf("expected a FATAL result, got %+v", results)
+			}
+
+			// The restarted backend must serve the next request.
+			if r := runLint(lin, ctx, "hello"); !hasResult(r, SEVERITY_ERROR, "ok") {
+				t.Errorf("restarted backend did not serve the next request: %+v", r)
+			}
+			if backendPID(lin) == pid {
+				t.Error("backend should have restarted")
+			}
+		})
+	}
+}
+
+func TestPipelinedBackend_FailsBeforeWarmUp(t *testing.T) {
+	lin, stop := startStubBackend(t, PKIMETAL_READY, "pipelined", "warmup")
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The request in flight is failed without waiting for the restarted backend to warm up.
+	if r := runLint(lin, ctx, "hello"); !hasResult(r, SEVERITY_ERROR, "ok") {
+		t.Fatalf("expected an 'ok' result, got %+v", r)
+	}
+	start := time.Now()
+	if results := runLint(lin, ctx, "CRASH"); !hasResult(results, SEVERITY_FATAL, "stub") {
+		t.Errorf("expected a FATAL result, got %+v", results)
+	} else if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Errorf("got the failure after %v, want it before the warm-up", elapsed)
+	}
+	if r := runLint(lin, ctx, "hello"); !hasResult(r, SEVERITY_ERROR, "ok") {
+		t.Errorf("restarted backend did not serve the next request: %+v", r)
+	}
+}
+
+func TestPipelinedBackend_RestartsOnBackendTimeout(t *testing.T) {
+	saved := config.Config.Linter.BackendTimeout
+	config.Config.Linter.BackendTimeout = 200 * time.Millisecond
+	defer func() { config.Config.Linter.BackendTimeout = saved }()
+
+	lin, stop := startStubBackend(t, "", "pipelined")
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The request that is pipelined behind the hung request is failed too, because the backend is restarted.
+	var hung, behind []LintingResult
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		hung = runLint(lin, ctx, "SLEEP")
+	}()
+	time.Sleep(50 * time.Millisecond)
+	behind = runLint(lin, ctx, "hello")
+	wg.Wait()
+	if !hasResult(hung, SEVERITY_FATAL, "timed out") || !hasResult(behind, SEVERITY_FATAL, "timed out") {
+		t.Errorf("expected backend-timeout FATALs, got %+v and %+v", hung, behind)
+	}
+}
+
+func TestJSONLinesBackend(t *testing.T) {
+	for _, mode := range [][]string{{"jsonlines"}, {"jsonlines", "pipelined"}} {
+		t.Run(strings.Join(mode, "+"), func(t *testing.T) {
+			lin, stop := startStubBackend(t, "", mode...)
+			defer stop()
+			pid := backendPID(lin)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// Structured findings are delivered, with their code and field.
+			results := runLint(lin, ctx, "hello")
+			if !hasResult(results, SEVERITY_ERROR, "ok") || results[0].Code != "stub_ok" || results[0].Field != AUTODETECT.String() {
+				t.Errorf("expected an 'ok' result, got %+v", results)
+			}
+
+			// A backend error is a fatal finding, but the backend is healthy.
+			if r := runLint(lin, ctx, "UNPARSEABLE"); !hasResult(r, SEVERITY_FATAL, "unparseable input") {
+				t.Errorf("expected a FATAL result, got %+v", r)
+			}
+			if backendPID(lin) != pid {
+				t.Error("backend should not have restarted on a backend error")
+			}
+
+			// A crash or desync fails the request and restarts the backend, which must serve the next request.
+			for _, input := range []string{"CRASH", "BADID"} {
+				pid = backendPID(lin)
+				if r := runLint(lin, ctx, input); !hasResult(r, SEVERITY_FATAL, "stub") {
+					t.Errorf("%s: expected a FATAL result, got %+v", input, r)
+				}
+				if r := runLint(lin, ctx, "hello"); !hasResult(r, SEVERITY_ERROR, "ok") {
+					t.Errorf("%s: restarted backend did not serve the next request: %+v", input, r)
+				}
+				if backendPID(lin) == pid {
+					t.Errorf("%s: backend should have restarted", input)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package linter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+)
+
+// A pipelined external backend is sent a request ID with each request, and includes it in each of its responses
+// (under the legacy protocol, by prefixing the header line and each response token, including the end-of-results
+// sentinel, with the request ID and a tab).  This lets several requests be in flight at once, so that the backend can
+// read the next request as soon as it has finished the previous one, rather than waiting for a round trip.
+
+// pipelinedRequest is a linting request that has been sent to a pipelined external backend.
+type pipelinedRequest struct {
+	lreq       LintingRequest
+	queuedFor  time.Duration
+	sentAt     time.Time
+	clientGone bool // Only accessed by the reader.
+}
+
+// pipeline tracks the requests that are in flight to a pipelined external backend.  Lock ordering: lin.Mutex (which
+// serialises writes and restarts) before mutex.
+type pipeline struct {
+	lin        *LinterInstance
+	lif        LinterInterface
+	mutex      sync.Mutex
+	pending    map[uint64]*pipelinedRequest
+	nextId     uint64
+	lastDoneAt time.Time     // When the backend last finished a request.
+	slots      chan struct{} // Limits the number of requests in flight.
+}
+
+// servePipelined sends requests to a pipelined external backend without waiting for earlier requests to finish, up to
+// the configured pipeline depth, whilst a reader goroutine matches the backend's responses to their requests.  Once
+// shutdown is requested, the requests in flight are allowed to finish.
+func (lin *LinterInstance) servePipelined(ctx context.Context, lif LinterInterface) {
+	p := &pipeline{
+		lin:     lin,
+		lif:     lif,
+		pending: make(map[uint64]*pipelinedRequest),
+		slots:   make(chan struct{}, max(config.Config.Linter.PipelineDepth, 1)),
+	}
+	go p.readLoop(ctx, lin.Stdout)
+
+	for {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			p.drain()
+			return
+		}
+
+		// Multiple backends share the same request channels, but only one backend will receive each request.
+		lreq, ok := lin.nextRequest(ctx)
+		if !ok {
+			<-p.slots
+			p.drain()
+			return // Respond to graceful shutdown requests.
+		}
+		lin.lastBusyAt.Store(time.Now().UnixNano())
+		if lreq.Ctx.Err() != nil {
+			<-p.slots // Skip requests whose deadline has already passed whilst queued.
+			continue
+		}
+		p.send(lreq)
+	}
+}
+
+// drain waits for the requests in flight to finish (or fail).
+func (p *pipeline) drain() {
+	for range cap(p.slots) {
+		p.slots <- struct{}{}
+	}
+}
+
+// send writes the request, with its request ID, to the backend's STDIN.
+func (p *pipeline) send(lreq LintingRequest) {
+	lin := p.lin
+	lin.Mutex.Lock()
+	defer lin.Mutex.Unlock()
+
+	now := time.Now()
+	pr := &pipelinedRequest{
+		lreq:      lreq,
+		queuedFor: now.Sub(lreq.QueuedAt),
+		sentAt:    now,
+	}
+	p.mutex.Lock()
+	id := p.nextId
+	p.nextId++
+	p.pending[id] = pr
+	p.setReadDeadline()
+	p.mutex.Unlock()
+
+	// A failed write is not retried: the backend is broken, so the reader will fail every request in flight (including
+	// this one) and restart it.
+	if lin.stdinFile != nil {
+		_ = lin.stdinFile.SetWriteDeadline(now.Add(config.Config.Linter.BackendTimeout))
+	}
+	if request, err := lin.encodeRequest(id, &lreq); err == nil {
+		_, _ = lin.Stdin.Write(request)
+	}
+	if lin.stdinFile != nil {
+		_ = lin.stdinFile.SetWriteDeadline(time.Time{})
+	}
+}
+
+// setReadDeadline bounds the next read from the backend's STDOUT by the backend timeout of the oldest request in
+// flight (if any), which is the request that the backend is processing.  The timeout is measured from when the
+// backend began processing it, so that time spent waiting behind earlier requests does not count against it.  The
+// caller must hold p.mutex.
+func (p *pipeline) setReadDeadline() {
+	if p.lin.stdoutFile == nil {
+		return
+	}
+	var began, deadline time.Time
+	for _, pr := range p.pending {
+		if began.IsZero() || pr.sentAt.Before(began) {
+			began = pr.sentAt
+		}
+	}
+	if !began.IsZero() {
+		if p.lastDoneAt.After(began) {
+			began = p.lastDoneAt
+		}
+		deadline = began.Add(config.Config.Linter.BackendTimeout)
+	}
+	_ = p.lin.stdoutFile.SetReadDeadline(deadline)
+}
+
+// readLoop delivers the results that the backend writes to STDOUT to the requests that they belong to, until the
+// backend crashes, desyncs, or exceeds the backend timeout of a request in flight; then it fails every request in
+// flight and, unless shutdown has been requested, restarts the backend and continues reading from the new backend.
+func (p *pipeline) readLoop(ctx context.Context, stdout *bufio.Scanner) {
+	lin := p.lin
+	for {
+		err := p.read(stdout)
+
+		// Hold lin.Mutex until the backend has been restarted, so that no request is written to the failed backend.
+		lin.Mutex.Lock()
+		p.mutex.Lock()
+		pending := p.pending
+		p.pending = make(map[uint64]*pipelinedRequest)
+		p.mutex.Unlock()
+
+		// Fail the requests in flight before restarting the backend, since the restart (which waits for the new backend
+		// to warm up) is not bounded by their deadlines.
+		now := time.Now()
+		for _, pr := range pending {
+			if !pr.clientGone {
+				lin.sendBackendFailure(&pr.lreq, err)
+			}
+			lin.finishRequest(&pr.lreq, pr.queuedFor, now.Sub(pr.sentAt), true)
+			<-p.slots
+		}
+
+		if ctx.Err() != nil {
+			lin.Mutex.Unlock()
+			return
+		}
+		lin.restartInstance_external(err)
+		stdout = lin.Stdout
+		p.mutex.Lock()
+		p.setReadDeadline()
+		p.mutex.Unlock()
+		lin.Mutex.Unlock()
+	}
+}
+
+// read delivers results until the backend fails, returning the error.
+func (p *pipeline) read(stdout *bufio.Scanner) error {
+	lin := p.lin
+	for stdout.Scan() {
+		// Parse the response into linting result(s), and match it to its request.
+		id, results, end, err := lin.parseResponse(stdout.Text())
+		if err != nil {
+			return err
+		}
+		p.mutex.Lock()
+		pr := p.pending[id]
+		p.mutex.Unlock()
+		if pr == nil {
+			return fmt.Errorf("unknown request ID: %d", id)
+		}
+
+		for _, lresult := range results {
+			// Deliver results whilst the client is still waiting.  Once it has given up, keep reading the backend so
+			// that the backend stays in sync and warm, but stop delivering.
+			if pr.clientGone || !pr.lreq.isResultSelected(lresult) {
+				continue
+			}
+			if !lin.sendResult(&pr.lreq, p.lif.ProcessResult(lresult)) {
+				pr.clientGone = true
+			}
+		}
+		if end {
+			p.finish(id, pr)
+		}
+	}
+
+	if err := stdout.Err(); err != nil {
+		return err
+	}
+	return errors.New("stdout.Scan() => false")
+}
+
+// finish completes a request whose results have all been delivered.  The backend processes one request at a time, so
+// the request's processing began when it was sent or when the backend finished the previous request, whichever was
+// later; until then, it was queued.
+func (p *pipeline) finish(id uint64, pr *pipelinedRequest) {
+	now := time.Now()
+	p.mutex.Lock()
+	began := pr.sentAt
+	if p.lastDoneAt.After(began) {
+		began = p.lastDoneAt
+	}
+	p.lastDoneAt = now
+	delete(p.pending, id)
+	p.setReadDeadline()
+	p.mutex.Unlock()
+
+	p.lin.finishRequest(&pr.lreq, pr.queuedFor+began.Sub(pr.sentAt), now.Sub(began), false)
+	<-p.slots
+}
@@ -29,13 +29,15 @@ func init() {
 
 	// Register pkilint.
 	(&linter.Linter{
-		Name:         "pkilint",
-		Version:      Version,
-		Url:          "https://github.com/digicert/pkilint",
-		Unsupported:  nil,
-		NumInstances: config.Config.Linter.Pkilint.NumProcesses,
-		ReadySignal:  linter.PKIMETAL_READY,
-		Interface:    func() linter.LinterInterface { return &Pkilint{} },
+		Name:           "pkilint",
+		Version:        Version,
+		Url:            "https://github.com/digicert/pkilint",
+		Unsupported:    linter.CsrOrPublicKeyProfileIDs,
+		NumInstances:   config.Config.Linter.Pkilint.NumProcesses,
+		ForwardsChecks: true,
+		Pipelined:      true,
+		Protocol:       linter.PROTOCOL_JSONLINES,
+		Interface:      func() linter.LinterInterface { return &Pkilint{} },
 	}).Register()
 }
 
@@ -45,6 +47,8 @@ func (l *Pkilint) StartInstance() (useHandleRequest bool, directory, cmd string,
 	return false, config.Config.Linter.Pkilint.PythonDir, "python3",
 		[]string{"-c", `#!/usr/bin/python3
 import base64
+import json
+from fnmatch import fnmatchcase
 from sys import stdin
 from pkilint import etsi, finding_filter, loader, pkix, validation
 from pkilint.cabf import cabf_crl, serverauth, smime
@@ -55,9 +59,36 @@ from pkilint.pkix import certificate, crl, extension, name, ocsp
 from pkilint.report import ReportGeneratorJson
 
 
+# Check selection:
+check_include = []
+check_exclude = []
+
+def is_check_selected(code):
+	if check_include and not any(fnmatchcase(code, p) for p in check_include):
+		return False
+	return not any(fnmatchcase(code, p) for p in check_exclude)
+
+def select_checks(results):
+	if not check_include and not check_exclude:
+		return results
+	selected = []
+	for r in results:
+		fds = [fd for fd in r.finding_descriptions if is_check_selected(fd.finding.code)]
+		if fds:
+			selected.append(validation.ValidationResult(r.validator, r.node, fds))
+	return selected
+
+def report(results):
+	findings = []
+	for r in json.loads(ReportGeneratorJson(select_checks(results), validation.ValidationFindingSeverity.DEBUG).generate())["results"]:
+		for fd in r["finding_descriptions"]:
+			findings.append({"severity": fd["severity"].lower(), "code": fd["code"], "field": r["node_path"], "message": fd.get("message") or ""})
+	return findings
+
+
 # lint_cabf_smime_cert:
 ` + smime_profile_dictionary + `
-sbr_profile_ids = {` + linter.ProfileIDList(linter.SbrLeafProfileIDs) + `}
+sbr_profile_ids = {` + linter.ProfileNameList(linter.SbrLeafProfileIDs) + `}
 smime_decoding_validators = smime.create_decoding_validators()
 smime_doc_validators = {}
 
@@ -68,20 +99,17 @@ def init_smime_validators():
 			smime_doc_validators[v][g] = certificate.create_pkix_certificate_validator_container(smime_decoding_validators, smime.create_subscriber_validators(v, g))
 
 def lint_cabf_smime_cert(profile_id, pem_data):
-	try:
-		cert = loader.load_pem_certificate(pem_data, "")
-		v_g = smime_profile_dictionary.get(profile_id, smime.determine_validation_level_and_generation(cert))
-		if v_g is None:
-			return "E: Could not determine validation level and generation"
-		validation_level, generation = v_g
-		return ReportGeneratorJson(smime_doc_validators[validation_level][generation].validate(cert.root), validation.ValidationFindingSeverity.DEBUG).generate()
-	except Exception as e:
-		return "F: Exception: " + str(e)
+	cert = loader.load_pem_certificate(pem_data, "")
+	v_g = smime_profile_dictionary.get(profile_id, smime.determine_validation_level_and_generation(cert))
+	if v_g is None:
+		return [{"severity": "error", "message": "Could not determine validation level and generation"}]
+	validation_level, generation = v_g
+	return report(smime_doc_validators[validation_level][generation].validate(cert.root))
 
 
 # lint_cabf_serverauth_cert:
 ` + serverauth_profile_dictionary + `
-tbr_tevg_profile_ids = {` + linter.ProfileIDList(linter.TbrTevgCertificateProfileIDs) + `}
+tbr_tevg_profile_ids = {` + linter.ProfileNameList(linter.TbrTevgCertificateProfileIDs) + `}
 serverauth_decoding_validators = serverauth.create_decoding_validators()
 serverauth_doc_validators = {}
 serverauth_finding_filters = {}
@@ -92,19 +120,16 @@ def init_serverauth_validators_and_filters():
 		serverauth_finding_filters[ct] = serverauth.create_serverauth_finding_filters(ct)
 
 def lint_cabf_serverauth_cert(profile_id, pem_data):
-	try:
-		cert = loader.load_pem_certificate(pem_data, "")
-		certificate_type = serverauth_profile_dictionary.get(profile_id, serverauth.determine_certificate_type(cert))
-		results, _ = finding_filter.filter_results(serverauth_finding_filters[certificate_type], serverauth_doc_validators[certificate_type].validate(cert.root))
-		return ReportGeneratorJson(results, validation.ValidationFindingSeverity.DEBUG).generate()
-	except Exception as e:
-		return "F: Exception: " + str(e)
+	cert = loader.load_pem_certificate(pem_data, "")
+	certificate_type = serverauth_profile_dictionary.get(profile_id, serverauth.determine_certificate_type(cert))
+	results, _ = finding_filter.filter_results(serverauth_finding_filters[certificate_type], serverauth_doc_validators[certificate_type].validate(cert.root))
+	return report(results)
 
 
 # lint_etsi_cert:
 ` + etsi_profile_dictionary + `
-etsi_profile_ids = {` + linter.ProfileIDList(linter.EtsiCertificateProfileIDs) + `}
-etsi_non_browser_profile_ids = {` + linter.ProfileIDList(linter.EtsiNonBrowserCertificateProfileIDs) + `}
+etsi_profile_ids = {` + linter.ProfileNameList(linter.EtsiCertificateProfileIDs) + `}
+etsi_non_browser_profile_ids = {` + linter.ProfileNameList(linter.EtsiNonBrowserCertificateProfileIDs) + `}
 etsi_doc_validators = {}
 etsi_finding_filters = {}
 
@@ -114,15 +139,12 @@ def init_etsi_validators_and_filters():
 		etsi_finding_filters[ct] = etsi.create_etsi_finding_filters(ct)
 
 def lint_etsi_cert(profile_id, report_all, pem_data):
-	try:
-		cert = loader.load_pem_certificate(pem_data, "")
-		certificate_type = etsi_profile_dictionary.get(profile_id, etsi.determine_certificate_type(cert))
-		results = etsi_doc_validators[certificate_type].validate(cert.root)
-		if not report_all:
-			results, _ = finding_filter.filter_results(etsi_finding_filters[certificate_type], results)
-		return ReportGeneratorJson(results, validation.ValidationFindingSeverity.DEBUG).generate()
-	except Exception as e:
-		return "F: Exception: " + str(e)
+	cert = loader.load_pem_certificate(pem_data, "")
+	certificate_type = etsi_profile_dictionary.get(profile_id, etsi.determine_certificate_type(cert))
+	results = etsi_doc_validators[certificate_type].validate(cert.root)
+	if not report_all:
+		results, _ = finding_filter.filter_results(etsi_finding_filters[certificate_type], results)
+	return report(results)
 
 
 # lint_pkix_cert:
@@ -137,84 +159,86 @@ pkix_doc_validator = certificate.create_pkix_certificate_validator_container(
 )
 
 def lint_pkix_cert(pem_data):
-	try:
-		cert = loader.load_pem_certificate(pem_data, "")
-		return ReportGeneratorJson(pkix_doc_validator.validate(cert.root), validation.ValidationFindingSeverity.DEBUG).generate()
-	except Exception as e:
-		return "F: Exception: " + str(e)
+	cert = loader.load_pem_certificate(pem_data, "")
+	return report(pkix_doc_validator.validate(cert.root))
 
 
 # lint_crl:
-crl_profile_ids = {` + linter.ProfileIDList(linter.CrlProfileIDs) + `}
+crl_profile_ids = {` + linter.ProfileNameList(linter.CrlProfileIDs) + `}
 crl_doc_validators = {}
-crl_doc_validators[` + fmt.Sprintf("%d", linter.RFC5280_CRL) + `] = crl.create_pkix_crl_validator_container(
+crl_doc_validators[` + fmt.Sprintf("%q", linter.RFC5280_CRL) + `] = crl.create_pkix_crl_validator_container(
 	[pkix.create_attribute_decoder(name.ATTRIBUTE_TYPE_MAPPINGS), pkix.create_extension_decoder(extension.EXTENSION_MAPPINGS)],
 	[crl.create_issuer_validator_container([]), crl.create_validity_validator_container([]), crl.create_extensions_validator_container([])]
 )
-crl_doc_validators[` + fmt.Sprintf("%d", linter.TBR_CRL) + `] = crl.create_pkix_crl_validator_container(
+crl_doc_validators[` + fmt.Sprintf("%q", linter.TBR_CRL) + `] = crl.create_pkix_crl_validator_container(
 	[pkix.create_attribute_decoder(name.ATTRIBUTE_TYPE_MAPPINGS), pkix.create_extension_decoder(extension.EXTENSION_MAPPINGS)],
 	[crl.create_issuer_validator_container([]), crl.create_validity_validator_container([cabf_crl.create_validity_period_validator(crl.CertificateRevocationListType.CRL)]), crl.create_extensions_validator_container([]), cabf_crl.CabfCrlReasonCodeAllowlistValidator(crl.CertificateRevocationListType.CRL)]
 )
-crl_doc_validators[` + fmt.Sprintf("%d", linter.RFC5280_ARL) + `] = crl.create_pkix_crl_validator_container(
+crl_doc_validators[` + fmt.Sprintf("%q", linter.RFC5280_ARL) + `] = crl.create_pkix_crl_validator_container(
 	[pkix.create_attribute_decoder(name.ATTRIBUTE_TYPE_MAPPINGS), pkix.create_extension_decoder(extension.EXTENSION_MAPPINGS)],
 	[crl.create_issuer_validator_container([]), crl.create_validity_validator_container([]), crl.create_extensions_validator_container([])]
 )
-crl_doc_validators[` + fmt.Sprintf("%d", linter.TBR_ARL) + `] = crl.create_pkix_crl_validator_container(
+crl_doc_validators[` + fmt.Sprintf("%q", linter.TBR_ARL) + `] = crl.create_pkix_crl_validator_container(
 	[pkix.create_attribute_decoder(name.ATTRIBUTE_TYPE_MAPPINGS), pkix.create_extension_decoder(extension.EXTENSION_MAPPINGS)],
 	[crl.create_issuer_validator_container([]), crl.create_validity_validator_container([cabf_crl.create_validity_period_validator(crl.CertificateRevocationListType.ARL)]), crl.create_extensions_validator_container([]), cabf_crl.CabfCrlReasonCodeAllowlistValidator(crl.CertificateRevocationListType.ARL)]
 )
 
 def lint_crl(pem_data, crl_profile_id):
-	try:
-		crl_or_arl = loader.load_pem_crl(pem_data, "")
-		return ReportGeneratorJson(crl_doc_validators[crl_profile_id].validate(crl_or_arl.root), validation.ValidationFindingSeverity.DEBUG).generate()
-	except Exception as e:
-		return "F: Exception: " + str(e)
+	crl_or_arl = loader.load_pem_crl(pem_data, "")
+	return report(crl_doc_validators[crl_profile_id].validate(crl_or_arl.root))
 
 
 # lint_ocsp_response:
-ocspresponse_profile_ids = {` + linter.ProfileIDList(linter.OcspProfileIDs) + `}
+ocspresponse_profile_ids = {` + linter.ProfileNameList(linter.OcspProfileIDs) + `}
 ocsp_doc_validator = ocsp.create_pkix_ocsp_response_validator_container(
 	[ocsp.create_response_decoder(), pkix.create_attribute_decoder(name.ATTRIBUTE_TYPE_MAPPINGS), pkix.create_extension_decoder(extension.EXTENSION_MAPPINGS)], []
 )
 
 def lint_ocsp_response(pem_data):
-	try:
-		ocsp_response = loader.load_ocsp_response(pem_data, "")
-		return ReportGeneratorJson(ocsp_doc_validator.validate(ocsp_response.root), validation.ValidationFindingSeverity.DEBUG).generate()
-	except Exception as e:
-		return "F: Exception: " + str(e)
-
+	ocsp_response = loader.load_ocsp_response(pem_data, "")
+	return report(ocsp_doc_validator.validate(ocsp_response.root))
+
+
+# JSON-lines protocol:
+protocol_version = ` + fmt.Sprint(linter.PROTOCOL_JSONLINES) + `
+pem_labels = {"certificate": "CERTIFICATE", "crl": "X509 CRL", "ocspresponse": "OCSP RESPONSE"}
+
+def lint(request):
+	global check_include, check_exclude
+	profile_id = request["profile"]
+	check_include = request["options"].get("include", [])
+	check_exclude = request["options"].get("exclude", [])
+	pem_label = pem_labels[request["type"]]
+	b64_data = request["der"]
+	pem_data = "-----BEGIN %s-----\n%s\n-----END %s-----\n" % (pem_label, "\n".join(b64_data[i:i + 64] for i in range(0, len(b64_data), 64)), pem_label)
+	if profile_id in etsi_profile_ids:
+		return lint_etsi_cert(profile_id, profile_id not in etsi_non_browser_profile_ids, pem_data)
+	elif profile_id in sbr_profile_ids:
+		return lint_cabf_smime_cert(profile_id, pem_data)
+	elif profile_id in tbr_tevg_profile_ids:
+		return lint_cabf_serverauth_cert(profile_id, pem_data)
+	elif profile_id in crl_profile_ids:
+		return lint_crl(pem_data, profile_id)
+	elif profile_id in ocspresponse_profile_ids:
+		return lint_ocsp_response(pem_data)
+	return lint_pkix_cert(pem_data)
 
-profile_id = -1
-pem_data = ""
 try:
 	init_smime_validators()
 	init_serverauth_validators_and_filters()
 	init_etsi_validators_and_filters()
-	print("` + linter.PKIMETAL_READY + `", flush=True)
+	print(json.dumps({"v": protocol_version, "ready": True}), flush=True)
 	for line in stdin:
-		if profile_id == -1:
-			profile_id = int(line.strip())
-		else:
-			pem_data = pem_data + line.strip() + "\n"
-
-		if "END CERTIFICATE" in line or "END X509 CRL" in line or "END OCSP RESPONSE" in line:
-			if profile_id in etsi_profile_ids:
-				print(lint_etsi_cert(profile_id, profile_id not in etsi_non_browser_profile_ids, pem_data))
-			elif profile_id in sbr_profile_ids:
-				print(lint_cabf_smime_cert(profile_id, pem_data))
-			elif profile_id in tbr_tevg_profile_ids:
-				print(lint_cabf_serverauth_cert(profile_id, pem_data))
-			elif profile_id in crl_profile_ids:
-				print(lint_crl(pem_data, profile_id))
-			elif profile_id in ocspresponse_profile_ids:
-				print(lint_ocsp_response(pem_data))
+		request = json.loads(line)
+		response = {"v": protocol_version, "id": request["id"], "end": True}
+		try:
+			if request["v"] != protocol_version:
+				response["error"] = "Unsupported protocol version: " + str(request["v"])
 			else:
-				print(lint_pkix_cert(pem_data))
-			print("` + linter.PKIMETAL_ENDOFRESULTS + `", flush=True)
-			profile_id = -1
-			pem_data = ""
+				response["findings"] = lint(request)
+		except Exception as e:
+			response["error"] = "Exception: " + str(e)
+		print(json.dumps(response), flush=True)
 except KeyboardInterrupt:
 	pass
 `}
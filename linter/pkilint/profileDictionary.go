@@ -8,28 +8,28 @@ import (
 
 var serverauth_profile_dictionary = fmt.Sprintf(`serverauth_profile_dictionary = {
 	# CABForum TLS Baseline Requirements.
-	%d: serverauth_constants.CertificateType.ROOT_CA,
-	%d: serverauth_constants.CertificateType.INTERNAL_CROSS_CA,
-	%d: serverauth_constants.CertificateType.INTERNAL_SUBSCRIBER_ISSUING_CROSS_CA,
-	%d: serverauth_constants.CertificateType.EXTERNAL_CROSS_CA,
-	%d: serverauth_constants.CertificateType.EXTERNAL_SUBSCRIBER_ISSUING_CROSS_CA,
-	%d: serverauth_constants.CertificateType.INTERNAL_UNCONSTRAINED_TLS_CA,
-	%d: serverauth_constants.CertificateType.INTERNAL_CONSTRAINED_TLS_CA,
-	%d: serverauth_constants.CertificateType.EXTERNAL_UNCONSTRAINED_TLS_CA,
-	%d: serverauth_constants.CertificateType.EXTERNAL_CONSTRAINED_TLS_CA,
-	%d: serverauth_constants.CertificateType.PRECERT_SIGNING_CA,
-	%d: serverauth_constants.CertificateType.DV_FINAL_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.DV_PRE_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.OV_FINAL_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.OV_PRE_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.IV_FINAL_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.IV_PRE_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.OCSP_RESPONDER,
+	%q: serverauth_constants.CertificateType.ROOT_CA,
+	%q: serverauth_constants.CertificateType.INTERNAL_CROSS_CA,
+	%q: serverauth_constants.CertificateType.INTERNAL_SUBSCRIBER_ISSUING_CROSS_CA,
+	%q: serverauth_constants.CertificateType.EXTERNAL_CROSS_CA,
+	%q: serverauth_constants.CertificateType.EXTERNAL_SUBSCRIBER_ISSUING_CROSS_CA,
+	%q: serverauth_constants.CertificateType.INTERNAL_UNCONSTRAINED_TLS_CA,
+	%q: serverauth_constants.CertificateType.INTERNAL_CONSTRAINED_TLS_CA,
+	%q: serverauth_constants.CertificateType.EXTERNAL_UNCONSTRAINED_TLS_CA,
+	%q: serverauth_constants.CertificateType.EXTERNAL_CONSTRAINED_TLS_CA,
+	%q: serverauth_constants.CertificateType.PRECERT_SIGNING_CA,
+	%q: serverauth_constants.CertificateType.DV_FINAL_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.DV_PRE_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.OV_FINAL_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.OV_PRE_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.IV_FINAL_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.IV_PRE_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.OCSP_RESPONDER,
 	# CABForum TLS Extended Validation Guidelines.
-	%d: serverauth_constants.CertificateType.EV_FINAL_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.EV_PRE_CERTIFICATE,
-	%d: serverauth_constants.CertificateType.EXTERNAL_UNCONSTRAINED_EV_TLS_CA,
-	%d: serverauth_constants.CertificateType.EXTERNAL_CONSTRAINED_EV_TLS_CA
+	%q: serverauth_constants.CertificateType.EV_FINAL_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.EV_PRE_CERTIFICATE,
+	%q: serverauth_constants.CertificateType.EXTERNAL_UNCONSTRAINED_EV_TLS_CA,
+	%q: serverauth_constants.CertificateType.EXTERNAL_CONSTRAINED_EV_TLS_CA
 }`,
 	linter.TBR_ROOT_TLSSERVER,
 	linter.TBR_CROSS_INTERNAL, linter.TBR_CROSS_INTERNAL_SUBSCRIBERISSUING, linter.TBR_CROSS_EXTERNAL, linter.TBR_CROSS_EXTERNAL_SUBSCRIBERISSUING,
@@ -46,18 +46,18 @@ var serverauth_profile_dictionary = fmt.Sprintf(`serverauth_profile_dictionary =
 
 var smime_profile_dictionary = fmt.Sprintf(`smime_profile_dictionary = {
 	# CABForum S/MIME Baseline Requirements.
-	%d: [smime_constants.ValidationLevel.MAILBOX, smime_constants.Generation.LEGACY],
-	%d: [smime_constants.ValidationLevel.MAILBOX, smime_constants.Generation.MULTIPURPOSE],
-	%d: [smime_constants.ValidationLevel.MAILBOX, smime_constants.Generation.STRICT],
-	%d: [smime_constants.ValidationLevel.ORGANIZATION, smime_constants.Generation.LEGACY],
-	%d: [smime_constants.ValidationLevel.ORGANIZATION, smime_constants.Generation.MULTIPURPOSE],
-	%d: [smime_constants.ValidationLevel.ORGANIZATION, smime_constants.Generation.STRICT],
-	%d: [smime_constants.ValidationLevel.SPONSORED, smime_constants.Generation.LEGACY],
-	%d: [smime_constants.ValidationLevel.SPONSORED, smime_constants.Generation.MULTIPURPOSE],
-	%d: [smime_constants.ValidationLevel.SPONSORED, smime_constants.Generation.STRICT],
-	%d: [smime_constants.ValidationLevel.INDIVIDUAL, smime_constants.Generation.LEGACY],
-	%d: [smime_constants.ValidationLevel.INDIVIDUAL, smime_constants.Generation.MULTIPURPOSE],
-	%d: [smime_constants.ValidationLevel.INDIVIDUAL, smime_constants.Generation.STRICT]
+	%q: [smime_constants.ValidationLevel.MAILBOX, smime_constants.Generation.LEGACY],
+	%q: [smime_constants.ValidationLevel.MAILBOX, smime_constants.Generation.MULTIPURPOSE],
+	%q: [smime_constants.ValidationLevel.MAILBOX, smime_constants.Generation.STRICT],
+	%q: [smime_constants.ValidationLevel.ORGANIZATION, smime_constants.Generation.LEGACY],
+	%q: [smime_constants.ValidationLevel.ORGANIZATION, smime_constants.Generation.MULTIPURPOSE],
+	%q: [smime_constants.ValidationLevel.ORGANIZATION, smime_constants.Generation.STRICT],
+	%q: [smime_constants.ValidationLevel.SPONSORED, smime_constants.Generation.LEGACY],
+	%q: [smime_constants.ValidationLevel.SPONSORED, smime_constants.Generation.MULTIPURPOSE],
+	%q: [smime_constants.ValidationLevel.SPONSORED, smime_constants.Generation.STRICT],
+	%q: [smime_constants.ValidationLevel.INDIVIDUAL, smime_constants.Generation.LEGACY],
+	%q: [smime_constants.ValidationLevel.INDIVIDUAL, smime_constants.Generation.MULTIPURPOSE],
+	%q: [smime_constants.ValidationLevel.INDIVIDUAL, smime_constants.Generation.STRICT]
 }`,
 	linter.SBR_LEAF_SMIME_MV_LEGACY, linter.SBR_LEAF_SMIME_MV_MULTIPURPOSE, linter.SBR_LEAF_SMIME_MV_STRICT,
 	linter.SBR_LEAF_SMIME_OV_LEGACY, linter.SBR_LEAF_SMIME_OV_MULTIPURPOSE, linter.SBR_LEAF_SMIME_OV_STRICT,
@@ -67,25 +67,25 @@ var smime_profile_dictionary = fmt.Sprintf(`smime_profile_dictionary = {
 
 var etsi_profile_dictionary = fmt.Sprintf(`etsi_profile_dictionary = {
 	# ETSI EN 319 412.
-	%d: etsi_constants.CertificateType.NCP_W_NATURAL_PERSON_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.NCP_W_NATURAL_PERSON_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.NCP_W_LEGAL_PERSON_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.NCP_W_LEGAL_PERSON_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.DVCP_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.DVCP_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.IVCP_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.IVCP_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.OVCP_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.OVCP_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.EVCP_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.EVCP_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QEVCP_W_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QEVCP_W_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_IV_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_IV_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_OV_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_OV_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QEVCP_W_NON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QEVCP_W_NON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_IV_NON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_IV_NON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_OV_NON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_OV_NON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_NON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_NON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_NON_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_NON_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_NON_BROWSER_FINAL_CERTIFICATE, %d: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_NON_BROWSER_PRE_CERTIFICATE,
-	%d: etsi_constants.CertificateType.NCP_NATURAL_PERSON_CERTIFICATE, %d: etsi_constants.CertificateType.NCP_LEGAL_PERSON_CERTIFICATE,
+	%q: etsi_constants.CertificateType.NCP_W_NATURAL_PERSON_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.NCP_W_NATURAL_PERSON_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.NCP_W_LEGAL_PERSON_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.NCP_W_LEGAL_PERSON_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.DVCP_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.DVCP_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.IVCP_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.IVCP_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.OVCP_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.OVCP_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.EVCP_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.EVCP_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QEVCP_W_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QEVCP_W_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_IV_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_IV_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_OV_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_OV_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QEVCP_W_NON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QEVCP_W_NON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_IV_NON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_IV_NON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_OV_NON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_OV_NON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_NON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_GEN_NATURAL_PERSON_NON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_NON_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QNCP_W_GEN_LEGAL_PERSON_NON_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_NON_BROWSER_FINAL_CERTIFICATE, %q: etsi_constants.CertificateType.QEVCP_W_PSD2_EIDAS_NON_BROWSER_PRE_CERTIFICATE,
+	%q: etsi_constants.CertificateType.NCP_NATURAL_PERSON_CERTIFICATE, %q: etsi_constants.CertificateType.NCP_LEGAL_PERSON_CERTIFICATE,
 }`,
 	linter.ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON, linter.ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON_PRECERTIFICATE,
 	linter.ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON, linter.ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON_PRECERTIFICATE,
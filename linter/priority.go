@@ -0,0 +1,76 @@
+package linter
+
+import (
+	"context"
+
+	"github.com/pkimetal/pkimetal/config"
+)
+
+// PriorityClass determines which of a linter's queues a linting request waits in.
+type PriorityClass int
+
+const (
+	PRIORITYSTRING_INTERACTIVE = "interactive"
+	PRIORITYSTRING_BULK        = "bulk"
+)
+
+const (
+	PRIORITY_INTERACTIVE PriorityClass = iota
+	PRIORITY_BULK
+	NUM_PRIORITIES
+)
+
+var PriorityString = []string{
+	PRIORITYSTRING_INTERACTIVE,
+	PRIORITYSTRING_BULK,
+}
+
+var Priority = map[string]PriorityClass{
+	PRIORITYSTRING_INTERACTIVE: PRIORITY_INTERACTIVE,
+	PRIORITYSTRING_BULK:        PRIORITY_BULK,
+}
+
+// Queue returns the channel on which linting requests of the specified priority class are queued.  If the linter has
+// no bulk queue, bulk requests share the interactive queue.
+func (l *Linter) Queue(priority PriorityClass) chan LintingRequest {
+	if priority == PRIORITY_BULK && l.BulkReqChannel != nil {
+		return l.BulkReqChannel
+	}
+	return l.ReqChannel
+}
+
+// nextRequest waits for the next linting request to process, returning false if shutdown is requested first.
+// Interactive requests are preferred, but once the configured number of interactive requests have been processed in a
+// row, a waiting bulk request is processed next, so that bulk traffic is slowed rather than starved.
+func (lin *LinterInstance) nextRequest(ctx context.Context) (LintingRequest, bool) {
+	if ctx.Err() != nil {
+		return LintingRequest{}, false
+	}
+
+	if lin.interactiveStreak >= max(config.Config.Priority.InteractiveWeight, 1) {
+		select {
+		case lreq := <-lin.BulkReqChannel:
+			lin.interactiveStreak = 0
+			return lreq, true
+		default:
+		}
+	}
+	select {
+	case lreq := <-lin.ReqChannel:
+		lin.interactiveStreak++
+		return lreq, true
+	default:
+	}
+
+	// Neither queue has a preferred request waiting, so take whichever request arrives first.
+	select {
+	case lreq := <-lin.ReqChannel:
+		lin.interactiveStreak++
+		return lreq, true
+	case lreq := <-lin.BulkReqChannel:
+		lin.interactiveStreak = 0
+		return lreq, true
+	case <-ctx.Done():
+		return LintingRequest{}, false
+	}
+}
@@ -4,15 +4,81 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
 )
 
 type ProfileId int
 
 type Profile struct {
-	Name        string
-	Source      string
-	Description string
-	Linters     []*string
+	Name           string
+	Source         string
+	Description    string
+	Type           DocumentType `json:",omitempty"`
+	Hierarchy      Hierarchy    `json:",omitempty"` // Certificate profiles only.
+	Standard       Standard     `json:",omitempty"`
+	Precertificate bool         `json:",omitempty"`
+	Parent         string       `json:",omitempty"` // The more general profile that this profile refines.
+	EffectiveFrom  string       `json:",omitempty"` // YYYY-MM-DD.
+	EffectiveUntil string       `json:",omitempty"` // YYYY-MM-DD.
+	BaseProfile    string       `json:",omitempty"` // Custom profiles only.
+	Linters        []*string
+	parent         ProfileId
+	nonBrowser     bool
+	custom         *customProfile
+}
+
+// DocumentType is the type of document that a profile applies to.
+type DocumentType string
+
+const (
+	DOCUMENTTYPE_CERTIFICATE  DocumentType = "certificate"
+	DOCUMENTTYPE_CRL          DocumentType = "crl"
+	DOCUMENTTYPE_OCSPRESPONSE DocumentType = "ocspresponse"
+	DOCUMENTTYPE_CSR          DocumentType = "csr"
+	DOCUMENTTYPE_PUBLICKEY    DocumentType = "publickey"
+)
+
+// Hierarchy is a certificate profile's position in the PKI hierarchy.  Cross-certificates are subordinate CA
+// certificates.
+type Hierarchy string
+
+const (
+	HIERARCHY_ROOT        Hierarchy = "root"
+	HIERARCHY_SUBORDINATE Hierarchy = "subordinate"
+	HIERARCHY_LEAF        Hierarchy = "leaf"
+)
+
+// Standard is the standard or set of requirements that defines a profile.
+type Standard string
+
+const (
+	STANDARD_RFC5280   Standard = "rfc5280"
+	STANDARD_RFC6960   Standard = "rfc6960"
+	STANDARD_RFC2986   Standard = "rfc2986"
+	STANDARD_TBR       Standard = "tbr"
+	STANDARD_TEVG      Standard = "tevg"
+	STANDARD_SBR       Standard = "sbr"
+	STANDARD_CSBR      Standard = "csbr"
+	STANDARD_ETSI      Standard = "etsi"
+	STANDARD_BIMIGROUP Standard = "bimigroup"
+)
+
+var (
+	DocumentTypes     = []DocumentType{DOCUMENTTYPE_CERTIFICATE, DOCUMENTTYPE_CRL, DOCUMENTTYPE_OCSPRESPONSE, DOCUMENTTYPE_CSR, DOCUMENTTYPE_PUBLICKEY}
+	Hierarchies       = []Hierarchy{HIERARCHY_ROOT, HIERARCHY_SUBORDINATE, HIERARCHY_LEAF}
+	Standards         = []Standard{STANDARD_RFC5280, STANDARD_RFC6960, STANDARD_RFC2986, STANDARD_TBR, STANDARD_TEVG, STANDARD_SBR, STANDARD_CSBR, STANDARD_ETSI, STANDARD_BIMIGROUP}
+	CabforumStandards = []Standard{STANDARD_TBR, STANDARD_TEVG, STANDARD_SBR, STANDARD_CSBR}
+)
+
+// customProfile holds the configuration of a custom profile declared in config.yaml.
+type customProfile struct {
+	base            ProfileId
+	enabledLinters  []string
+	disabledLinters []string
+	checksAdded     []string
+	checksDisabled  []string
 }
 
 const (
@@ -30,8 +96,11 @@ const (
 	RFC5280_LEAF_OCSPSIGNING
 	RFC5280_CRL
 	RFC5280_ARL
+	RFC5280_PUBLICKEY
 	// RFC6960.
 	RFC6960_OCSPRESPONSE
+	// RFC2986.
+	RFC2986_CSR
 	// CABForum TLS Baseline Requirements.
 	TBR_ROOT_TLSSERVER
 	TBR_CROSS_INTERNAL
@@ -129,197 +198,389 @@ const (
 	BIMIGROUP_LEAF_COMMONMARK_PRECERTIFICATE
 	BIMIGROUP_LEAF_VERIFIEDMARK
 	BIMIGROUP_LEAF_VERIFIEDMARK_PRECERTIFICATE
+	// Custom profiles, declared in config.yaml, are numbered from here.
+	FIRST_CUSTOM_PROFILE
 )
 
+const CUSTOM_PROFILE_SOURCE = "Custom"
+
 var (
+	// Profile names are stable identifiers, so existing profiles must never be renamed.
 	AllProfiles = map[ProfileId]Profile{
 		AUTODETECT: {Name: "autodetect", Description: "AUTO-DETECT"},
 		// RFC5280.
-		RFC5280_ROOT:                 {Name: "rfc5280_root", Source: "RFC5280", Description: "Root CA Certificate"},
-		RFC5280_SUBORDINATE:          {Name: "rfc5280_subordinate", Source: "RFC5280", Description: "Subordinate CA Certificate"},
-		RFC5280_LEAF:                 {Name: "rfc5280_leaf", Source: "RFC5280", Description: "Leaf Certificate"},
-		RFC5280_LEAF_TLSCLIENT:       {Name: "rfc5280_leaf_tlsclient", Source: "RFC5280", Description: "TLS Client Certificate"},
-		RFC5280_LEAF_TLSSERVER:       {Name: "rfc5280_leaf_tlsserver", Source: "RFC5280", Description: "TLS Server Certificate"},
-		RFC5280_LEAF_SMIME:           {Name: "rfc5280_leaf_smime", Source: "RFC5280", Description: "S/MIME Certificate"},
-		RFC5280_LEAF_CODESIGNING:     {Name: "rfc5280_leaf_codesigning", Source: "RFC5280", Description: "Code Signing Certificate"},
-		RFC5280_LEAF_TIMESTAMPING:    {Name: "rfc5280_leaf_timestamping", Source: "RFC5280", Description: "Time Stamping Certificate"},
-		RFC5280_LEAF_DOCUMENTSIGNING: {Name: "rfc5280_leaf_documentsigning", Source: "RFC5280", Description: "Document Signing Certificate"},
-		RFC5280_LEAF_OCSPSIGNING:     {Name: "rfc5280_leaf_ocspsigning", Source: "RFC5280", Description: "OCSP Signing Certificate"},
-		RFC5280_CRL:                  {Name: "rfc5280_crl", Source: "RFC5280", Description: "Certificate Revocation List"},
-		RFC5280_ARL:                  {Name: "rfc5280_arl", Source: "RFC5280", Description: "Authority Revocation List"},
+		RFC5280_ROOT:                 {Name: "rfc5280_root", Source: "RFC5280", Description: "Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_RFC5280},
+		RFC5280_SUBORDINATE:          {Name: "rfc5280_subordinate", Source: "RFC5280", Description: "Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_RFC5280},
+		RFC5280_LEAF:                 {Name: "rfc5280_leaf", Source: "RFC5280", Description: "Leaf Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280},
+		RFC5280_LEAF_TLSCLIENT:       {Name: "rfc5280_leaf_tlsclient", Source: "RFC5280", Description: "TLS Client Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_LEAF_TLSSERVER:       {Name: "rfc5280_leaf_tlsserver", Source: "RFC5280", Description: "TLS Server Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_LEAF_SMIME:           {Name: "rfc5280_leaf_smime", Source: "RFC5280", Description: "S/MIME Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_LEAF_CODESIGNING:     {Name: "rfc5280_leaf_codesigning", Source: "RFC5280", Description: "Code Signing Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_LEAF_TIMESTAMPING:    {Name: "rfc5280_leaf_timestamping", Source: "RFC5280", Description: "Time Stamping Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_LEAF_DOCUMENTSIGNING: {Name: "rfc5280_leaf_documentsigning", Source: "RFC5280", Description: "Document Signing Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_LEAF_OCSPSIGNING:     {Name: "rfc5280_leaf_ocspsigning", Source: "RFC5280", Description: "OCSP Signing Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_RFC5280, parent: RFC5280_LEAF},
+		RFC5280_CRL:                  {Name: "rfc5280_crl", Source: "RFC5280", Description: "Certificate Revocation List", Type: DOCUMENTTYPE_CRL, Standard: STANDARD_RFC5280},
+		RFC5280_ARL:                  {Name: "rfc5280_arl", Source: "RFC5280", Description: "Authority Revocation List", Type: DOCUMENTTYPE_CRL, Standard: STANDARD_RFC5280, parent: RFC5280_CRL},
+		RFC5280_PUBLICKEY:            {Name: "rfc5280_publickey", Source: "RFC5280", Description: "Subject Public Key", Type: DOCUMENTTYPE_PUBLICKEY, Standard: STANDARD_RFC5280},
 		// RFC6960.
-		RFC6960_OCSPRESPONSE: {Name: "rfc6960_ocspresponse", Source: "RFC6960", Description: "OCSP Response"},
+		RFC6960_OCSPRESPONSE: {Name: "rfc6960_ocspresponse", Source: "RFC6960", Description: "OCSP Response", Type: DOCUMENTTYPE_OCSPRESPONSE, Standard: STANDARD_RFC6960},
+		// RFC2986.
+		RFC2986_CSR: {Name: "rfc2986_csr", Source: "RFC2986", Description: "Certificate Signing Request", Type: DOCUMENTTYPE_CSR, Standard: STANDARD_RFC2986},
 		// CABForum TLS Baseline Requirements.
-		TBR_ROOT_TLSSERVER:                               {Name: "tbr_root_tlsserver", Source: "TLS BRs", Description: "TLS Server Root CA Certificate"},
-		TBR_CROSS_INTERNAL:                               {Name: "tbr_cross_internal", Source: "TLS BRs", Description: "Internal TLS Server Cross-Certified Root CA Certificate"},
-		TBR_CROSS_INTERNAL_SUBSCRIBERISSUING:             {Name: "tbr_cross_internal_subscriberissuing", Source: "TLS BRs", Description: "Internal TLS Server Cross-Certified Subordinate CA Certificate"},
-		TBR_CROSS_EXTERNAL:                               {Name: "tbr_cross_external", Source: "TLS BRs", Description: "External TLS Server Cross-Certified Root CA Certificate"},
-		TBR_CROSS_EXTERNAL_SUBSCRIBERISSUING:             {Name: "tbr_cross_external_subscriberissuing", Source: "TLS BRs", Description: "External TLS Server Cross-Certified Subordinate CA Certificate"},
-		TBR_SUBORDINATE_TLSSERVER:                        {Name: "tbr_subordinate_tlsserver", Source: "TLS BRs", Description: "TLS Server Subordinate CA Certificate"},
-		TBR_SUBORDINATE_TLSSERVER_INTERNAL_UNCONSTRAINED: {Name: "tbr_subordinate_tlsserver_internal_unconstrained", Source: "TLS BRs", Description: "Unconstrained Internal TLS Server Subordinate CA Certificate"},
-		TBR_SUBORDINATE_TLSSERVER_INTERNAL_CONSTRAINED:   {Name: "tbr_subordinate_tlsserver_internal_constrained", Source: "TLS BRs", Description: "Constrained Internal TLS Server Subordinate CA Certificate"},
-		TBR_SUBORDINATE_TLSSERVER_EXTERNAL_UNCONSTRAINED: {Name: "tbr_subordinate_tlsserver_external_unconstrained", Source: "TLS BRs", Description: "Unconstrained External TLS Server Subordinate CA Certificate"},
-		TBR_SUBORDINATE_TLSSERVER_EXTERNAL_CONSTRAINED:   {Name: "tbr_subordinate_tlsserver_external_constrained", Source: "TLS BRs", Description: "Constrained External TLS Server Subordinate CA Certificate"},
-		TBR_SUBORDINATE_PRECERTSIGNING:                   {Name: "tbr_subordinate_precertsigning", Source: "TLS BRs", Description: "Precertificate Signing Subordinate CA Certificate"},
-		TBR_LEAF_TLSSERVER_DV:                            {Name: "tbr_leaf_tlsserver_dv", Source: "TLS BRs", Description: "TLS Server Certificate: Domain Validated"},
-		TBR_LEAF_TLSSERVER_DV_PRECERTIFICATE:             {Name: "tbr_leaf_tlsserver_dv_precertificate", Source: "TLS BRs", Description: "TLS Server Precertificate: Domain Validated"},
-		TBR_LEAF_TLSSERVER_OV:                            {Name: "tbr_leaf_tlsserver_ov", Source: "TLS BRs", Description: "TLS Server Certificate: Organization Validated"},
-		TBR_LEAF_TLSSERVER_OV_PRECERTIFICATE:             {Name: "tbr_leaf_tlsserver_ov_precertificate", Source: "TLS BRs", Description: "TLS Server Precertificate: Organization Validated"},
-		TBR_LEAF_TLSSERVER_IV:                            {Name: "tbr_leaf_tlsserver_iv", Source: "TLS BRs", Description: "TLS Server Certificate: Individual Validated"},
-		TBR_LEAF_TLSSERVER_IV_PRECERTIFICATE:             {Name: "tbr_leaf_tlsserver_iv_precertificate", Source: "TLS BRs", Description: "TLS Server Precertificate: Individual Validated"},
-		TBR_LEAF_OCSPSIGNING:                             {Name: "tbr_leaf_ocspsigning", Source: "TLS BRs", Description: "OCSP Signing Certificate"},
-		TBR_CRL:                                          {Name: "tbr_crl", Source: "TLS BRs", Description: "Certificate Revocation List"},
-		TBR_ARL:                                          {Name: "tbr_arl", Source: "TLS BRs", Description: "Authority Revocation List"},
+		TBR_ROOT_TLSSERVER:                               {Name: "tbr_root_tlsserver", Source: "TLS BRs", Description: "TLS Server Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_TBR, parent: RFC5280_ROOT, EffectiveFrom: "2023-09-15"},
+		TBR_CROSS_INTERNAL:                               {Name: "tbr_cross_internal", Source: "TLS BRs", Description: "Internal TLS Server Cross-Certified Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: RFC5280_SUBORDINATE, EffectiveFrom: "2023-09-15"},
+		TBR_CROSS_INTERNAL_SUBSCRIBERISSUING:             {Name: "tbr_cross_internal_subscriberissuing", Source: "TLS BRs", Description: "Internal TLS Server Cross-Certified Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: TBR_CROSS_INTERNAL, EffectiveFrom: "2023-09-15"},
+		TBR_CROSS_EXTERNAL:                               {Name: "tbr_cross_external", Source: "TLS BRs", Description: "External TLS Server Cross-Certified Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: RFC5280_SUBORDINATE, EffectiveFrom: "2023-09-15"},
+		TBR_CROSS_EXTERNAL_SUBSCRIBERISSUING:             {Name: "tbr_cross_external_subscriberissuing", Source: "TLS BRs", Description: "External TLS Server Cross-Certified Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: TBR_CROSS_EXTERNAL, EffectiveFrom: "2023-09-15"},
+		TBR_SUBORDINATE_TLSSERVER:                        {Name: "tbr_subordinate_tlsserver", Source: "TLS BRs", Description: "TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: RFC5280_SUBORDINATE, EffectiveFrom: "2023-09-15"},
+		TBR_SUBORDINATE_TLSSERVER_INTERNAL_UNCONSTRAINED: {Name: "tbr_subordinate_tlsserver_internal_unconstrained", Source: "TLS BRs", Description: "Unconstrained Internal TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: TBR_SUBORDINATE_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_SUBORDINATE_TLSSERVER_INTERNAL_CONSTRAINED:   {Name: "tbr_subordinate_tlsserver_internal_constrained", Source: "TLS BRs", Description: "Constrained Internal TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: TBR_SUBORDINATE_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_SUBORDINATE_TLSSERVER_EXTERNAL_UNCONSTRAINED: {Name: "tbr_subordinate_tlsserver_external_unconstrained", Source: "TLS BRs", Description: "Unconstrained External TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: TBR_SUBORDINATE_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_SUBORDINATE_TLSSERVER_EXTERNAL_CONSTRAINED:   {Name: "tbr_subordinate_tlsserver_external_constrained", Source: "TLS BRs", Description: "Constrained External TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: TBR_SUBORDINATE_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_SUBORDINATE_PRECERTSIGNING:                   {Name: "tbr_subordinate_precertsigning", Source: "TLS BRs", Description: "Precertificate Signing Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TBR, parent: RFC5280_SUBORDINATE, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_TLSSERVER_DV:                            {Name: "tbr_leaf_tlsserver_dv", Source: "TLS BRs", Description: "TLS Server Certificate: Domain Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, parent: RFC5280_LEAF_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_TLSSERVER_DV_PRECERTIFICATE:             {Name: "tbr_leaf_tlsserver_dv_precertificate", Source: "TLS BRs", Description: "TLS Server Precertificate: Domain Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, Precertificate: true, parent: TBR_LEAF_TLSSERVER_DV, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_TLSSERVER_OV:                            {Name: "tbr_leaf_tlsserver_ov", Source: "TLS BRs", Description: "TLS Server Certificate: Organization Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, parent: RFC5280_LEAF_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_TLSSERVER_OV_PRECERTIFICATE:             {Name: "tbr_leaf_tlsserver_ov_precertificate", Source: "TLS BRs", Description: "TLS Server Precertificate: Organization Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, Precertificate: true, parent: TBR_LEAF_TLSSERVER_OV, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_TLSSERVER_IV:                            {Name: "tbr_leaf_tlsserver_iv", Source: "TLS BRs", Description: "TLS Server Certificate: Individual Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, parent: RFC5280_LEAF_TLSSERVER, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_TLSSERVER_IV_PRECERTIFICATE:             {Name: "tbr_leaf_tlsserver_iv_precertificate", Source: "TLS BRs", Description: "TLS Server Precertificate: Individual Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, Precertificate: true, parent: TBR_LEAF_TLSSERVER_IV, EffectiveFrom: "2023-09-15"},
+		TBR_LEAF_OCSPSIGNING:                             {Name: "tbr_leaf_ocspsigning", Source: "TLS BRs", Description: "OCSP Signing Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TBR, parent: RFC5280_LEAF_OCSPSIGNING, EffectiveFrom: "2023-09-15"},
+		TBR_CRL:                                          {Name: "tbr_crl", Source: "TLS BRs", Description: "Certificate Revocation List", Type: DOCUMENTTYPE_CRL, Standard: STANDARD_TBR, parent: RFC5280_CRL, EffectiveFrom: "2023-09-15"},
+		TBR_ARL:                                          {Name: "tbr_arl", Source: "TLS BRs", Description: "Authority Revocation List", Type: DOCUMENTTYPE_CRL, Standard: STANDARD_TBR, parent: RFC5280_ARL, EffectiveFrom: "2023-09-15"},
 		// CABForum TLS Extended Validation Guidelines.
-		TEVG_ROOT_TLSSERVER:                               {Name: "tevg_root_tlsserver", Source: "TLS EVGs", Description: "EV TLS Server Root CA Certificate"},
-		TEVG_SUBORDINATE_TLSSERVER:                        {Name: "tevg_subordinate_tlsserver", Source: "TLS EVGs", Description: "EV TLS Subordinate CA Certificate"},
-		TEVG_SUBORDINATE_TLSSERVER_EXTERNAL_UNCONSTRAINED: {Name: "tevg_subordinate_tlsserver_external_unconstrained", Source: "TLS EVGs", Description: "Unconstrained External EV TLS Server Subordinate CA Certificate"},
-		TEVG_SUBORDINATE_TLSSERVER_EXTERNAL_CONSTRAINED:   {Name: "tevg_subordinate_tlsserver_external_constrained", Source: "TLS EVGs", Description: "Constrained External EV TLS Server Subordinate CA Certificate"},
-		TEVG_LEAF_TLSSERVER_EV:                            {Name: "tevg_leaf_tlsserver_ev", Source: "TLS EVGs", Description: "TLS Server Certificate: Extended Validation"},
-		TEVG_LEAF_TLSSERVER_EV_PRECERTIFICATE:             {Name: "tevg_leaf_tlsserver_ev_precertificate", Source: "TLS EVGs", Description: "TLS Server Precertificate: Extended Validation"},
+		TEVG_ROOT_TLSSERVER:                               {Name: "tevg_root_tlsserver", Source: "TLS EVGs", Description: "EV TLS Server Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_TEVG, parent: TBR_ROOT_TLSSERVER},
+		TEVG_SUBORDINATE_TLSSERVER:                        {Name: "tevg_subordinate_tlsserver", Source: "TLS EVGs", Description: "EV TLS Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TEVG, parent: TBR_SUBORDINATE_TLSSERVER},
+		TEVG_SUBORDINATE_TLSSERVER_EXTERNAL_UNCONSTRAINED: {Name: "tevg_subordinate_tlsserver_external_unconstrained", Source: "TLS EVGs", Description: "Unconstrained External EV TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TEVG, parent: TBR_SUBORDINATE_TLSSERVER_EXTERNAL_UNCONSTRAINED},
+		TEVG_SUBORDINATE_TLSSERVER_EXTERNAL_CONSTRAINED:   {Name: "tevg_subordinate_tlsserver_external_constrained", Source: "TLS EVGs", Description: "Constrained External EV TLS Server Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_TEVG, parent: TBR_SUBORDINATE_TLSSERVER_EXTERNAL_CONSTRAINED},
+		TEVG_LEAF_TLSSERVER_EV:                            {Name: "tevg_leaf_tlsserver_ev", Source: "TLS EVGs", Description: "TLS Server Certificate: Extended Validation", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TEVG, parent: RFC5280_LEAF_TLSSERVER},
+		TEVG_LEAF_TLSSERVER_EV_PRECERTIFICATE:             {Name: "tevg_leaf_tlsserver_ev_precertificate", Source: "TLS EVGs", Description: "TLS Server Precertificate: Extended Validation", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_TEVG, Precertificate: true, parent: TEVG_LEAF_TLSSERVER_EV},
 		// CABForum S/MIME Baseline Requirements.
-		SBR_ROOT_SMIME:                 {Name: "sbr_root_smime", Source: "S/MIME BRs", Description: "S/MIME Root CA Certificate"},
-		SBR_SUBORDINATE_SMIME:          {Name: "sbr_subordinate_smime", Source: "S/MIME BRs", Description: "S/MIME Subordinate CA Certificate"},
-		SBR_LEAF_SMIME_MV_LEGACY:       {Name: "sbr_leaf_smime_mv_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Mailbox Validated, Legacy"},
-		SBR_LEAF_SMIME_MV_MULTIPURPOSE: {Name: "sbr_leaf_smime_mv_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Mailbox Validated, Multipurpose"},
-		SBR_LEAF_SMIME_MV_STRICT:       {Name: "sbr_leaf_smime_mv_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Mailbox Validated, Strict"},
-		SBR_LEAF_SMIME_OV_LEGACY:       {Name: "sbr_leaf_smime_ov_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Organization Validated, Legacy"},
-		SBR_LEAF_SMIME_OV_MULTIPURPOSE: {Name: "sbr_leaf_smime_ov_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Organization Validated, Multipurpose"},
-		SBR_LEAF_SMIME_OV_STRICT:       {Name: "sbr_leaf_smime_ov_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Organization Validated, Strict"},
-		SBR_LEAF_SMIME_SV_LEGACY:       {Name: "sbr_leaf_smime_sv_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Sponsor Validated, Legacy"},
-		SBR_LEAF_SMIME_SV_MULTIPURPOSE: {Name: "sbr_leaf_smime_sv_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Sponsor Validated, Multipurpose"},
-		SBR_LEAF_SMIME_SV_STRICT:       {Name: "sbr_leaf_smime_sv_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Sponsor Validated, Strict"},
-		SBR_LEAF_SMIME_IV_LEGACY:       {Name: "sbr_leaf_smime_iv_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Individual Validated, Legacy"},
-		SBR_LEAF_SMIME_IV_MULTIPURPOSE: {Name: "sbr_leaf_smime_iv_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Individual Validated, Multipurpose"},
-		SBR_LEAF_SMIME_IV_STRICT:       {Name: "sbr_leaf_smime_iv_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Individual Validated, Strict"},
+		SBR_ROOT_SMIME:                 {Name: "sbr_root_smime", Source: "S/MIME BRs", Description: "S/MIME Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_SBR, parent: RFC5280_ROOT, EffectiveFrom: "2023-09-01"},
+		SBR_SUBORDINATE_SMIME:          {Name: "sbr_subordinate_smime", Source: "S/MIME BRs", Description: "S/MIME Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_SBR, parent: RFC5280_SUBORDINATE, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_MV_LEGACY:       {Name: "sbr_leaf_smime_mv_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Mailbox Validated, Legacy", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_MV_MULTIPURPOSE: {Name: "sbr_leaf_smime_mv_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Mailbox Validated, Multipurpose", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_MV_STRICT:       {Name: "sbr_leaf_smime_mv_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Mailbox Validated, Strict", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_OV_LEGACY:       {Name: "sbr_leaf_smime_ov_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Organization Validated, Legacy", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_OV_MULTIPURPOSE: {Name: "sbr_leaf_smime_ov_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Organization Validated, Multipurpose", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_OV_STRICT:       {Name: "sbr_leaf_smime_ov_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Organization Validated, Strict", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_SV_LEGACY:       {Name: "sbr_leaf_smime_sv_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Sponsor Validated, Legacy", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_SV_MULTIPURPOSE: {Name: "sbr_leaf_smime_sv_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Sponsor Validated, Multipurpose", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_SV_STRICT:       {Name: "sbr_leaf_smime_sv_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Sponsor Validated, Strict", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_IV_LEGACY:       {Name: "sbr_leaf_smime_iv_legacy", Source: "S/MIME BRs", Description: "S/MIME Certificate: Individual Validated, Legacy", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_IV_MULTIPURPOSE: {Name: "sbr_leaf_smime_iv_multipurpose", Source: "S/MIME BRs", Description: "S/MIME Certificate: Individual Validated, Multipurpose", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
+		SBR_LEAF_SMIME_IV_STRICT:       {Name: "sbr_leaf_smime_iv_strict", Source: "S/MIME BRs", Description: "S/MIME Certificate: Individual Validated, Strict", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_SBR, parent: RFC5280_LEAF_SMIME, EffectiveFrom: "2023-09-01"},
 		// CABForum Code Signing Baseline Requirements.
-		CSBR_ROOT_CODESIGNING:         {Name: "csbr_root_codesigning", Source: "Code Signing BRs", Description: "Code Signing Root CA Certificate"},
-		CSBR_ROOT_TIMESTAMPING:        {Name: "csbr_root_timestamping", Source: "Code Signing BRs", Description: "Time Stamping Root CA Certificate"},
-		CSBR_SUBORDINATE_CODESIGNING:  {Name: "csbr_subordinate_codesigning", Source: "Code Signing BRs", Description: "Code Signing Subordinate CA Certificate"},
-		CSBR_SUBORDINATE_TIMESTAMPING: {Name: "csbr_subordinate_timestamping", Source: "Code Signing BRs", Description: "Time Stamping Subordinate CA Certificate"},
-		CSBR_LEAF_CODESIGNING_OV:      {Name: "csbr_leaf_codesigning_ov", Source: "Code Signing BRs", Description: "Code Signing Certificate: Organization Validated"},
-		CSBR_LEAF_CODESIGNING_EV:      {Name: "csbr_leaf_codesigning_ev", Source: "Code Signing BRs", Description: "Code Signing Certificate: Extended Validation"},
-		CSBR_LEAF_TIMESTAMPING:        {Name: "csbr_leaf_timestamping", Source: "Code Signing BRs", Description: "Time Stamping Certificate"},
+		CSBR_ROOT_CODESIGNING:         {Name: "csbr_root_codesigning", Source: "Code Signing BRs", Description: "Code Signing Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_CSBR, parent: RFC5280_ROOT},
+		CSBR_ROOT_TIMESTAMPING:        {Name: "csbr_root_timestamping", Source: "Code Signing BRs", Description: "Time Stamping Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_CSBR, parent: RFC5280_ROOT},
+		CSBR_SUBORDINATE_CODESIGNING:  {Name: "csbr_subordinate_codesigning", Source: "Code Signing BRs", Description: "Code Signing Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_CSBR, parent: RFC5280_SUBORDINATE},
+		CSBR_SUBORDINATE_TIMESTAMPING: {Name: "csbr_subordinate_timestamping", Source: "Code Signing BRs", Description: "Time Stamping Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_CSBR, parent: RFC5280_SUBORDINATE},
+		CSBR_LEAF_CODESIGNING_OV:      {Name: "csbr_leaf_codesigning_ov", Source: "Code Signing BRs", Description: "Code Signing Certificate: Organization Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_CSBR, parent: RFC5280_LEAF_CODESIGNING},
+		CSBR_LEAF_CODESIGNING_EV:      {Name: "csbr_leaf_codesigning_ev", Source: "Code Signing BRs", Description: "Code Signing Certificate: Extended Validation", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_CSBR, parent: RFC5280_LEAF_CODESIGNING},
+		CSBR_LEAF_TIMESTAMPING:        {Name: "csbr_leaf_timestamping", Source: "Code Signing BRs", Description: "Time Stamping Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_CSBR, parent: RFC5280_LEAF_TIMESTAMPING},
 		// ETSI.
-		ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON:                            {Name: "etsi_leaf_tlsserver_ncpwnaturalperson", Source: "EN 319 412", Description: "ETSI Website Authentication Certificate: Natural Person"},
-		ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON_PRECERTIFICATE:             {Name: "etsi_leaf_tlsserver_ncpwnaturalperson_precertificate", Source: "EN 319 412", Description: "ETSI Website Authentication Precertificate: Natural Person"},
-		ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON:                              {Name: "etsi_leaf_tlsserver_ncpwlegalperson", Source: "EN 319 412", Description: "ETSI Website Authentication Certificate: Legal Person"},
-		ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_ncpwlegalperson_precertificate", Source: "EN 319 412", Description: "ETSI Website Authentication Precertificate: Legal Person"},
-		ETSI_LEAF_TLSSERVER_DVCP:                                         {Name: "etsi_leaf_tlsserver_dvcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Domain Validated"},
-		ETSI_LEAF_TLSSERVER_DVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_dvcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Domain Validated"},
-		ETSI_LEAF_TLSSERVER_IVCP:                                         {Name: "etsi_leaf_tlsserver_ivcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Individual Validated"},
-		ETSI_LEAF_TLSSERVER_IVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_ivcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Individual Validated"},
-		ETSI_LEAF_TLSSERVER_OVCP:                                         {Name: "etsi_leaf_tlsserver_ovcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Organization Validated"},
-		ETSI_LEAF_TLSSERVER_OVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_ovcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Organization Validated"},
-		ETSI_LEAF_TLSSERVER_EVCP:                                         {Name: "etsi_leaf_tlsserver_evcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Extended Validation"},
-		ETSI_LEAF_TLSSERVER_EVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_evcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Extended Validation"},
-		ETSI_LEAF_TLSSERVER_QEVCPWEIDAS:                                  {Name: "etsi_leaf_tlsserver_qevcpweidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWEIDAS_PRECERTIFICATE:                   {Name: "etsi_leaf_tlsserver_qevcpweidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWIVEIDAS:                                 {Name: "etsi_leaf_tlsserver_qncpwiveidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: IV (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWIVEIDAS_PRECERTIFICATE:                  {Name: "etsi_leaf_tlsserver_qncpwiveidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: IV (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWOVEIDAS:                                 {Name: "etsi_leaf_tlsserver_qncpwoveidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: OV (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWOVEIDAS_PRECERTIFICATE:                  {Name: "etsi_leaf_tlsserver_qncpwoveidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: OV (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONEIDAS:                   {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Natural Person (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONEIDAS_PRECERTIFICATE:    {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: Natural Person (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONEIDAS:                     {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Legal Person (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONEIDAS_PRECERTIFICATE:      {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: Legal Person (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWNONEIDAS:                               {Name: "etsi_leaf_tlsserver_qevcpwnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWNONEIDAS_PRECERTIFICATE:                {Name: "etsi_leaf_tlsserver_qevcpwnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWIVNONEIDAS:                              {Name: "etsi_leaf_tlsserver_qncpwivnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: IV (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWIVNONEIDAS_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_qncpwivnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: IV (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWOVNONEIDAS:                              {Name: "etsi_leaf_tlsserver_qncpwovnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: OV (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWOVNONEIDAS_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_qncpwovnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: OV (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONNONEIDAS:                {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersonnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Natural Person (Qualified, non-EIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONNONEIDAS_PRECERTIFICATE: {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersonnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: Natural Person (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONNONEIDAS:                  {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersonnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Legal Person (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONNONEIDAS_PRECERTIFICATE:   {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersonnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Legal Person (Qualified, non-eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDAS:                              {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV, PSD2 (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDAS_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV, PSD2 (Qualified, eIDAS)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER:                    {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidasnonbrowser", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV, PSD2 (Qualified, eIDAS, non-Browser)"},
-		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER_PRECERTIFICATE:     {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidasnonbrowser_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV, PSD2 (Qualified, eIDAS, non-Browser)"},
-		ETSI_LEAF_NCPNATURALPERSON:                                       {Name: "etsi_leaf_ncpnaturalperson", Source: "EN 319 412", Description: "ETSI Electronic Seal Certificate: Natural Person"},
-		ETSI_LEAF_NCPLEGALPERSON:                                         {Name: "etsi_leaf_ncplegalperson", Source: "EN 319 412", Description: "ETSI Electronic Seal Certificate: Legal Person "},
+		ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON:                            {Name: "etsi_leaf_tlsserver_ncpwnaturalperson", Source: "EN 319 412", Description: "ETSI Website Authentication Certificate: Natural Person", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF_TLSSERVER},
+		ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON_PRECERTIFICATE:             {Name: "etsi_leaf_tlsserver_ncpwnaturalperson_precertificate", Source: "EN 319 412", Description: "ETSI Website Authentication Precertificate: Natural Person", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_NCPWNATURALPERSON},
+		ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON:                              {Name: "etsi_leaf_tlsserver_ncpwlegalperson", Source: "EN 319 412", Description: "ETSI Website Authentication Certificate: Legal Person", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF_TLSSERVER},
+		ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_ncpwlegalperson_precertificate", Source: "EN 319 412", Description: "ETSI Website Authentication Precertificate: Legal Person", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_NCPWLEGALPERSON},
+		ETSI_LEAF_TLSSERVER_DVCP:                                         {Name: "etsi_leaf_tlsserver_dvcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Domain Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: TBR_LEAF_TLSSERVER_DV},
+		ETSI_LEAF_TLSSERVER_DVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_dvcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Domain Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_DVCP},
+		ETSI_LEAF_TLSSERVER_IVCP:                                         {Name: "etsi_leaf_tlsserver_ivcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Individual Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: TBR_LEAF_TLSSERVER_IV},
+		ETSI_LEAF_TLSSERVER_IVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_ivcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Individual Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_IVCP},
+		ETSI_LEAF_TLSSERVER_OVCP:                                         {Name: "etsi_leaf_tlsserver_ovcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Organization Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: TBR_LEAF_TLSSERVER_OV},
+		ETSI_LEAF_TLSSERVER_OVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_ovcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Organization Validated", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_OVCP},
+		ETSI_LEAF_TLSSERVER_EVCP:                                         {Name: "etsi_leaf_tlsserver_evcp", Source: "EN 319 412", Description: "ETSI Server Certificate: Extended Validation", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: TEVG_LEAF_TLSSERVER_EV},
+		ETSI_LEAF_TLSSERVER_EVCP_PRECERTIFICATE:                          {Name: "etsi_leaf_tlsserver_evcp_precertificate", Source: "EN 319 412", Description: "ETSI Server Precertificate: Extended Validation", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_EVCP},
+		ETSI_LEAF_TLSSERVER_QEVCPWEIDAS:                                  {Name: "etsi_leaf_tlsserver_qevcpweidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_EVCP},
+		ETSI_LEAF_TLSSERVER_QEVCPWEIDAS_PRECERTIFICATE:                   {Name: "etsi_leaf_tlsserver_qevcpweidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QEVCPWEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWIVEIDAS:                                 {Name: "etsi_leaf_tlsserver_qncpwiveidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: IV (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_IVCP},
+		ETSI_LEAF_TLSSERVER_QNCPWIVEIDAS_PRECERTIFICATE:                  {Name: "etsi_leaf_tlsserver_qncpwiveidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: IV (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWIVEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWOVEIDAS:                                 {Name: "etsi_leaf_tlsserver_qncpwoveidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: OV (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_OVCP},
+		ETSI_LEAF_TLSSERVER_QNCPWOVEIDAS_PRECERTIFICATE:                  {Name: "etsi_leaf_tlsserver_qncpwoveidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: OV (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWOVEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONEIDAS:                   {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Natural Person (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF_TLSSERVER},
+		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONEIDAS_PRECERTIFICATE:    {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: Natural Person (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONEIDAS:                     {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Legal Person (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF_TLSSERVER},
+		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONEIDAS_PRECERTIFICATE:      {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: Legal Person (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONEIDAS},
+		ETSI_LEAF_TLSSERVER_QEVCPWNONEIDAS:                               {Name: "etsi_leaf_tlsserver_qevcpwnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_EVCP},
+		ETSI_LEAF_TLSSERVER_QEVCPWNONEIDAS_PRECERTIFICATE:                {Name: "etsi_leaf_tlsserver_qevcpwnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QEVCPWNONEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWIVNONEIDAS:                              {Name: "etsi_leaf_tlsserver_qncpwivnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: IV (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_IVCP},
+		ETSI_LEAF_TLSSERVER_QNCPWIVNONEIDAS_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_qncpwivnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: IV (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWIVNONEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWOVNONEIDAS:                              {Name: "etsi_leaf_tlsserver_qncpwovnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: OV (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_OVCP},
+		ETSI_LEAF_TLSSERVER_QNCPWOVNONEIDAS_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_qncpwovnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: OV (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWOVNONEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONNONEIDAS:                {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersonnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Natural Person (Qualified, non-EIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF_TLSSERVER},
+		ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONNONEIDAS_PRECERTIFICATE: {Name: "etsi_leaf_tlsserver_qncpwgennaturalpersonnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: Natural Person (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWGENNATURALPERSONNONEIDAS},
+		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONNONEIDAS:                  {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersonnoneidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Legal Person (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF_TLSSERVER},
+		ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONNONEIDAS_PRECERTIFICATE:   {Name: "etsi_leaf_tlsserver_qncpwgenlegalpersonnoneidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Cert: Legal Person (Qualified, non-eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QNCPWGENLEGALPERSONNONEIDAS},
+		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDAS:                              {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidas", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV, PSD2 (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: ETSI_LEAF_TLSSERVER_EVCP},
+		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDAS_PRECERTIFICATE:               {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidas_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV, PSD2 (Qualified, eIDAS)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, parent: ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDAS},
+		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER:                    {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidasnonbrowser", Source: "EN 319 412", Description: "ETSI Web Auth Cert: EV, PSD2 (Qualified, eIDAS, non-Browser)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, nonBrowser: true, parent: ETSI_LEAF_TLSSERVER_EVCP},
+		ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER_PRECERTIFICATE:     {Name: "etsi_leaf_tlsserver_qevcpwpsd2eidasnonbrowser_precertificate", Source: "EN 319 412", Description: "ETSI Web Auth Precert: EV, PSD2 (Qualified, eIDAS, non-Browser)", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, Precertificate: true, nonBrowser: true, parent: ETSI_LEAF_TLSSERVER_QEVCPWPSD2EIDASNONBROWSER},
+		ETSI_LEAF_NCPNATURALPERSON:                                       {Name: "etsi_leaf_ncpnaturalperson", Source: "EN 319 412", Description: "ETSI Electronic Seal Certificate: Natural Person", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF},
+		ETSI_LEAF_NCPLEGALPERSON:                                         {Name: "etsi_leaf_ncplegalperson", Source: "EN 319 412", Description: "ETSI Electronic Seal Certificate: Legal Person ", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_ETSI, parent: RFC5280_LEAF},
 		// BIMI Mark Certificates.
-		BIMIGROUP_ROOT_BIMI:                        {Name: "bimigroup_root_bimi", Source: "Mark Certificate Guidelines", Description: "BIMI Root CA Certificate"},
-		BIMIGROUP_SUBORDINATE_BIMI:                 {Name: "bimigroup_subordinate_bimi", Source: "Mark Certificate Guidelines", Description: "BIMI Subordinate CA Certificate"},
-		BIMIGROUP_LEAF_COMMONMARK:                  {Name: "bimigroup_leaf_commonmark", Source: "Mark Certificate Guidelines", Description: "Common Mark Certificate"},
-		BIMIGROUP_LEAF_COMMONMARK_PRECERTIFICATE:   {Name: "bimigroup_leaf_commonmark_precertificate", Source: "Mark Certificate Guidelines", Description: "Common Mark Precertificate"},
-		BIMIGROUP_LEAF_VERIFIEDMARK:                {Name: "bimigroup_leaf_verifiedmark", Source: "Mark Certificate Guidelines", Description: "Verified Mark Certificate"},
-		BIMIGROUP_LEAF_VERIFIEDMARK_PRECERTIFICATE: {Name: "bimigroup_leaf_verifiedmark_precertificate", Source: "Mark Certificate Guidelines", Description: "Verified Mark Precertificate"},
+		BIMIGROUP_ROOT_BIMI:                        {Name: "bimigroup_root_bimi", Source: "Mark Certificate Guidelines", Description: "BIMI Root CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_ROOT, Standard: STANDARD_BIMIGROUP, parent: RFC5280_ROOT},
+		BIMIGROUP_SUBORDINATE_BIMI:                 {Name: "bimigroup_subordinate_bimi", Source: "Mark Certificate Guidelines", Description: "BIMI Subordinate CA Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_SUBORDINATE, Standard: STANDARD_BIMIGROUP, parent: RFC5280_SUBORDINATE},
+		BIMIGROUP_LEAF_COMMONMARK:                  {Name: "bimigroup_leaf_commonmark", Source: "Mark Certificate Guidelines", Description: "Common Mark Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_BIMIGROUP, parent: RFC5280_LEAF},
+		BIMIGROUP_LEAF_COMMONMARK_PRECERTIFICATE:   {Name: "bimigroup_leaf_commonmark_precertificate", Source: "Mark Certificate Guidelines", Description: "Common Mark Precertificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_BIMIGROUP, Precertificate: true, parent: BIMIGROUP_LEAF_COMMONMARK},
+		BIMIGROUP_LEAF_VERIFIEDMARK:                {Name: "bimigroup_leaf_verifiedmark", Source: "Mark Certificate Guidelines", Description: "Verified Mark Certificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_BIMIGROUP, parent: RFC5280_LEAF},
+		BIMIGROUP_LEAF_VERIFIEDMARK_PRECERTIFICATE: {Name: "bimigroup_leaf_verifiedmark_precertificate", Source: "Mark Certificate Guidelines", Description: "Verified Mark Precertificate", Type: DOCUMENTTYPE_CERTIFICATE, Hierarchy: HIERARCHY_LEAF, Standard: STANDARD_BIMIGROUP, Precertificate: true, parent: BIMIGROUP_LEAF_VERIFIEDMARK},
 	}
 
-	AllProfilesOrdered                                                               []Profile
-	CrlProfileIDs, OcspProfileIDs, RootProfileIDs, SubordinateProfileIDs             []ProfileId
-	SbrLeafProfileIDs, TbrTevgLeafProfileIDs, TbrTevgCertificateProfileIDs           []ProfileId
-	TbrArlProfileIDs                                                                 = []ProfileId{TBR_ARL}
-	NonTbrTevgCertificateProfileIDs, NonCabforumProfileIDs, NonCertificateProfileIDs []ProfileId
-	MarkCertificateProfileIDs                                                        []ProfileId
-	EtsiCertificateProfileIDs, EtsiNonBrowserCertificateProfileIDs                   []ProfileId
-	PrecertificateProfileIDs                                                         []ProfileId
+	AllProfilesOrdered                                                                   []Profile
+	CrlProfileIDs, OcspProfileIDs, RootProfileIDs, SubordinateProfileIDs                 []ProfileId
+	SbrLeafProfileIDs, TbrTevgLeafProfileIDs, TbrTevgCertificateProfileIDs               []ProfileId
+	TbrArlProfileIDs                                                                     = []ProfileId{TBR_ARL}
+	NonTbrTevgCertificateProfileIDs, NonCabforumProfileIDs, NonCertificateProfileIDs     []ProfileId
+	CsrProfileIDs, PublicKeyProfileIDs, CsrOrPublicKeyProfileIDs, NonPublicKeyProfileIDs []ProfileId
+	MarkCertificateProfileIDs                                                            []ProfileId
+	EtsiCertificateProfileIDs, EtsiNonBrowserCertificateProfileIDs                       []ProfileId
+	PrecertificateProfileIDs                                                             []ProfileId
 )
 
 func init() {
-	// First pass.  Populate lists that don't intersect with other lists.
-	for k, v := range AllProfiles {
-		if strings.HasSuffix(v.Name, "_crl") || strings.HasSuffix(v.Name, "_arl") {
-			CrlProfileIDs = append(CrlProfileIDs, k)
-		} else if strings.HasSuffix(v.Name, "_ocspresponse") {
-			OcspProfileIDs = append(OcspProfileIDs, k)
-		} else if strings.Contains(v.Name, "_root_") || strings.HasSuffix(v.Name, "_root") {
-			RootProfileIDs = append(RootProfileIDs, k)
-		} else if strings.Contains(v.Name, "_subordinate_") || strings.HasSuffix(v.Name, "_subordinate") || strings.Contains(v.Name, "_cross_") {
-			SubordinateProfileIDs = append(SubordinateProfileIDs, k)
-		} else if strings.HasPrefix(v.Name, "sbr_leaf_") {
-			SbrLeafProfileIDs = append(SbrLeafProfileIDs, k)
-		} else if strings.HasPrefix(v.Name, "tbr_leaf_") || strings.HasPrefix(v.Name, "tevg_leaf_") || (strings.HasPrefix(v.Name, "etsi_leaf_tlsserver_") && !strings.Contains(v.Name, "person")) {
-			TbrTevgLeafProfileIDs = append(TbrTevgLeafProfileIDs, k)
-		} else if strings.HasPrefix(v.Name, "bimigroup_") {
-			MarkCertificateProfileIDs = append(MarkCertificateProfileIDs, k)
+	if err := validateProfiles(); err != nil {
+		panic(err)
+	}
+
+	// Derive the lists of profile IDs from the profiles' attributes.
+	for id := AUTODETECT; id < FIRST_CUSTOM_PROFILE; id++ {
+		profile := AllProfiles[id]
+		switch profile.Type {
+		case DOCUMENTTYPE_CRL:
+			CrlProfileIDs = append(CrlProfileIDs, id)
+		case DOCUMENTTYPE_OCSPRESPONSE:
+			OcspProfileIDs = append(OcspProfileIDs, id)
+		case DOCUMENTTYPE_CSR:
+			CsrProfileIDs = append(CsrProfileIDs, id)
+		case DOCUMENTTYPE_PUBLICKEY:
+			PublicKeyProfileIDs = append(PublicKeyProfileIDs, id)
+		}
+
+		switch profile.Hierarchy {
+		case HIERARCHY_ROOT:
+			RootProfileIDs = append(RootProfileIDs, id)
+		case HIERARCHY_SUBORDINATE:
+			SubordinateProfileIDs = append(SubordinateProfileIDs, id)
+		case HIERARCHY_LEAF:
+			if profile.Standard == STANDARD_SBR {
+				SbrLeafProfileIDs = append(SbrLeafProfileIDs, id)
+			} else if profile.Standard == STANDARD_BIMIGROUP {
+				MarkCertificateProfileIDs = append(MarkCertificateProfileIDs, id)
+			}
+		}
+
+		// A leaf profile that refines a TLS BR or TLS EVG profile (e.g., an ETSI DVCP/OVCP/IVCP/EVCP profile) is subject
+		// to the same requirements.  However, the linters lint ETSI profiles separately, so only the TLS BR and TLS EVG
+		// profiles themselves are TbrTevgCertificateProfileIDs.
+		if profile.Type == DOCUMENTTYPE_CERTIFICATE && (profile.Standard == STANDARD_TBR || profile.Standard == STANDARD_TEVG) {
+			TbrTevgCertificateProfileIDs = append(TbrTevgCertificateProfileIDs, id)
+		} else {
+			NonTbrTevgCertificateProfileIDs = append(NonTbrTevgCertificateProfileIDs, id)
+		}
+		if profile.Hierarchy == HIERARCHY_LEAF && refines(id, STANDARD_TBR, STANDARD_TEVG) {
+			TbrTevgLeafProfileIDs = append(TbrTevgLeafProfileIDs, id)
+		}
+
+		if !slices.Contains(CabforumStandards, profile.Standard) {
+			NonCabforumProfileIDs = append(NonCabforumProfileIDs, id)
+		}
+
+		if profile.Standard == STANDARD_ETSI {
+			EtsiCertificateProfileIDs = append(EtsiCertificateProfileIDs, id)
+			if profile.nonBrowser {
+				EtsiNonBrowserCertificateProfileIDs = append(EtsiNonBrowserCertificateProfileIDs, id)
+			}
+		}
+
+		if profile.Precertificate {
+			PrecertificateProfileIDs = append(PrecertificateProfileIDs, id)
 		}
 	}
 
-	// Second pass.  NonCabforumProfileIDs intersects with other lists, and TbrTevgCertificateProfileIDs requires CrlProfileIDs to be populated first.
-	for k, v := range AllProfiles {
-		if !strings.HasPrefix(v.Name, "tbr_") && !strings.HasPrefix(v.Name, "tevg_") && !strings.HasPrefix(v.Name, "sbr_") && !strings.HasPrefix(v.Name, "csbr_") {
-			NonCabforumProfileIDs = append(NonCabforumProfileIDs, k)
-		} else if (strings.HasPrefix(v.Name, "tbr_") || strings.HasPrefix(v.Name, "tevg_") || (strings.HasPrefix(v.Name, "etsi_leaf_tlsserver_") && !strings.Contains(v.Name, "person"))) && !slices.Contains(CrlProfileIDs, k) && !slices.Contains(OcspProfileIDs, k) {
-			TbrTevgCertificateProfileIDs = append(TbrTevgCertificateProfileIDs, k)
+	NonCertificateProfileIDs = slices.Concat(CrlProfileIDs, OcspProfileIDs, CsrProfileIDs, PublicKeyProfileIDs)
+	CsrOrPublicKeyProfileIDs = slices.Concat(CsrProfileIDs, PublicKeyProfileIDs)
+	NonPublicKeyProfileIDs = slices.Concat(CrlProfileIDs, OcspProfileIDs)
+
+	// Finally, add the custom profiles.  These are deliberately absent from the lists above, because the linters only
+	// ever see the base profile.
+	if err := RegisterCustomProfiles(config.Config.Profiles.Custom); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterCustomProfiles validates the custom profiles declared in config.yaml, and adds them to AllProfiles in place
+// of any previously registered custom profiles.
+func RegisterCustomProfiles(cps []config.CustomProfile) error {
+	for id := range AllProfiles {
+		if id >= FIRST_CUSTOM_PROFILE {
+			delete(AllProfiles, id)
+		}
+	}
+	for i, cp := range cps {
+		id := FIRST_CUSTOM_PROFILE + ProfileId(i)
+		profile := Profile{Name: cp.Name, Source: CUSTOM_PROFILE_SOURCE, Description: cp.Description, BaseProfile: cp.BaseProfile}
+		profile.custom = &customProfile{
+			base:            -1,
+			enabledLinters:  cp.EnabledLinters,
+			disabledLinters: cp.DisabledLinters,
+			checksAdded:     cp.Include,
+			checksDisabled:  cp.Exclude,
+		}
+		if cp.Name == "" {
+			return fmt.Errorf("custom profile %d: name must be specified", i)
+		} else if _, ok := GetProfileId(cp.Name); ok {
+			return fmt.Errorf("custom profile %d: duplicate profile name: %s", i, cp.Name)
+		} else if base, ok := GetProfileId(cp.BaseProfile); !ok || base == AUTODETECT || AllProfiles[base].custom != nil {
+			return fmt.Errorf("custom profile %d: unrecognised base profile: %s", i, cp.BaseProfile)
+		} else {
+			profile.custom.base = base
+		}
+		for _, checks := range [][]string{cp.Include, cp.Exclude} {
+			if _, err := ParseCheckSelection(strings.Join(checks, ",")); err != nil {
+				return fmt.Errorf("custom profile %d: unrecognised check selection: %w", i, err)
+			}
+		}
+		// A custom profile has the same attributes as its base profile, which is also its parent.
+		base := AllProfiles[profile.custom.base]
+		if profile.Description == "" {
+			profile.Description = base.Description
+		}
+		profile.Type, profile.Hierarchy, profile.Standard, profile.Precertificate = base.Type, base.Hierarchy, base.Standard, base.Precertificate
+		profile.EffectiveFrom, profile.EffectiveUntil = base.EffectiveFrom, base.EffectiveUntil
+		profile.Parent, profile.parent = base.Name, profile.custom.base
+		AllProfiles[id] = profile
+	}
+
+	// Any linters that were registered before these custom profiles were added are also used by them.
+	for _, l := range Linters {
+		if l.NumInstances > 0 {
+			registerLinterWithProfiles(l, FIRST_CUSTOM_PROFILE)
 		}
+	}
+	return nil
+}
 
-		if strings.HasPrefix(v.Name, "etsi_") {
-			EtsiCertificateProfileIDs = append(EtsiCertificateProfileIDs, k)
-			if strings.Contains(v.Name, "nonbrowser") {
-				EtsiNonBrowserCertificateProfileIDs = append(EtsiNonBrowserCertificateProfileIDs, k)
+// validateProfiles verifies that every built-in profile has a consistent set of attributes, and fills in the name of
+// each profile's parent.
+func validateProfiles() error {
+	for id := AUTODETECT + 1; id < FIRST_CUSTOM_PROFILE; id++ {
+		profile, ok := AllProfiles[id]
+		switch {
+		case !ok:
+			return fmt.Errorf("profile %d: not defined", id)
+		case !slices.Contains(DocumentTypes, profile.Type):
+			return fmt.Errorf("profile %s: unrecognised document type: %s", profile.Name, profile.Type)
+		case !slices.Contains(Standards, profile.Standard):
+			return fmt.Errorf("profile %s: unrecognised standard: %s", profile.Name, profile.Standard)
+		case (profile.Type == DOCUMENTTYPE_CERTIFICATE) != slices.Contains(Hierarchies, profile.Hierarchy):
+			return fmt.Errorf("profile %s: unrecognised hierarchy: %s", profile.Name, profile.Hierarchy)
+		case profile.Precertificate && profile.Hierarchy != HIERARCHY_LEAF:
+			return fmt.Errorf("profile %s: only leaf profiles can be precertificate profiles", profile.Name)
+		case profile.parent == AUTODETECT:
+		case profile.parent < 0 || profile.parent >= FIRST_CUSTOM_PROFILE || profile.parent >= id:
+			return fmt.Errorf("profile %s: parent must be a built-in profile that is defined earlier", profile.Name)
+		case AllProfiles[profile.parent].Type != profile.Type:
+			return fmt.Errorf("profile %s: parent %s has a different document type", profile.Name, AllProfiles[profile.parent].Name)
+		case AllProfiles[profile.parent].Precertificate && !profile.Precertificate:
+			return fmt.Errorf("profile %s: parent %s is a precertificate profile", profile.Name, AllProfiles[profile.parent].Name)
+		}
+		for _, date := range []string{profile.EffectiveFrom, profile.EffectiveUntil} {
+			if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+				return fmt.Errorf("profile %s: unrecognised effective date: %w", profile.Name, err)
 			}
 		}
+		if profile.parent != AUTODETECT {
+			profile.Parent = AllProfiles[profile.parent].Name
+			AllProfiles[id] = profile
+		}
+	}
+	return nil
+}
+
+// refines reports whether the profile, or any of its ancestors, is defined by one of the specified standards.
+func refines(id ProfileId, standards ...Standard) bool {
+	for ; id != AUTODETECT; id = AllProfiles[id].parent {
+		if slices.Contains(standards, AllProfiles[id].Standard) {
+			return true
+		}
+	}
+	return false
+}
 
-		if strings.HasSuffix(v.Name, "_precertificate") {
-			PrecertificateProfileIDs = append(PrecertificateProfileIDs, k)
+// GetProfileId returns the ID of the profile with the specified name.
+func GetProfileId(name string) (ProfileId, bool) {
+	for id, profile := range AllProfiles {
+		if profile.Name == name {
+			return id, true
 		}
 	}
+	return -1, false
+}
+
+// BaseProfileId returns the base profile of a custom profile, or else the profile itself.  The linters are only ever
+// sent the base profile.
+func BaseProfileId(id ProfileId) ProfileId {
+	if custom := AllProfiles[id].custom; custom != nil {
+		return custom.base
+	}
+	return id
+}
+
+// ProfileChecks returns the check codes/globs that a custom profile includes and excludes.
+func ProfileChecks(id ProfileId) (checksAdded, checksDisabled []string) {
+	if custom := AllProfiles[id].custom; custom != nil {
+		return custom.checksAdded, custom.checksDisabled
+	}
+	return nil, nil
+}
 
-	// Third pass.  NonCertificateProfileIDs requires CrlProfileIDs and OcspProfileIDs to be populated first, and intersects with other lists.
-	NonCertificateProfileIDs = append(CrlProfileIDs, OcspProfileIDs...)
+// IsApplicable reports whether the linter supports the specified profile, taking into account a custom profile's
+// base profile and enabled/disabled linters.
+func (l *Linter) IsApplicable(id ProfileId) bool {
+	if custom := AllProfiles[id].custom; custom != nil {
+		if (len(custom.enabledLinters) > 0 && !slices.Contains(custom.enabledLinters, l.Name)) || slices.Contains(custom.disabledLinters, l.Name) {
+			return false
+		}
+		id = custom.base
+	}
+	return !slices.Contains(l.Unsupported, id)
+}
 
-	// Fourth pass.  NonTbrTevgCertificateProfileIDs requires TbrTevgCertificateProfileIDs to be populated first.
-	for k := range AllProfiles {
-		if !slices.Contains(TbrTevgCertificateProfileIDs, k) {
-			NonTbrTevgCertificateProfileIDs = append(NonTbrTevgCertificateProfileIDs, k)
+// checkCustomProfileLinters verifies that each linter named by a custom profile is a registered linter.
+func checkCustomProfileLinters() error {
+	for _, profile := range AllProfiles {
+		if profile.custom == nil {
+			continue
+		}
+		for _, name := range slices.Concat(profile.custom.enabledLinters, profile.custom.disabledLinters) {
+			if !slices.ContainsFunc(Linters, func(l *Linter) bool { return l.Name == name }) {
+				return fmt.Errorf("custom profile %s: unrecognised linter: %s", profile.Name, name)
+			}
 		}
 	}
+	return nil
+}
+
+// String returns the profile's name.  Profile names are the stable identifiers that are sent to external backends
+// and reported in API output; unlike profile IDs, which depend on the order in which the profiles are declared, they
+// never change between releases.
+func (id ProfileId) String() string {
+	return AllProfiles[id].Name
 }
 
-func ProfileIDList(list []ProfileId) string {
+// ProfileNameList returns a comma-separated list of the quoted names of the specified profiles, for embedding in a
+// backend's script.
+func ProfileNameList(list []ProfileId) string {
 	var s strings.Builder
 	for _, id := range list {
-		s.WriteString(fmt.Sprintf(",%d", id))
+		s.WriteString(fmt.Sprintf(",%q", id))
 	}
 	return s.String()[1:]
 }
 
-func registerLinterWithProfiles(linter *Linter) {
+// registerLinterWithProfiles lists the linter in each applicable profile whose ID is at least firstId.
+func registerLinterWithProfiles(linter *Linter, firstId ProfileId) {
 	for id, profile := range AllProfiles {
-		if !slices.Contains(linter.Unsupported, id) {
+		if id >= firstId && linter.IsApplicable(id) {
 			profile.Linters = append(profile.Linters, &linter.Name)
 			AllProfiles[id] = profile
 		}
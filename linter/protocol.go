@@ -0,0 +1,160 @@
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkimetal/pkimetal/utils"
+)
+
+// External backends speak one of two protocols on their STDIN and STDOUT.  The legacy protocol sends a header line
+// (the profile name) followed by PEM input, and receives "S: description" or pkilint JSON result tokens followed by the
+// end-of-results sentinel.  The JSON-lines protocol sends one request envelope per line, and receives response
+// envelopes that carry structured findings, errors, and an end marker.  See doc/INSTALL.md for the specification.
+const (
+	PROTOCOL_LEGACY    = 0
+	PROTOCOL_JSONLINES = 1 // The current version of the JSON-lines protocol.
+)
+
+// backendRequest is a JSON-lines protocol request envelope.
+type backendRequest struct {
+	Version int            `json:"v"`
+	Id      uint64         `json:"id"`
+	Profile string         `json:"profile"`
+	Type    DocumentType   `json:"type"`
+	DER     []byte         `json:"der"` // Base64-encoded.
+	Options backendOptions `json:"options"`
+}
+
+// backendOptions holds a request's options.
+type backendOptions struct {
+	Include []string `json:"include,omitempty"` // Only sent to backends that apply the check selection themselves.
+	Exclude []string `json:"exclude,omitempty"`
+	AsOf    string   `json:"asof,omitempty"` // RFC3339.  If omitted, evaluate time-dependent requirements as of now.
+}
+
+// backendResponse is a JSON-lines protocol response envelope.  A backend may send any number of envelopes for a
+// request, the last of which sets End.
+type backendResponse struct {
+	Version  int              `json:"v"`
+	Id       uint64           `json:"id"`
+	Ready    bool             `json:"ready"` // Only sent once, when the backend has finished initialising.
+	Findings []backendFinding `json:"findings"`
+	Error    string           `json:"error"` // The backend could not lint the input.
+	End      bool             `json:"end"`
+}
+
+// backendFinding is a finding within a JSON-lines protocol response envelope.
+type backendFinding struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+}
+
+// encodeRequest encodes a request to send to the backend on its STDIN.  Under the legacy protocol, the request ID is
+// only sent to pipelined backends.
+func (lin *LinterInstance) encodeRequest(id uint64, lreq *LintingRequest) ([]byte, error) {
+	if lin.Protocol == PROTOCOL_LEGACY {
+		if lin.Pipelined {
+			return utils.S2B(fmt.Sprintf("%d\t%s\n%s\n", id, lin.requestHeader(lreq), strings.TrimSpace(lreq.B64Input))), nil
+		}
+		return utils.S2B(fmt.Sprintf("%s\n%s\n", lin.requestHeader(lreq), strings.TrimSpace(lreq.B64Input))), nil
+	}
+
+	breq := backendRequest{
+		Version: lin.Protocol,
+		Id:      id,
+		Profile: lreq.ProfileId.String(),
+		Type:    AllProfiles[lreq.ProfileId].Type,
+		DER:     lreq.DecodedInput,
+	}
+	if lin.ForwardsChecks {
+		breq.Options.Include, breq.Options.Exclude = lreq.forwardedChecksAdded(), lreq.ChecksDisabled
+	}
+	if !lreq.AsOf.IsZero() {
+		breq.Options.AsOf = lreq.AsOf.UTC().Format(time.RFC3339)
+	}
+	b, err := json.Marshal(&breq)
+	return append(b, '\n'), err
+}
+
+// parseResponse parses a line that the backend wrote to its STDOUT into the ID of the request that it belongs to and
+// zero or more linting results.  end is true once the backend has finished the request.  Under the legacy protocol,
+// the request ID is only received from pipelined backends (and is otherwise 0).
+func (lin *LinterInstance) parseResponse(line string) (id uint64, results []LintingResult, end bool, err error) {
+	if lin.Protocol != PROTOCOL_LEGACY {
+		return lin.parseResponseEnvelope(line)
+	}
+
+	token := line
+	if lin.Pipelined {
+		var idString string
+		var ok bool
+		if idString, token, ok = strings.Cut(line, "\t"); !ok {
+			err = fmt.Errorf("response token has no request ID: '%s'", line)
+			return
+		} else if id, err = strconv.ParseUint(idString, 10, 64); err != nil {
+			err = fmt.Errorf("unrecognised request ID: '%s'", idString)
+			return
+		}
+	}
+	results, end, err = parseResultToken(lin.Name, token)
+	return
+}
+
+// parseResponseEnvelope parses a JSON-lines protocol response envelope.  An error reported by the backend is a fatal
+// finding, whereas a malformed envelope is a protocol error.
+func (lin *LinterInstance) parseResponseEnvelope(line string) (id uint64, results []LintingResult, end bool, err error) {
+	var bresp backendResponse
+	if err = json.Unmarshal(utils.S2B(line), &bresp); err != nil {
+		err = fmt.Errorf("unrecognised response envelope: '%s'", line)
+		return
+	} else if bresp.Version != lin.Protocol {
+		err = fmt.Errorf("unsupported protocol version: %d", bresp.Version)
+		return
+	} else if bresp.Ready {
+		err = fmt.Errorf("unexpected ready envelope")
+		return
+	}
+
+	for _, bf := range bresp.Findings {
+		severity, ok := Severity[strings.ToLower(bf.Severity)]
+		if !ok {
+			err = fmt.Errorf("unrecognised severity: '%s'", bf.Severity)
+			return
+		}
+		lresult := LintingResult{
+			LinterName: lin.Name,
+			Finding:    bf.Message,
+			Field:      bf.Field,
+			Code:       bf.Code,
+			Severity:   severity,
+		}
+		if lresult.Finding == "" {
+			lresult.Finding = bf.Code
+		}
+		results = append(results, lresult)
+	}
+	if bresp.Error != "" {
+		results = append(results, LintingResult{
+			LinterName: lin.Name,
+			Finding:    bresp.Error,
+			Severity:   SEVERITY_FATAL,
+		})
+	}
+	return bresp.Id, results, bresp.End, nil
+}
+
+// isReady determines whether or not a line that the backend wrote to its STDOUT during warm-up signals that it has
+// finished initialising.
+func (lin *LinterInstance) isReady(line string) bool {
+	if lin.Protocol == PROTOCOL_LEGACY {
+		return line == lin.ReadySignal
+	}
+	var bresp backendResponse
+	return json.Unmarshal(utils.S2B(line), &bresp) == nil && bresp.Version == lin.Protocol && bresp.Ready
+}
@@ -0,0 +1,85 @@
+package linter
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	zrsa "github.com/zmap/zcrypto/rsa"
+	"github.com/zmap/zcrypto/x509"
+)
+
+// PublicKey returns the subject public key of the request's certificate, CSR, or
+// public key.
+func (lreq *LintingRequest) PublicKey() any {
+	if lreq.Cert != nil {
+		return lreq.Cert.PublicKey
+	} else if lreq.Csr != nil {
+		return lreq.Csr.PublicKey
+	}
+	return lreq.Key
+}
+
+// RawSubjectPublicKeyInfo returns the DER-encoded SubjectPublicKeyInfo of the
+// request's certificate, CSR, or public key.
+func (lreq *LintingRequest) RawSubjectPublicKeyInfo() []byte {
+	if lreq.Cert != nil {
+		return lreq.Cert.RawSubjectPublicKeyInfo
+	} else if lreq.Csr != nil {
+		return lreq.Csr.RawSubjectPublicKeyInfo
+	}
+	return lreq.RawKey
+}
+
+// PublicKeyFindings reports the type and size of the request's public key, if
+// the input is a standalone public key.
+func (lreq *LintingRequest) PublicKeyFindings() []LintingResult {
+	if lreq.RawKey == nil {
+		return nil
+	}
+	return lreq.selectPkimetalResults(publicKeyFindings(lreq.Key))
+}
+
+// publicKeyFindings reports the public key's type and size, and checks them
+// against the algorithms and sizes that are widely accepted by the WebPKI.
+func publicKeyFindings(publicKey any) []LintingResult {
+	const field = "subjectPublicKeyInfo"
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsaPublicKeyFindings(pub.N, big.NewInt(int64(pub.E)))
+	case *zrsa.PublicKey:
+		return rsaPublicKeyFindings(pub.N, pub.E)
+	case *x509.AugmentedECDSA:
+		return publicKeyFindings(pub.Pub)
+	case *ecdsa.PublicKey:
+		switch name := pub.Curve.Params().Name; name {
+		case "P-256", "P-384", "P-521":
+			return []LintingResult{{Severity: SEVERITY_INFO, Field: field, Finding: "Public Key is ECDSA " + name}}
+		default:
+			return []LintingResult{{Severity: SEVERITY_ERROR, Code: "e_key_ecdsa_unsupported_curve", Field: field, Finding: fmt.Sprintf("ECDSA curve %s is not P-256, P-384, or P-521", name)}}
+		}
+	case ed25519.PublicKey:
+		return []LintingResult{{Severity: SEVERITY_INFO, Field: field, Finding: "Public Key is Ed25519"}}
+	default:
+		return []LintingResult{{Severity: SEVERITY_WARNING, Code: "w_key_unsupported_type", Field: field, Finding: fmt.Sprintf("Public Key type %T is not supported", publicKey)}}
+	}
+}
+
+func rsaPublicKeyFindings(n, e *big.Int) []LintingResult {
+	const field = "subjectPublicKeyInfo"
+	bits := n.BitLen()
+	lres := []LintingResult{{Severity: SEVERITY_INFO, Field: field, Finding: fmt.Sprintf("Public Key is RSA-%d", bits)}}
+	if bits < 2048 {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_key_rsa_too_small", Field: field, Finding: "RSA modulus is smaller than 2048 bits"})
+	} else if bits%8 != 0 {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_key_rsa_mod_not_multiple_of_8", Field: field, Finding: "RSA modulus size in bits is not evenly divisible by 8"})
+	}
+	if e.Cmp(big.NewInt(3)) < 0 || e.Bit(0) == 0 {
+		lres = append(lres, LintingResult{Severity: SEVERITY_ERROR, Code: "e_key_rsa_invalid_exponent", Field: field, Finding: "RSA public exponent is not an odd number greater than or equal to 3"})
+	} else if e.Cmp(big.NewInt(65537)) < 0 {
+		lres = append(lres, LintingResult{Severity: SEVERITY_WARNING, Code: "w_key_rsa_small_exponent", Field: field, Finding: "RSA public exponent is less than 65537"})
+	}
+	return lres
+}
@@ -44,7 +44,7 @@ func init() {
 		Name:         "pwnedkeys",
 		Version:      "1.0.0",
 		Url:          "https://pwnedkeys.com/api/v1",
-		Unsupported:  linter.NonCertificateProfileIDs,
+		Unsupported:  linter.NonPublicKeyProfileIDs,
 		NumInstances: config.Config.Linter.Pwnedkeys.NumGoroutines,
 		Interface:    func() linter.LinterInterface { return &Pwnedkeys{} },
 	}).Register()
@@ -61,7 +61,7 @@ func (l *Pwnedkeys) HandleRequest(ctx context.Context, lin *linter.LinterInstanc
 	var lres []linter.LintingResult
 	var httpRequest *http.Request
 	var err error
-	s := sha256.Sum256(lreq.Cert.RawSubjectPublicKeyInfo)
+	s := sha256.Sum256(lreq.RawSubjectPublicKeyInfo())
 	if httpRequest, err = http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://v1.pwnedkeys.com/%s", hex.EncodeToString(s[:])), nil); err != nil {
 		lres = append(lres, linter.LintingResult{
 			Severity: linter.Severity[config.Config.Linter.Pwnedkeys.APIErrorSeverity],
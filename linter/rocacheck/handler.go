@@ -18,7 +18,7 @@ func init() {
 		Name:         "rocacheck",
 		Version:      linter.GetPackageVersion("github.com/titanous/rocacheck"),
 		Url:          "https://github.com/titanous/rocacheck",
-		Unsupported:  linter.NonCertificateProfileIDs,
+		Unsupported:  linter.NonPublicKeyProfileIDs,
 		NumInstances: config.Config.Linter.Rocacheck.NumGoroutines,
 		Interface:    func() linter.LinterInterface { return &Rocacheck{} },
 	}).Register()
@@ -36,9 +36,9 @@ func (l *Rocacheck) HandleRequest(ctx context.Context, lin *linter.LinterInstanc
 		Severity: linter.SEVERITY_INFO,
 		Finding:  "Public Key is not a ROCA weak key",
 	}
-	switch lreq.Cert.PublicKey.(type) {
+	switch lreq.PublicKey().(type) {
 	case *rsa.PublicKey:
-		if rocacheck.IsWeak(lreq.Cert.PublicKey.(*rsa.PublicKey)) {
+		if rocacheck.IsWeak(lreq.PublicKey().(*rsa.PublicKey)) {
 			lres.Severity = linter.SEVERITY_ERROR
 			lres.Finding = "Public Key is a ROCA weak key"
 		}
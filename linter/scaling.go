@@ -0,0 +1,77 @@
+package linter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/logger"
+
+	"go.uber.org/zap"
+)
+
+// extraInstanceCount numbers the instances that are added by scaling, after those that are preconfigured.
+var extraInstanceCount atomic.Int64
+
+// scaleLoop periodically adjusts the number of instances of a linter whose backends run as external processes, between
+// its minimum and maximum number of instances, until shutdown is requested.
+func (l *Linter) scaleLoop(ctx context.Context) {
+	defer ShutdownWG.Done()
+
+	ticker := time.NewTicker(config.Config.Linter.Scaling.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			l.scale(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scale adds an instance if the estimated queue wait exceeds the threshold, or otherwise retires an added instance that
+// has been idle for the whole cool-down period.  Only one instance is added or retired at a time, and no instance is
+// added whilst another is still starting, so that the effect of each change is seen before the next.
+func (l *Linter) scale(ctx context.Context, now time.Time) {
+	instances := l.minInstances + len(l.extraInstances)
+	if instances < l.maxInstances && l.runningInstances.Load() == int64(instances) && l.EstimatedWait(PRIORITY_BULK) > config.Config.Linter.Scaling.ScaleUpWait {
+		l.addInstance(ctx)
+		return
+	}
+
+	// Retire the most recently added idle instance.
+	for i := len(l.extraInstances) - 1; i >= 0; i-- {
+		lin := l.extraInstances[i]
+		if now.Sub(time.Unix(0, lin.lastBusyAt.Load())) < config.Config.Linter.Scaling.CoolDown {
+			continue
+		}
+		l.extraInstances = append(l.extraInstances[:i], l.extraInstances[i+1:]...)
+		logger.Logger.Info("Retiring Linter", zap.Int("instance#", lin.instanceNumber), zap.String("name", lin.Name))
+		lin.retire() // The server loop finishes any request in progress, then stops the backend.
+		return
+	}
+}
+
+// RunningInstances returns the number of the linter's instances that are running.
+func (l *Linter) RunningInstances() int {
+	return int(l.runningInstances.Load())
+}
+
+// addInstance starts an extra instance, which (like the preconfigured instances) is started and warmed up by its
+// server loop.  Extra instances are not added to linterInstances, because external backends need no interface-level
+// cleanup when pkimetal stops.
+func (l *Linter) addInstance(ctx context.Context) {
+	instanceCtx, retire := context.WithCancel(ctx)
+	lin := &LinterInstance{
+		Linter:         l,
+		instanceNumber: len(linterInstances) + int(extraInstanceCount.Add(1)) - 1,
+		Mutex:          &sync.Mutex{},
+		retire:         retire,
+	}
+	lin.lastBusyAt.Store(time.Now().UnixNano()) // Start the cool-down afresh.
+	l.extraInstances = append(l.extraInstances, lin)
+	lin.start(instanceCtx)
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/pkimetal/pkimetal/config"
 	"github.com/pkimetal/pkimetal/linter"
 	"github.com/pkimetal/pkimetal/logger"
 
 	"github.com/zmap/zcrypto/x509"
-	"github.com/zmap/zlint/v3"
+	_ "github.com/zmap/zlint/v3" // Registers zlint's lints.
 	"github.com/zmap/zlint/v3/lint"
 
 	"go.uber.org/zap"
@@ -34,7 +35,7 @@ func init() {
 		Name:         "zlint",
 		Version:      linter.GetPackageVersion("github.com/zmap/zlint"),
 		Url:          "https://github.com/zmap/zlint",
-		Unsupported:  nil,
+		Unsupported:  linter.CsrOrPublicKeyProfileIDs,
 		NumInstances: config.Config.Linter.Zlint.NumGoroutines,
 		Interface:    func() linter.LinterInterface { return &Zlint{} },
 	}).Register()
@@ -89,28 +90,64 @@ func (l *Zlint) StartInstance() (useHandleRequest bool, directory, cmd string, a
 func (l *Zlint) StopInstance(lin *linter.LinterInstance) {
 }
 
+// asOf returns the time as of which the request's time-dependent requirements are evaluated.
+func asOf(lreq *linter.LintingRequest) time.Time {
+	if lreq.AsOf.IsZero() {
+		return time.Now()
+	}
+	return lreq.AsOf
+}
+
+// effectiveAsOf evaluates a lint's effective dates as of the as-of date, rather than (as zlint does) as of the
+// document's issuance date.  A lint that is no longer effective does not apply; for a lint that is not yet effective,
+// the date from which it is effective is returned, so that its findings can be reported separately.
+func effectiveAsOf(md *lint.LintMetadata, asOf time.Time) (applies bool, notYetEffective string) {
+	if !md.IneffectiveDate.IsZero() && !asOf.Before(md.IneffectiveDate) {
+		return false, ""
+	} else if !md.EffectiveDate.IsZero() && asOf.Before(md.EffectiveDate) {
+		return true, md.EffectiveDate.UTC().Format(time.DateOnly)
+	}
+	return true, ""
+}
+
+// undated returns a copy of the lint's metadata without its effective dates, so that zlint executes the lint
+// irrespective of the document's issuance date.
+func undated(md lint.LintMetadata) lint.LintMetadata {
+	md.EffectiveDate, md.IneffectiveDate = time.Time{}, time.Time{}
+	return md
+}
+
+// makeResult converts a zlint lint result into a linting result, if the lint reported a finding.
+func makeResult(md *lint.LintMetadata, result *lint.LintResult, notYetEffective string) (linter.LintingResult, bool) {
+	lresult := linter.LintingResult{
+		Finding:         md.Description,
+		Code:            md.Name,
+		NotYetEffective: notYetEffective,
+	}
+	switch result.Status {
+	case lint.Notice:
+		lresult.Severity = linter.SEVERITY_NOTICE
+	case lint.Warn:
+		lresult.Severity = linter.SEVERITY_WARNING
+	case lint.Error:
+		lresult.Severity = linter.SEVERITY_ERROR
+	case lint.Fatal:
+		lresult.Severity = linter.SEVERITY_FATAL
+	default:
+		return lresult, false
+	}
+	return lresult, true
+}
+
 func lintCert(lreq *linter.LintingRequest, registry *lint.Registry) []linter.LintingResult {
 	var lres []linter.LintingResult
-	zlintResultSet := zlint.LintCertificateEx(lreq.Cert, *registry)
-	for k, v := range zlintResultSet.Results {
-		certificateLint := defaultRegistry.CertificateLints().ByName(k)
-		lresult := linter.LintingResult{
-			Finding: certificateLint.Description,
-			Code:    certificateLint.Name,
-		}
-		switch v.Status {
-		case lint.Notice:
-			lresult.Severity = linter.SEVERITY_NOTICE
-		case lint.Warn:
-			lresult.Severity = linter.SEVERITY_WARNING
-		case lint.Error:
-			lresult.Severity = linter.SEVERITY_ERROR
-		case lint.Fatal:
-			lresult.Severity = linter.SEVERITY_FATAL
-		default:
-			continue
+	for _, certificateLint := range (*registry).CertificateLints().Lints() {
+		if applies, notYetEffective := effectiveAsOf(&certificateLint.LintMetadata, asOf(lreq)); applies {
+			l := lint.CertificateLint{LintMetadata: undated(certificateLint.LintMetadata), Lint: certificateLint.Lint}
+			if lresult, ok := makeResult(&certificateLint.LintMetadata, l.Execute(lreq.Cert, (*registry).GetConfiguration()), notYetEffective); ok {
+				lres = append(lres, lresult)
+			}
 		}
-		lres = append(lres, lresult)
 	}
 	return lres
 }
@@ -123,26 +160,13 @@ func lintCRL(lreq *linter.LintingRequest, registry *lint.Registry) []linter.Lint
 			Finding:  fmt.Sprintf("Could not parse CRL: %v", err),
 		})
 	} else {
-		zlintResultSet := zlint.LintRevocationListEx(crl, *registry)
-		for k, v := range zlintResultSet.Results {
-			crlLint := defaultRegistry.RevocationListLints().ByName(k)
-			lresult := linter.LintingResult{
-				Finding: crlLint.Description,
-				Code:    crlLint.Name,
+		for _, crlLint := range (*registry).RevocationListLints().Lints() {
+			if applies, notYetEffective := effectiveAsOf(&crlLint.LintMetadata, asOf(lreq)); applies {
+				l := lint.RevocationListLint{LintMetadata: undated(crlLint.LintMetadata), Lint: crlLint.Lint}
+				if lresult, ok := makeResult(&crlLint.LintMetadata, l.Execute(crl, (*registry).GetConfiguration()), notYetEffective); ok {
+					lres = append(lres, lresult)
+				}
 			}
-			switch v.Status {
-			case lint.Notice:
-				lresult.Severity = linter.SEVERITY_NOTICE
-			case lint.Warn:
-				lresult.Severity = linter.SEVERITY_WARNING
-			case lint.Error:
-				lresult.Severity = linter.SEVERITY_ERROR
-			case lint.Fatal:
-				lresult.Severity = linter.SEVERITY_FATAL
-			default:
-				continue
-			}
-			lres = append(lres, lresult)
 		}
 	}
 	return lres
@@ -156,31 +180,37 @@ func lintOCSPResponse(lreq *linter.LintingRequest, registry *lint.Registry) []li
 			Finding:  fmt.Sprintf("Could not parse OCSP Response: %v", err),
 		})
 	} else {
-		zlintResultSet := zlint.LintOcspResponseEx(ocspResponse, *registry)
-		for k, v := range zlintResultSet.Results {
-			ocspResponseLint := defaultRegistry.OcspResponseLints().ByName(k)
-			lresult := linter.LintingResult{
-				Finding: ocspResponseLint.Description,
-				Code:    ocspResponseLint.Name,
-			}
-			switch v.Status {
-			case lint.Notice:
-				lresult.Severity = linter.SEVERITY_NOTICE
-			case lint.Warn:
-				lresult.Severity = linter.SEVERITY_WARNING
-			case lint.Error:
-				lresult.Severity = linter.SEVERITY_ERROR
-			case lint.Fatal:
-				lresult.Severity = linter.SEVERITY_FATAL
-			default:
-				continue
+		for _, ocspResponseLint := range (*registry).OcspResponseLints().Lints() {
+			if applies, notYetEffective := effectiveAsOf(&ocspResponseLint.LintMetadata, asOf(lreq)); applies {
+				l := lint.OcspResponseLint{LintMetadata: undated(ocspResponseLint.LintMetadata), Lint: ocspResponseLint.Lint}
+				if lresult, ok := makeResult(&ocspResponseLint.LintMetadata, l.Execute(ocspResponse, (*registry).GetConfiguration()), notYetEffective); ok {
+					lres = append(lres, lresult)
+				}
 			}
-			lres = append(lres, lresult)
 		}
 	}
 	return lres
 }
 
+func selectChecks(lreq *linter.LintingRequest, registry *lint.Registry) (*lint.Registry, error) {
+	// Expand the requested codes/globs into the names of the lints to run.
+	var names []string
+	for _, name := range (*registry).Names() {
+		if lreq.IsCheckSelected(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	filteredRegistry, err := (*registry).Filter(lint.FilterOptions{IncludeNames: names})
+	if err != nil {
+		return nil, err
+	}
+	return &filteredRegistry, nil
+}
+
 func (l *Zlint) HandleRequest(ctx context.Context, lin *linter.LinterInstance, lreq *linter.LintingRequest) []linter.LintingResult {
 	var registry *lint.Registry
 	if slices.Contains(linter.TbrTevgLeafProfileIDs, lreq.ProfileId) {
@@ -195,6 +225,19 @@ func (l *Zlint) HandleRequest(ctx context.Context, lin *linter.LinterInstance, l
 		registry = &defaultRegistry
 	}
 
+	// Apply the request's check selection, if any, by filtering the registry.
+	if lreq.HasCheckSelection() {
+		var err error
+		if registry, err = selectChecks(lreq, registry); err != nil {
+			return []linter.LintingResult{{
+				Severity: linter.SEVERITY_FATAL,
+				Finding:  fmt.Sprintf("Could not apply check selection: %v", err),
+			}}
+		} else if registry == nil {
+			return nil // No lints are selected.
+		}
+	}
+
 	if slices.Contains(linter.OcspProfileIDs, lreq.ProfileId) {
 		return lintOCSPResponse(lreq, registry)
 	} else if slices.Contains(linter.CrlProfileIDs, lreq.ProfileId) {
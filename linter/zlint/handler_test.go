@@ -94,3 +94,69 @@ func TestTBRARLUsesCACRLNextUpdateLimit(t *testing.T) {
 		t.Fatal("TBR ARL reported subscriber nextUpdate limit violation")
 	}
 }
+
+func TestCheckSelectionFiltersRegistry(t *testing.T) {
+	thisUpdate := time.Date(2026, time.June, 24, 13, 0, 0, 0, time.UTC)
+	crlDER := testCRLDER(t, thisUpdate, thisUpdate.AddDate(0, 0, 11))
+
+	excludedResults := (&Zlint{}).HandleRequest(context.Background(), nil, &linter.LintingRequest{
+		DecodedInput:   crlDER,
+		ProfileId:      linter.TBR_CRL,
+		ChecksDisabled: []string{"e_crl_next_update_*"},
+	})
+	if hasFinding(excludedResults, "e_crl_next_update_invalid") {
+		t.Fatal("excluded lint was reported")
+	}
+
+	includedResults := (&Zlint{}).HandleRequest(context.Background(), nil, &linter.LintingRequest{
+		DecodedInput: crlDER,
+		ProfileId:    linter.TBR_CRL,
+		ChecksAdded:  []string{"e_crl_next_update_invalid"},
+	})
+	if len(includedResults) != 1 || !hasFinding(includedResults, "e_crl_next_update_invalid") {
+		t.Fatalf("expected only the included lint to be reported, got %+v", includedResults)
+	}
+
+	noneResults := (&Zlint{}).HandleRequest(context.Background(), nil, &linter.LintingRequest{
+		DecodedInput: crlDER,
+		ProfileId:    linter.TBR_CRL,
+		ChecksAdded:  []string{"no_such_lint_*"},
+	})
+	if len(noneResults) != 0 {
+		t.Fatalf("expected no results when no lints are selected, got %+v", noneResults)
+	}
+}
+
+func findingFor(results []linter.LintingResult, code string) *linter.LintingResult {
+	for i := range results {
+		if results[i].Code == code {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func TestAsOfEvaluatesEffectiveDates(t *testing.T) {
+	// e_crl_next_update_invalid is effective from 2023-07-15, after this CRL was issued.
+	thisUpdate := time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)
+	crlDER := testCRLDER(t, thisUpdate, thisUpdate.AddDate(0, 0, 11))
+
+	// As of now, the requirement is effective.
+	nowResults := (&Zlint{}).HandleRequest(context.Background(), nil, &linter.LintingRequest{
+		DecodedInput: crlDER,
+		ProfileId:    linter.TBR_CRL,
+	})
+	if lres := findingFor(nowResults, "e_crl_next_update_invalid"); lres == nil || lres.NotYetEffective != "" {
+		t.Fatalf("expected an effective finding, got %+v", lres)
+	}
+
+	// As of the CRL's issuance, the requirement is not yet effective.
+	issuanceResults := (&Zlint{}).HandleRequest(context.Background(), nil, &linter.LintingRequest{
+		DecodedInput: crlDER,
+		ProfileId:    linter.TBR_CRL,
+		AsOf:         thisUpdate,
+	})
+	if lres := findingFor(issuanceResults, "e_crl_next_update_invalid"); lres == nil || lres.NotYetEffective != "2023-07-15" {
+		t.Fatalf("expected a not yet effective finding, got %+v", lres)
+	}
+}
@@ -9,8 +9,11 @@ import (
 
 	"github.com/pkimetal/pkimetal/linter"
 	"github.com/pkimetal/pkimetal/logger"
+	"github.com/pkimetal/pkimetal/request"
 	"github.com/pkimetal/pkimetal/server"
 
+	"go.uber.org/zap"
+
 	// Register all of the enabled linter backends.
 	// External:
 	_ "github.com/pkimetal/pkimetal/linter/badkeys"
@@ -34,10 +37,18 @@ func main() {
 	defer logger.Logger.Info("Shutting down")
 	defer linter.ShutdownWG.Wait()
 
+	// Check that the decision policy only names registered linters.
+	if err := request.CheckDecisionPolicyLinters(); err != nil {
+		logger.Logger.Fatal("Invalid decision policy", zap.Error(err))
+	}
+
 	// Start the linters.
 	linter.StartLinters(ctx)
 	defer linter.StopLinters(ctx)
 
+	// Watch for newer data snapshots.
+	request.WatchDataSnapshots(ctx)
+
 	// Start the HTTP servers (Web and Monitoring).
 	server.Run()
 	defer server.Shutdown()
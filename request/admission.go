@@ -0,0 +1,60 @@
+package request
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkimetal/pkimetal/health"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/valyala/fasthttp"
+)
+
+const ADMISSION_REJECTED = "Linter queues are full; retry later"
+
+// admitLinters applies admission control, for the specified priority class, to every available linter that is
+// applicable to any of the specified profiles (or, if none are specified, to every available linter), counting one
+// request per applicable profile.  If any of them rejects the request, the longest of their retry intervals is
+// returned.
+func admitLinters(profileIds []linter.ProfileId, priority linter.PriorityClass) (time.Duration, bool) {
+	var retryAfter time.Duration
+	admitted := true
+	for _, l := range linter.Linters {
+		requests := 1
+		if len(profileIds) > 0 {
+			requests = 0
+			for _, id := range profileIds {
+				if l.IsApplicable(id) {
+					requests++
+				}
+			}
+		}
+		if l.NumInstances <= 0 || requests == 0 {
+			continue
+		} else if wait, ok := l.Admit(priority, requests); !ok {
+			retryAfter, admitted = max(retryAfter, wait), false
+		}
+	}
+	return retryAfter, admitted
+}
+
+// admit applies admission control to the linters that would handle the request.
+func (ri *RequestInfo) admit() (time.Duration, bool) {
+	if ri.complianceProfileIds != nil {
+		return admitLinters(ri.complianceProfileIds, ri.priority)
+	}
+	return admitLinters([]linter.ProfileId{ri.profileId}, ri.priority)
+}
+
+// setTooManyRequests marks the response as rejected by admission control, telling the client when to retry.  If an
+// interactive request was rejected, the instance is also reported as busy, so that readiness checks steer load
+// elsewhere; bulk requests are expected to back off and retry.
+func setTooManyRequests(fhctx *fasthttp.RequestCtx, retryAfter time.Duration, priority linter.PriorityClass) int {
+	if priority == linter.PRIORITY_INTERACTIVE {
+		now := time.Now()
+		health.UpdateLatestTimestamps(nil, nil, &now) // Busy.
+	}
+	fhctx.Response.Header.Set("Retry-After", strconv.Itoa(max(int(math.Ceil(retryAfter.Seconds())), 1)))
+	return fasthttp.StatusTooManyRequests
+}
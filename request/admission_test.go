@@ -0,0 +1,76 @@
+package request
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/health"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/valyala/fasthttp"
+)
+
+// withFullQueue replaces the registered linters with one that doesn't handle CRLs and whose queue is full, and one
+// whose queue is empty.
+func withFullQueue(t *testing.T) {
+	t.Helper()
+	saved := linter.Linters
+	t.Cleanup(func() { linter.Linters = saved })
+
+	full := &linter.Linter{Name: "full", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 1), Unsupported: linter.CrlProfileIDs}
+	full.ReqChannel <- linter.LintingRequest{}
+	linter.Linters = linter.LinterSlice{
+		full,
+		{Name: "idle", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 1)},
+	}
+}
+
+func TestAdmitLinters(t *testing.T) {
+	withFullQueue(t)
+	if _, ok := admitLinters([]linter.ProfileId{linter.RFC5280_CRL}, linter.PRIORITY_INTERACTIVE); !ok {
+		t.Error("expected a CRL to be admitted, because the full linter doesn't handle CRLs")
+	} else if retryAfter, ok := admitLinters([]linter.ProfileId{linter.RFC6960_OCSPRESPONSE}, linter.PRIORITY_INTERACTIVE); ok || retryAfter != time.Second {
+		t.Errorf("got %v, %v; want an OCSP response to be rejected", retryAfter, ok)
+	} else if _, ok = admitLinters([]linter.ProfileId{linter.RFC5280_CRL, linter.RFC6960_OCSPRESPONSE}, linter.PRIORITY_INTERACTIVE); ok {
+		t.Error("expected a request for multiple profiles to be rejected if any of their linters is full")
+	} else if _, ok = admitLinters(nil, linter.PRIORITY_INTERACTIVE); ok {
+		t.Error("expected a request for any profile to be rejected if any linter is full")
+	}
+}
+
+func TestSetTooManyRequests(t *testing.T) {
+	for _, tc := range []struct {
+		retryAfter time.Duration
+		want       string
+	}{
+		{0, "1"},
+		{time.Second, "1"},
+		{1500 * time.Millisecond, "2"},
+		{time.Minute, "60"},
+	} {
+		fhctx := newPostCtx("", nil)
+		if status := setTooManyRequests(fhctx, tc.retryAfter, linter.PRIORITY_BULK); status != fasthttp.StatusTooManyRequests {
+			t.Errorf("got status %d, want 429", status)
+		} else if got := string(fhctx.Response.Header.Peek("Retry-After")); got != tc.want {
+			t.Errorf("%v: got Retry-After %q, want %s", tc.retryAfter, got, tc.want)
+		}
+	}
+}
+
+func TestSetTooManyRequests_Busy(t *testing.T) {
+	saved := config.Config.Server.RememberBusyTimeout
+	t.Cleanup(func() { config.Config.Server.RememberBusyTimeout = saved })
+	config.Config.Server.RememberBusyTimeout = time.Hour
+
+	// Only the rejection of an interactive request marks the instance as busy.
+	ready := health.IsReady(&fasthttp.RequestCtx{})
+	setTooManyRequests(newPostCtx("", nil), time.Second, linter.PRIORITY_BULK)
+	if health.IsReady(&fasthttp.RequestCtx{}) != ready {
+		t.Error("expected a bulk rejection not to change readiness")
+	}
+	setTooManyRequests(newPostCtx("", nil), time.Second, linter.PRIORITY_INTERACTIVE)
+	if health.IsReady(&fasthttp.RequestCtx{}) {
+		t.Error("expected an interactive rejection to mark the instance as busy")
+	}
+}
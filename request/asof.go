@@ -0,0 +1,43 @@
+package request
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// The "asof" parameter's keywords.  Otherwise, the parameter is a date (YYYY-MM-DD) or an RFC3339 date/time.
+const (
+	ASOF_NOW       = "now"
+	ASOF_NOTBEFORE = "notBefore"
+)
+
+// parseAsOf parses an "asof" parameter, which defaults to now.  notBefore is true if the parameter refers to the
+// certificate's notBefore date, which is only known once the certificate has been parsed.
+func parseAsOf(s string) (asOf time.Time, notBefore bool, err error) {
+	switch {
+	case s == "" || strings.EqualFold(s, ASOF_NOW):
+		return time.Now(), false, nil
+	case strings.EqualFold(s, ASOF_NOTBEFORE):
+		return time.Time{}, true, nil
+	}
+	if asOf, err = time.Parse(time.DateOnly, s); err != nil {
+		asOf, err = time.Parse(time.RFC3339, s)
+	}
+	return asOf, false, err
+}
+
+// GetAsOf sets the time as of which time-dependent requirements are evaluated.
+func (ri *RequestInfo) GetAsOf(s string) error {
+	asOf, notBefore, err := parseAsOf(s)
+	if err != nil {
+		return err
+	} else if notBefore {
+		if ri.cert == nil {
+			return errors.New("notBefore is only supported for certificates")
+		}
+		asOf = ri.cert.NotBefore
+	}
+	ri.asOf = asOf
+	return nil
+}
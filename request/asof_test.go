@@ -0,0 +1,93 @@
+package request
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+func TestGetAsOf(t *testing.T) {
+	cert, err := x509.ParseCertificate(testcaseDER(t, "tls_ov_certificate.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		asOf string
+		want time.Time
+	}{
+		{"notBefore", cert.NotBefore},
+		{"NOTBEFORE", cert.NotBefore},
+		{"2024-03-15", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-15T12:30:00+01:00", time.Date(2024, 3, 15, 11, 30, 0, 0, time.UTC)},
+	} {
+		ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+		if err := ri.GetAsOf(tc.asOf); err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.asOf, err)
+		} else if !ri.asOf.Equal(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.asOf, ri.asOf, tc.want)
+		}
+	}
+
+	// The as-of date defaults to now.
+	for _, asOf := range []string{"", "now"} {
+		ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+		if err := ri.GetAsOf(asOf); err != nil {
+			t.Errorf("%q: unexpected error: %v", asOf, err)
+		} else if time.Since(ri.asOf) > time.Minute {
+			t.Errorf("%q: got %v, want now", asOf, ri.asOf)
+		}
+	}
+
+	for _, asOf := range []string{"yesterday", "2024-13-01", "15/03/2024"} {
+		ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+		if err := ri.GetAsOf(asOf); err == nil {
+			t.Errorf("%s: expected an error", asOf)
+		}
+	}
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCRL}
+	if err := ri.GetAsOf("notBefore"); err == nil {
+		t.Error("expected notBefore to be rejected for a CRL")
+	}
+}
+
+func TestLint_NotYetEffective(t *testing.T) {
+	withDecisionPolicy(t, "error", nil)
+	withFakeLinters(t,
+		linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_future", Code: "e_future", NotYetEffective: "2027-03-15"},
+		linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_WARNING, Finding: "w_a", Code: "w_a"},
+	)
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, decisionMode: true}
+	ri.GetProfile("")
+	ri.GetAsOf("2026-01-01")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lrespFiltered := ri.lint(ctx)
+
+	// The finding that is not yet effective is sorted last, and does not affect the decision.
+	if last := lrespFiltered[len(lrespFiltered)-1]; last.Code != "e_future" || last.NotYetEffective != "2027-03-15" {
+		t.Errorf("got %+v, want the not yet effective finding last", last)
+	} else if suffix := annotationSuffix(last); suffix != " (not yet effective: effective from 2027-03-15)" {
+		t.Errorf("got suffix %q", suffix)
+	}
+	if ri.decision.verdict != VERDICT_PASS || ri.decision.maxSeverity != linter.SEVERITY_WARNING {
+		t.Errorf("got %+v, want pass with max severity warning", ri.decision)
+	} else if ri.maxSeverities["fake"] != linter.SEVERITY_WARNING {
+		t.Errorf("got max severity %s, want warning", linter.SeverityString[ri.maxSeverities["fake"]])
+	}
+
+	// json2 reports the as-of date, and the finding that is not yet effective in a separate section.
+	jresp := ri.makeJSON2Response(lrespFiltered)
+	if jresp.AsOf != "2026-01-01T00:00:00Z" {
+		t.Errorf("got as_of %q", jresp.AsOf)
+	} else if len(jresp.NotYetEffective) != 1 || jresp.NotYetEffective[0].Linter != "fake" || jresp.NotYetEffective[0].EffectiveDate != "2027-03-15" {
+		t.Errorf("got not_yet_effective %+v", jresp.NotYetEffective)
+	} else if jresp.Summary["error"] != 0 || jresp.Summary["warning"] != 1 {
+		t.Errorf("got summary %v, want only the effective finding to be counted", jresp.Summary)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/pkimetal/pkimetal/linter"
 
-	"github.com/crtsh/ccadb_data"
 	"github.com/zmap/zcrypto/encoding/asn1"
 	"github.com/zmap/zcrypto/x509"
 	"github.com/zmap/zcrypto/x509/pkix"
@@ -57,6 +59,9 @@ var (
 	oidAttribute_jurisdictionCountryName asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 60, 2, 1, 3}
 	oidAttribute_markType                asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 53087, 1, 13}
 
+	// CABForum Certificate Policy OID arc.
+	oidPolicy_CABForum_arc asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 23, 140, 1}
+
 	// QCStatement OIDs.
 	oidQCStatement_etsiQcsQcCompliance    asn1.ObjectIdentifier = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 1}
 	oidQCStatement_etsiQcsQcCClegislation asn1.ObjectIdentifier = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 7}
@@ -152,6 +157,10 @@ func hasBIMIMarkTypeAttribute(subject pkix.Name) (bool, bool) {
 	return false, false
 }
 
+func hasPrecertificatePoison(cert *x509.Certificate) bool {
+	return slices.ContainsFunc(cert.Extensions, func(e pkix.Extension) bool { return e.Id.Equal(oidExtension_PrecertificatePoison) })
+}
+
 func isRootCertificate(cert *x509.Certificate) bool {
 	if cert.Version >= 3 && (!cert.BasicConstraintsValid || !cert.IsCA) {
 		return false
@@ -179,62 +188,145 @@ func hasUnsupportedSignatureAlgorithm(cert *x509.Certificate) bool {
 
 func (ri *RequestInfo) GetProfile(profileName string) bool {
 	// Determine the Profile ID (default = auto-detect).
+	ri.detectionReasons = nil
 	if profileName == "" {
 		ri.profileId = linter.AUTODETECT
 	} else {
-		ri.profileId = -1
-		for id, profile := range linter.AllProfiles {
-			if profile.Name == profileName {
-				ri.profileId = id
-				break
-			}
+		var ok bool
+		if ri.profileId, ok = linter.GetProfileId(profileName); !ok {
+			return false
 		}
-		if ri.profileId == -1 {
+
+		// CSRs and public keys can only be linted against CSR and public key profiles respectively, and vice versa.
+		baseProfileId := linter.BaseProfileId(ri.profileId)
+		if ri.profileId != linter.AUTODETECT && ((ri.endpoint == ENDPOINT_LINTCSR) != slices.Contains(linter.CsrProfileIDs, baseProfileId) || (ri.endpoint == ENDPOINT_LINTKEY) != slices.Contains(linter.PublicKeyProfileIDs, baseProfileId)) {
 			return false
 		}
 	}
 
-	// Perform profile autodetection, if necessary.
+	// Perform profile autodetection, if necessary.  Custom profiles take precedence over the built-in profiles.
 	if ri.profileId == linter.AUTODETECT {
-		switch ri.endpoint {
-		case ENDPOINT_LINTCRL, ENDPOINT_LINTTBSCRL:
-			ri.profileId = ri.detectCRLProfile()
-		case ENDPOINT_LINTOCSP, ENDPOINT_LINTTBSOCSP:
-			ri.profileId = linter.RFC6960_OCSPRESPONSE
-		case ENDPOINT_LINTCERT, ENDPOINT_LINTTBSCERT:
-			if isRootCertificate(ri.cert) {
-				ri.profileId = ri.detectRootCertificateProfile()
-			} else if ri.cert.BasicConstraintsValid && ri.cert.IsCA {
-				ri.profileId = ri.detectSubordinateCertificateProfile()
-			} else {
-				ri.profileId = ri.detectLeafCertificateProfile()
-			}
+		ri.autodetected = true
+		if id, ok := ri.detectCustomProfile(); ok {
+			ri.explain("Matches the autodetection criteria of custom profile %s", id)
+			ri.profileId = id
+		} else {
+			ri.profileId = ri.detectBuiltInProfile()
 		}
+		ri.explain("Selected %s", ri.profileId)
 	}
 
 	return true
 }
 
+// detectBuiltInProfile autodetects the built-in profile that best matches the input.
+func (ri *RequestInfo) detectBuiltInProfile() linter.ProfileId {
+	switch ri.endpoint {
+	case ENDPOINT_LINTCRL, ENDPOINT_LINTTBSCRL:
+		return ri.detectCRLProfile()
+	case ENDPOINT_LINTOCSP, ENDPOINT_LINTTBSOCSP:
+		ri.explain("OCSP response: only one profile applies")
+		return linter.RFC6960_OCSPRESPONSE
+	case ENDPOINT_LINTCSR:
+		ri.explain("CSR: only one profile applies")
+		return linter.RFC2986_CSR
+	case ENDPOINT_LINTKEY:
+		ri.explain("Public key: only one profile applies")
+		return linter.RFC5280_PUBLICKEY
+	case ENDPOINT_LINTCERT, ENDPOINT_LINTTBSCERT:
+		if isRootCertificate(ri.cert) {
+			ri.explain("Root certificate: self-issued CA certificate")
+			return ri.detectRootCertificateProfile()
+		} else if ri.cert.BasicConstraintsValid && ri.cert.IsCA {
+			ri.explain("Subordinate CA certificate: basicConstraints cA is TRUE")
+			return ri.detectSubordinateCertificateProfile()
+		}
+		ri.explain("Leaf certificate: not a CA certificate")
+		return ri.detectLeafCertificateProfile()
+	}
+	return linter.AUTODETECT
+}
+
+// explain appends a reason to the autodetection decision trail.
+func (ri *RequestInfo) explain(format string, args ...any) {
+	ri.detectionReasons = append(ri.detectionReasons, fmt.Sprintf(format, args...))
+}
+
+// explainPolicies records the certificate's CABForum policy OIDs.
+func (ri *RequestInfo) explainPolicies() {
+	var oids []string
+	for _, p := range ri.cert.PolicyIdentifiers {
+		if len(p) >= len(oidPolicy_CABForum_arc) && p[0:len(oidPolicy_CABForum_arc)].Equal(oidPolicy_CABForum_arc) {
+			oids = append(oids, p.String())
+		}
+	}
+	if len(oids) == 0 {
+		ri.explain("Certificate policies: no CABForum policy OIDs")
+	} else {
+		ri.explain("Certificate policies: CABForum policy OID(s) %s", strings.Join(oids, ", "))
+	}
+}
+
+// explainFlags records which of the named characteristics are present.
+func (ri *RequestInfo) explainFlags(what string, names []string, flags ...bool) {
+	var present []string
+	for i, flag := range flags {
+		if flag {
+			present = append(present, names[i])
+		}
+	}
+	if len(present) == 0 {
+		present = []string{"none"}
+	}
+	ri.explain("%s: %s", what, strings.Join(present, ", "))
+}
+
+// explainIssuerLookup records the outcome of an issuer registry or CCADB lookup.
+func (ri *RequestInfo) explainIssuerLookup(by, key string, ic *issuerCapabilities) {
+	switch {
+	case key == "":
+		ri.explain("Issuer lookup by %s: not possible", by)
+	case ic == nil:
+		ri.explain("Issuer lookup by %s %s: not found in the %s or %s", by, key, ISSUERSOURCE_REGISTRY, ISSUERSOURCE_CCADB)
+	default:
+		var capabilities []string
+		for _, c := range []struct {
+			name    string
+			capable bool
+		}{{"Root", ic.IsRoot}, {"TLS", ic.TlsCapable}, {"TLS EV", ic.TlsEvCapable}, {"S/MIME", ic.SmimeCapable}, {"Code Signing", ic.CodeSigningCapable}, {"VMC audit", ic.HasVMCAudit}} {
+			if c.capable {
+				capabilities = append(capabilities, c.name)
+			}
+		}
+		if len(capabilities) == 0 {
+			capabilities = []string{"none"}
+		}
+		ri.explain("Issuer lookup by %s %s: found in the %s; capabilities: %s", by, key, ic.Source, strings.Join(capabilities, ", "))
+	}
+}
+
 func (ri *RequestInfo) detectCRLProfile() linter.ProfileId {
-	// Use the Key Identifier from the CRL's AKI extension to lookup the issuer's capabilities in the CCADB data.
-	if rl, err := x509.ParseRevocationList(ri.decodedInput); err == nil {
-		if keyIdentifier := getBase64AKI(rl.Extensions); keyIdentifier != "" {
-			if ic := ccadb_data.GetIssuerCapabilitiesByKeyIdentifier(keyIdentifier); ic != nil {
-				// Infer the CRL profile based on the issuer's capabilities and CCADB record type.
-				if ic.CertificateRecordType == ccadb_data.CCADB_RECORD_ROOT {
-					if ic.TlsCapable {
-						return linter.TBR_ARL
-					} else {
-						return linter.RFC5280_ARL
-					}
-				} else {
-					if ic.TlsCapable {
-						return linter.TBR_CRL
-					} else {
-						return linter.RFC5280_CRL
-					}
-				}
+	// Use the Key Identifier from the CRL's AKI extension to lookup the issuer's capabilities in the issuer registry or
+	// the CCADB data.
+	if rl, err := x509.ParseRevocationList(ri.decodedInput); err != nil {
+		ri.explain("CRL could not be parsed")
+	} else if keyIdentifier := getBase64AKI(rl.Extensions); keyIdentifier == "" {
+		ri.explainIssuerLookup("AKI", "", nil)
+	} else if ic := lookupIssuerByKeyIdentifier(keyIdentifier); ic == nil {
+		ri.explainIssuerLookup("AKI", keyIdentifier, nil)
+	} else {
+		ri.explainIssuerLookup("AKI", keyIdentifier, ic)
+		// Infer the CRL profile based on the issuer's capabilities and whether or not it is a root CA.
+		if ic.TlsCapable {
+			if ic.IsRoot {
+				return linter.TBR_ARL
+			} else {
+				return linter.TBR_CRL
 			}
+		} else if id, ok := ri.issuerDefaultProfile(ic); ok {
+			return id
+		} else if ic.IsRoot {
+			return linter.RFC5280_ARL
 		}
 	}
 
@@ -242,8 +334,11 @@ func (ri *RequestInfo) detectCRLProfile() linter.ProfileId {
 }
 
 func (ri *RequestInfo) detectRootCertificateProfile() linter.ProfileId {
-	// Look for this root certificate's capabilities in the CCADB CSV data.
-	if ic := ccadb_data.GetCACertCapabilitiesBySHA256(sha256.Sum256(ri.decodedInput)); ic != nil {
+	// Look for this root certificate's capabilities in the issuer registry or the CCADB CSV data.
+	fingerprint := sha256.Sum256(ri.decodedInput)
+	ic := lookupCACertBySHA256(fingerprint, ri.cert.SubjectKeyId)
+	ri.explainIssuerLookup("SHA-256", fmt.Sprintf("%X", fingerprint), ic)
+	if ic != nil {
 		if ic.TlsEvCapable {
 			return linter.TEVG_ROOT_TLSSERVER
 		} else if ic.TlsCapable {
@@ -254,6 +349,8 @@ func (ri *RequestInfo) detectRootCertificateProfile() linter.ProfileId {
 			return linter.CSBR_ROOT_CODESIGNING
 		} else if ic.HasVMCAudit {
 			return linter.BIMIGROUP_ROOT_BIMI
+		} else if id, ok := ri.issuerDefaultProfile(ic); ok {
+			return id
 		}
 	}
 
@@ -265,11 +362,13 @@ func (ri *RequestInfo) detectSubordinateCertificateProfile() linter.ProfileId {
 	// CT is intended for the WebPKI, so the Precertificate Signing EKU implies TLS BR scope.
 	for _, eku := range ri.cert.UnknownExtKeyUsage {
 		if eku.Equal(oidEKU_PrecertificateSigning) {
+			ri.explain("EKUs: Precertificate Signing")
 			return linter.TBR_SUBORDINATE_PRECERTSIGNING
 		}
 	}
 
 	// Determine the subordinate certificate profile based on CABForum certificate policy OIDs.
+	ri.explainPolicies()
 	for _, p := range ri.cert.PolicyIdentifiers {
 		if p.Equal(oidPolicy_TLSServer_TBR_DV) || p.Equal(oidPolicy_TLSServer_TBR_OV) || p.Equal(oidPolicy_TLSServer_TBR_IV) {
 			return linter.TBR_SUBORDINATE_TLSSERVER
@@ -310,31 +409,38 @@ func (ri *RequestInfo) detectSubordinateCertificateProfile() linter.ProfileId {
 			hasBIMIEKU = true
 		}
 	}
+	ri.explainFlags("EKUs", []string{"Any/None", "Server Authentication", "Email Protection", "Code Signing", "Time Stamping", "BIMI"}, hasAnyOrNoEKU, hasServerAuthEKU, hasEmailProtectionEKU, hasCodeSigningEKU, hasTimeStampingEKU, hasBIMIEKU)
 
-	// Use the Key Identifier from the certificate's AKI extension to lookup the issuer's capabilities in the CCADB data.
-	if keyIdentifier := getBase64AKI(ri.cert.Extensions); keyIdentifier != "" {
-		if ic := ccadb_data.GetIssuerCapabilitiesByKeyIdentifier(keyIdentifier); ic != nil {
-			// Determine the subordinate certificate profile based on the issuer's capabilities and the certificate's EKUs.
-			if hasServerAuthEKU || hasAnyOrNoEKU {
-				if ic.TlsEvCapable {
-					return linter.TEVG_SUBORDINATE_TLSSERVER
-				} else if ic.TlsCapable {
-					return linter.TBR_SUBORDINATE_TLSSERVER
-				}
-			}
-			if (hasEmailProtectionEKU || hasAnyOrNoEKU) && ic.SmimeCapable {
-				return linter.SBR_SUBORDINATE_SMIME
-			}
-			if (hasCodeSigningEKU || hasAnyOrNoEKU) && ic.CodeSigningCapable {
-				return linter.CSBR_SUBORDINATE_CODESIGNING
-			}
-			if hasTimeStampingEKU { // The CCADB CSV data doesn't reveal timestamping capability, but the Timestamping EKU OID combined with presence in the CCADB is a strong indicator of CSBR scope.
-				return linter.CSBR_SUBORDINATE_TIMESTAMPING
-			}
-			if hasBIMIEKU {
-				return linter.BIMIGROUP_SUBORDINATE_BIMI
+	// Use the Key Identifier from the certificate's AKI extension to lookup the issuer's capabilities in the issuer registry or the CCADB data.
+	if keyIdentifier := getBase64AKI(ri.cert.Extensions); keyIdentifier == "" {
+		ri.explainIssuerLookup("AKI", "", nil)
+	} else if ic := lookupIssuerByKeyIdentifier(keyIdentifier); ic == nil {
+		ri.explainIssuerLookup("AKI", keyIdentifier, nil)
+	} else {
+		ri.explainIssuerLookup("AKI", keyIdentifier, ic)
+		// Determine the subordinate certificate profile based on the issuer's capabilities and the certificate's EKUs.
+		if hasServerAuthEKU || hasAnyOrNoEKU {
+			if ic.TlsEvCapable {
+				return linter.TEVG_SUBORDINATE_TLSSERVER
+			} else if ic.TlsCapable {
+				return linter.TBR_SUBORDINATE_TLSSERVER
 			}
 		}
+		if (hasEmailProtectionEKU || hasAnyOrNoEKU) && ic.SmimeCapable {
+			return linter.SBR_SUBORDINATE_SMIME
+		}
+		if (hasCodeSigningEKU || hasAnyOrNoEKU) && ic.CodeSigningCapable {
+			return linter.CSBR_SUBORDINATE_CODESIGNING
+		}
+		if hasTimeStampingEKU { // The CCADB CSV data doesn't reveal timestamping capability, but the Timestamping EKU OID combined with presence in the CCADB is a strong indicator of CSBR scope.
+			return linter.CSBR_SUBORDINATE_TIMESTAMPING
+		}
+		if hasBIMIEKU {
+			return linter.BIMIGROUP_SUBORDINATE_BIMI
+		}
+		if id, ok := ri.issuerDefaultProfile(ic); ok {
+			return id
+		}
 	}
 
 	return linter.RFC5280_SUBORDINATE
@@ -342,18 +448,16 @@ func (ri *RequestInfo) detectSubordinateCertificateProfile() linter.ProfileId {
 
 func (ri *RequestInfo) detectLeafCertificateProfile() linter.ProfileId {
 	// Determine if the certificate is a Precertificate.
-	isPrecertificate := false
-	for _, e := range ri.cert.Extensions {
-		if e.Id.Equal(oidExtension_PrecertificatePoison) {
-			isPrecertificate = true
-			break
-		}
-	}
+	isPrecertificate := hasPrecertificatePoison(ri.cert)
+
+	ri.explainFlags("Precertificate poison extension", []string{"present"}, isPrecertificate)
 
 	// Determine if the certificate has ETSI profile characteristics.
 	isQualified, isEidasQualified, isPSD2 := getQualifiedStatementInfo(ri.cert.Extensions)
+	ri.explainFlags("QCStatements", []string{"Qualified", "eIDAS Qualified", "PSD2"}, isQualified, isEidasQualified, isPSD2)
 
 	// Detect leaf profiles based on CABForum certificate policy OIDs.
+	ri.explainPolicies()
 	for _, p := range ri.cert.PolicyIdentifiers {
 		// Handle TLS BR and related ETSI TLS leaf profiles.
 		if isPrecertificate {
@@ -487,9 +591,11 @@ func (ri *RequestInfo) detectLeafCertificateProfile() linter.ProfileId {
 			hasBIMIEKU = true
 		}
 	}
+	ri.explainFlags("EKUs", []string{"Any/None", "Server Authentication", "Client Authentication", "Email Protection", "Code Signing", "Time Stamping", "OCSP Signing", "BIMI"}, hasAnyOrNoEKU, hasServerAuthEKU, hasClientAuthEKU, hasEmailProtectionEKU, hasCodeSigningEKU, hasTimeStampingEKU, hasOCSPSigningEKU, hasBIMIEKU)
 
 	// Handle non-CABForum ETSI TLS leaf profiles.
 	isNaturalPerson := hasAnyNaturalPersonAttribute(ri.cert.Subject)
+	ri.explainFlags("Subject natural person attributes", []string{"present"}, isNaturalPerson)
 	if hasServerAuthEKU {
 		if isNaturalPerson {
 			if isPrecertificate {
@@ -522,71 +628,77 @@ func (ri *RequestInfo) detectLeafCertificateProfile() linter.ProfileId {
 		}
 	}
 
-	// Use the Key Identifier in the certificate's AKI extension to lookup the issuer's capabilities in the CCADB data.
+	// Use the Key Identifier in the certificate's AKI extension to lookup the issuer's capabilities in the issuer registry or the CCADB data.
 	// This is useful to determine TLS BR and EVCS scope, since older versions of those documents did not require CABForum policy OIDs.
-	if keyIdentifier := getBase64AKI(ri.cert.Extensions); keyIdentifier != "" {
-		if ic := ccadb_data.GetIssuerCapabilitiesByKeyIdentifier(keyIdentifier); ic != nil {
-			// Determine the leaf certificate profile based on the issuer's capabilities and the certificate's EKUs.
-			if hasServerAuthEKU || hasAnyOrNoEKU {
-				if ic.TlsEvCapable {
+	if keyIdentifier := getBase64AKI(ri.cert.Extensions); keyIdentifier == "" {
+		ri.explainIssuerLookup("AKI", "", nil)
+	} else if ic := lookupIssuerByKeyIdentifier(keyIdentifier); ic == nil {
+		ri.explainIssuerLookup("AKI", keyIdentifier, nil)
+	} else {
+		ri.explainIssuerLookup("AKI", keyIdentifier, ic)
+		// Determine the leaf certificate profile based on the issuer's capabilities and the certificate's EKUs.
+		if hasServerAuthEKU || hasAnyOrNoEKU {
+			if ic.TlsEvCapable {
+				if isPrecertificate {
+					return linter.TEVG_LEAF_TLSSERVER_EV_PRECERTIFICATE
+				} else {
+					return linter.TEVG_LEAF_TLSSERVER_EV
+				}
+			} else if ic.TlsCapable {
+				if isNaturalPerson {
 					if isPrecertificate {
-						return linter.TEVG_LEAF_TLSSERVER_EV_PRECERTIFICATE
+						return linter.TBR_LEAF_TLSSERVER_IV_PRECERTIFICATE
 					} else {
-						return linter.TEVG_LEAF_TLSSERVER_EV
+						return linter.TBR_LEAF_TLSSERVER_IV
 					}
-				} else if ic.TlsCapable {
-					if isNaturalPerson {
-						if isPrecertificate {
-							return linter.TBR_LEAF_TLSSERVER_IV_PRECERTIFICATE
-						} else {
-							return linter.TBR_LEAF_TLSSERVER_IV
-						}
-					} else if hasOrganizationNameAttribute(ri.cert.Subject) {
-						if isPrecertificate {
-							return linter.TBR_LEAF_TLSSERVER_OV_PRECERTIFICATE
-						} else {
-							return linter.TBR_LEAF_TLSSERVER_OV
-						}
+				} else if hasOrganizationNameAttribute(ri.cert.Subject) {
+					if isPrecertificate {
+						return linter.TBR_LEAF_TLSSERVER_OV_PRECERTIFICATE
 					} else {
-						if isPrecertificate {
-							return linter.TBR_LEAF_TLSSERVER_DV_PRECERTIFICATE
-						} else {
-							return linter.TBR_LEAF_TLSSERVER_DV
-						}
+						return linter.TBR_LEAF_TLSSERVER_OV
+					}
+				} else {
+					if isPrecertificate {
+						return linter.TBR_LEAF_TLSSERVER_DV_PRECERTIFICATE
+					} else {
+						return linter.TBR_LEAF_TLSSERVER_DV
 					}
 				}
 			}
-			if hasOCSPSigningEKU && ic.TlsCapable {
-				return linter.TBR_LEAF_OCSPSIGNING
-			}
-			if (hasCodeSigningEKU || hasAnyOrNoEKU) && ic.CodeSigningCapable {
-				if hasJurisdictionCountryNameAttribute(ri.cert.Subject) {
-					return linter.CSBR_LEAF_CODESIGNING_EV
-				} else if hasOrganizationNameAttribute(ri.cert.Subject) {
-					return linter.CSBR_LEAF_CODESIGNING_OV
-				}
-			}
-			if hasTimeStampingEKU { // The CCADB CSV data doesn't reveal timestamping capability, but the Timestamping EKU OID combined with the issuer's presence in the CCADB is a strong indicator of CSBR scope.
-				return linter.CSBR_LEAF_TIMESTAMPING
+		}
+		if hasOCSPSigningEKU && ic.TlsCapable {
+			return linter.TBR_LEAF_OCSPSIGNING
+		}
+		if (hasCodeSigningEKU || hasAnyOrNoEKU) && ic.CodeSigningCapable {
+			if hasJurisdictionCountryNameAttribute(ri.cert.Subject) {
+				return linter.CSBR_LEAF_CODESIGNING_EV
+			} else if hasOrganizationNameAttribute(ri.cert.Subject) {
+				return linter.CSBR_LEAF_CODESIGNING_OV
 			}
-			if hasBIMIEKU {
-				if isMarkCertificate, isVerifiedMarkCertificate := hasBIMIMarkTypeAttribute(ri.cert.Subject); isMarkCertificate {
-					if isVerifiedMarkCertificate {
-						if isPrecertificate {
-							return linter.BIMIGROUP_LEAF_VERIFIEDMARK_PRECERTIFICATE
-						} else {
-							return linter.BIMIGROUP_LEAF_VERIFIEDMARK
-						}
+		}
+		if hasTimeStampingEKU { // The CCADB CSV data doesn't reveal timestamping capability, but the Timestamping EKU OID combined with the issuer's presence in the CCADB is a strong indicator of CSBR scope.
+			return linter.CSBR_LEAF_TIMESTAMPING
+		}
+		if hasBIMIEKU {
+			if isMarkCertificate, isVerifiedMarkCertificate := hasBIMIMarkTypeAttribute(ri.cert.Subject); isMarkCertificate {
+				if isVerifiedMarkCertificate {
+					if isPrecertificate {
+						return linter.BIMIGROUP_LEAF_VERIFIEDMARK_PRECERTIFICATE
 					} else {
-						if isPrecertificate {
-							return linter.BIMIGROUP_LEAF_COMMONMARK_PRECERTIFICATE
-						} else {
-							return linter.BIMIGROUP_LEAF_COMMONMARK
-						}
+						return linter.BIMIGROUP_LEAF_VERIFIEDMARK
+					}
+				} else {
+					if isPrecertificate {
+						return linter.BIMIGROUP_LEAF_COMMONMARK_PRECERTIFICATE
+					} else {
+						return linter.BIMIGROUP_LEAF_COMMONMARK
 					}
 				}
 			}
 		}
+		if id, ok := ri.issuerDefaultProfile(ic); ok {
+			return id
+		}
 	}
 
 	// Use the certificate's EKUs to determine RFC5280 leaf profiles.
@@ -1,6 +1,7 @@
 package request
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/pkimetal/pkimetal/linter"
@@ -35,6 +36,42 @@ func TestGetProfile_CRLAutodetectDefault(t *testing.T) {
 	}
 }
 
+func TestGetProfile_CSR(t *testing.T) {
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCSR}
+	if !ri.GetProfile("") || ri.profileId != linter.RFC2986_CSR {
+		t.Errorf("autodetect: got profileId %d, want RFC2986_CSR", ri.profileId)
+	}
+	if !ri.GetProfile("rfc2986_csr") || ri.profileId != linter.RFC2986_CSR {
+		t.Errorf("rfc2986_csr: got profileId %d, want RFC2986_CSR", ri.profileId)
+	}
+	if ri.GetProfile("rfc5280_leaf") {
+		t.Error("expected false for a certificate profile on the CSR endpoint")
+	}
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCERT}
+	if ri.GetProfile("rfc2986_csr") {
+		t.Error("expected false for the CSR profile on the certificate endpoint")
+	}
+}
+
+func TestGetProfile_PublicKey(t *testing.T) {
+	ri := RequestInfo{endpoint: ENDPOINT_LINTKEY}
+	if !ri.GetProfile("") || ri.profileId != linter.RFC5280_PUBLICKEY {
+		t.Errorf("autodetect: got profileId %d, want RFC5280_PUBLICKEY", ri.profileId)
+	}
+	if !ri.GetProfile("rfc5280_publickey") || ri.profileId != linter.RFC5280_PUBLICKEY {
+		t.Errorf("rfc5280_publickey: got profileId %d, want RFC5280_PUBLICKEY", ri.profileId)
+	}
+	if ri.GetProfile("rfc2986_csr") {
+		t.Error("expected false for the CSR profile on the public key endpoint")
+	}
+	for _, endpoint := range []Endpoint{ENDPOINT_LINTCERT, ENDPOINT_LINTCSR} {
+		ri = RequestInfo{endpoint: endpoint}
+		if ri.GetProfile("rfc5280_publickey") {
+			t.Errorf("endpoint %d: expected false for the public key profile", endpoint)
+		}
+	}
+}
+
 // certificateFixtures maps each certificate fixture to its expected auto-detected
 // profile and whether it is a precertificate (i.e. carries the CT precertificate
 // poison extension).
@@ -99,3 +136,24 @@ func hasPoisonExtension(cert *x509.Certificate) bool {
 	}
 	return false
 }
+
+func TestGetProfile_DetectionReasons(t *testing.T) {
+	cert, err := x509.ParseCertificate(testcaseDER(t, "tls_ov_certificate.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+	if !ri.GetProfile("") {
+		t.Fatal("expected a profile to be autodetected")
+	}
+	for _, want := range []string{"Leaf certificate: not a CA certificate", "Precertificate poison extension: none", "Certificate policies: CABForum policy OID(s) 2.23.140.1.2.2", "Selected tbr_leaf_tlsserver_ov"} {
+		if !slices.Contains(ri.detectionReasons, want) {
+			t.Errorf("got %q, want it to contain %q", ri.detectionReasons, want)
+		}
+	}
+
+	// An explicitly requested profile has no decision trail.
+	if !ri.GetProfile("rfc5280_leaf") || ri.detectionReasons != nil {
+		t.Errorf("got %q, want no reasons", ri.detectionReasons)
+	}
+}
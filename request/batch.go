@@ -0,0 +1,265 @@
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/health"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+
+	"go.uber.org/zap"
+)
+
+type batchItem struct {
+	Id       string `json:"id"`
+	Type     string `json:"type"`     // POST endpoint name, with or without the "lint" prefix (e.g., "cert", "tbscrl", "lintocsp").
+	Input    string `json:"input"`    // PEM or base64-encoded input.
+	Issuer   string `json:"issuer"`   // Optional PEM or base64-encoded issuer certificate (or PEM chain), for "cert" items.
+	Profile  string `json:"profile"`  // Optional (default = autodetect).
+	Severity string `json:"severity"` // Optional (default = the batch request's "severity" parameter).
+	AsOf     string `json:"asof"`     // Optional (default = the batch request's "asof" parameter).
+}
+
+type BatchResult struct {
+	Id      string
+	Results []LintResult
+}
+
+func BatchPOST(fhctx *fasthttp.RequestCtx) int {
+	status := fasthttp.StatusBadRequest
+
+	// This deadline only covers validating the batch.  The items are linted whilst the response is streamed, each
+	// subject to its own deadline.
+	ctxWithDeadline, cancel := context.WithDeadline(context.Background(), fhctx.Time().Add(time.Duration(config.Config.Server.RequestTimeout)))
+	defer cancel()
+
+	doneChan := make(chan int, 1)
+	go func() {
+		var template RequestInfo
+		var items []batchItem
+		var isNDJSON, ok bool
+		var retryAfter time.Duration
+		admitted := true
+		var err error
+		var errorMessage string
+		if requestBody := fhctx.Request.Body(); len(requestBody) == 0 {
+			errorMessage = "Empty request body"
+		} else if items, isNDJSON, err = parseBatchItems(requestBody); err != nil {
+			errorMessage = "Unrecognised batch input"
+		} else if len(items) == 0 {
+			errorMessage = "Empty batch"
+		} else if template.minimumSeverity, ok = linter.Severity[paramS(fhctx, "severity")]; !ok {
+			errorMessage = "Unrecognised severity"
+		} else if template.checksAdded, err = linter.ParseCheckSelection(paramS(fhctx, "include")); err != nil {
+			errorMessage = "Unrecognised check inclusion"
+		} else if template.checksDisabled, err = linter.ParseCheckSelection(paramS(fhctx, "exclude")); err != nil {
+			errorMessage = "Unrecognised check exclusion"
+		} else if _, _, err = parseAsOf(paramS(fhctx, "asof")); err != nil {
+			errorMessage = "Unrecognised asof"
+		} else if template.priority, err = getPriority(fhctx, linter.PRIORITY_BULK); err != nil {
+			errorMessage = "Unrecognised priority"
+		} else if retryAfter, admitted = admitLinters(nil, template.priority); !admitted {
+			errorMessage = ADMISSION_REJECTED
+		} else {
+			for i := range items {
+				if items[i].AsOf == "" {
+					items[i].AsOf = paramS(fhctx, "asof")
+				}
+			}
+		}
+
+		// Add Cross-Origin Resource Sharing (CORS) response header.
+		fhctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+
+		if errorMessage == "" {
+			logger.SetDetails(fhctx, zap.InfoLevel, "Batch Linting Request", nil, []zap.Field{
+				zap.Int("num_items", len(items)),
+			})
+			status = sendBatchResponse(fhctx, items, &template, isNDJSON)
+		} else {
+			logger.SetDetails(fhctx, zap.InfoLevel, "Batch Linting Request with Error", fmt.Errorf("%s", errorMessage), []zap.Field{
+				zap.Error(err),
+			})
+			fhctx.SetContentType("text/plain; charset=UTF-8")
+			fhctx.SetBodyString(errorMessage)
+			if !admitted {
+				status = setTooManyRequests(fhctx, retryAfter, template.priority)
+			}
+		}
+		fhctx.SetStatusCode(status)
+		doneChan <- 0
+	}()
+
+	return health.CompleteRequest(ctxWithDeadline, doneChan)
+}
+
+// parseBatchItems decodes either a JSON array of batch items or a sequence of
+// newline-delimited JSON (NDJSON) batch items.
+func parseBatchItems(body []byte) (items []batchItem, isNDJSON bool, err error) {
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &items)
+		return
+	}
+
+	isNDJSON = true
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var item batchItem
+		if err = decoder.Decode(&item); err == io.EOF {
+			return items, isNDJSON, nil
+		} else if err != nil {
+			return
+		}
+		items = append(items, item)
+	}
+}
+
+// lintBatch lints each batch item, running up to the configured number of items concurrently.  Each item is subject
+// to its own request deadline.  itemDone is called with each item's result, in the same order as the items, as soon as
+// that item and every item before it have been linted.
+func lintBatch(ctx context.Context, items []batchItem, template *RequestInfo, itemDone func(BatchResult)) {
+	results := make([]chan BatchResult, len(items))
+	for i := range results {
+		results[i] = make(chan BatchResult, 1)
+	}
+
+	go func() {
+		semaphore := make(chan struct{}, max(config.Config.Server.BatchConcurrency, 1))
+		for i := range items {
+			semaphore <- struct{}{}
+			go func(i int) {
+				defer func() { <-semaphore }()
+				itemCtx, cancel := context.WithTimeout(ctx, time.Duration(config.Config.Server.RequestTimeout))
+				defer cancel()
+				results[i] <- BatchResult{Id: items[i].Id, Results: lintBatchItem(itemCtx, &items[i], template)}
+			}(i)
+		}
+	}()
+
+	for i := range results {
+		itemDone(<-results[i])
+	}
+}
+
+func lintBatchItem(ctx context.Context, item *batchItem, template *RequestInfo) []LintResult {
+	var errorMessage string
+	var ok bool
+	ri := RequestInfo{
+		minimumSeverity: template.minimumSeverity,
+		checksAdded:     template.checksAdded,
+		checksDisabled:  template.checksDisabled,
+		priority:        template.priority,
+	}
+	if ctx.Err() != nil {
+		errorMessage = "Batch request timed out"
+	} else if !ri.GetPOSTEndpoint("lint" + strings.TrimPrefix(strings.ToLower(item.Type), "lint")) {
+		errorMessage = "Unrecognised type"
+	} else if item.Input == "" {
+		errorMessage = "Empty input"
+	} else if ri.b64Input = []byte(item.Input); ri.parseInput() != nil {
+		errorMessage = "Unrecognised input"
+	} else if ri.parseIssuerInput([]byte(item.Issuer)) != nil {
+		errorMessage = "Unrecognised issuer"
+	} else if !ri.GetProfile(item.Profile) {
+		errorMessage = "Unrecognised profile"
+	} else if ri.GetAsOf(item.AsOf) != nil {
+		errorMessage = "Unrecognised asof"
+	} else if item.Severity != "" {
+		if ri.minimumSeverity, ok = linter.Severity[item.Severity]; !ok {
+			errorMessage = "Unrecognised severity"
+		}
+	}
+
+	if errorMessage != "" {
+		return []LintResult{{
+			Linter:   linter.PKIMETAL_NAME,
+			Finding:  errorMessage,
+			Severity: linter.SeverityString[linter.SEVERITY_FATAL],
+		}}
+	}
+
+	lresp := ri.lint(ctx)
+	if slices.ContainsFunc(ri.linterReports, func(report linterReport) bool { return report.Status == LINTERSTATUS_TIMEDOUT }) {
+		// Report the item's partial results, and that they are incomplete.
+		lresp = append(lresp, LintResult{
+			Linter:   linter.PKIMETAL_NAME,
+			Finding:  "Batch item timed out",
+			Severity: linter.SeverityString[linter.SEVERITY_FATAL],
+		})
+	} else if lresp == nil {
+		return []LintResult{}
+	}
+	return lresp
+}
+
+// sendBatchResponse arranges for the batch items to be linted whilst the response is streamed, as NDJSON (one line
+// per batch item) or as a JSON array, matching the request.  Each item's result is flushed as soon as it (and every
+// item before it) has been linted, so the results of items that have finished are never lost if a later item runs
+// over.
+func sendBatchResponse(fhctx *fasthttp.RequestCtx, items []batchItem, template *RequestInfo, isNDJSON bool) int {
+	if isNDJSON {
+		fhctx.SetContentType("application/x-ndjson; charset=UTF-8")
+	} else {
+		fhctx.SetContentType("application/json; charset=UTF-8")
+	}
+
+	// The stream writer runs after the request handler has returned, so it needs its own deadline, which bounds the
+	// whole batch.
+	deadline := fhctx.Time().Add(time.Duration(config.Config.Server.BatchRequestTimeout))
+	fhctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		writeBatch(ctx, cancel, w, items, template, isNDJSON)
+	})
+
+	return fasthttp.StatusOK
+}
+
+// writeBatch lints the batch items, writing and flushing each item's result to w as it goes.  If w cannot be written
+// (e.g., because the client has gone away), cancel is called so that linting is abandoned.
+func writeBatch(ctx context.Context, cancel context.CancelFunc, w *bufio.Writer, items []batchItem, template *RequestInfo, isNDJSON bool) {
+	prefix, separator, suffix := "[", ",", "]\n"
+	if isNDJSON {
+		prefix, separator, suffix = "", "\n", "\n"
+	} else if config.Config.Response.JsonPrettyPrint {
+		prefix, separator, suffix = "[\n  ", ",\n  ", "\n]\n"
+	}
+	write := func(s string) {
+		if _, err := w.WriteString(s); err != nil {
+			cancel()
+		} else if err = w.Flush(); err != nil {
+			cancel()
+		}
+	}
+
+	n := 0
+	lintBatch(ctx, items, template, func(br BatchResult) {
+		var data []byte
+		var err error
+		if !isNDJSON && config.Config.Response.JsonPrettyPrint {
+			data, err = json.MarshalIndentWithOption(br, "  ", "  ", json.DisableHTMLEscape())
+		} else {
+			data, err = json.MarshalWithOption(br, json.DisableHTMLEscape())
+		}
+		if err != nil {
+			cancel()
+			return
+		} else if n++; n == 1 {
+			write(prefix + string(data))
+		} else {
+			write(separator + string(data))
+		}
+	})
+	write(suffix)
+}
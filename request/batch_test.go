@@ -0,0 +1,214 @@
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/valyala/fasthttp"
+)
+
+// --- parseBatchItems ---
+
+func TestParseBatchItems_JSONArray(t *testing.T) {
+	items, isNDJSON, err := parseBatchItems([]byte(` [{"id":"a","type":"cert","input":"x"},{"id":"b","type":"crl","input":"y","profile":"p","severity":"error"}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if isNDJSON {
+		t.Errorf("got isNDJSON = true, want false")
+	}
+	want := []batchItem{
+		{Id: "a", Type: "cert", Input: "x"},
+		{Id: "b", Type: "crl", Input: "y", Profile: "p", Severity: "error"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("item %d: got %+v, want %+v", i, items[i], want[i])
+		}
+	}
+}
+
+func TestParseBatchItems_NDJSON(t *testing.T) {
+	items, isNDJSON, err := parseBatchItems([]byte("{\"id\":\"a\",\"type\":\"cert\",\"input\":\"x\"}\n\n{\"id\":\"b\",\"type\":\"ocsp\",\"input\":\"y\"}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !isNDJSON {
+		t.Errorf("got isNDJSON = false, want true")
+	}
+	if len(items) != 2 || items[0].Id != "a" || items[1].Id != "b" || items[1].Type != "ocsp" {
+		t.Errorf("got %+v", items)
+	}
+}
+
+func TestParseBatchItems_Invalid(t *testing.T) {
+	for _, body := range []string{`[{"id":`, "{\"id\":\"a\"}\nnot json\n"} {
+		if _, _, err := parseBatchItems([]byte(body)); err == nil {
+			t.Errorf("%q: expected an error", body)
+		}
+	}
+}
+
+// --- lintBatchItem ---
+
+func TestLintBatchItem_Errors(t *testing.T) {
+	template := RequestInfo{minimumSeverity: linter.SEVERITY_META}
+	cases := []struct {
+		name string
+		item batchItem
+		want string
+	}{
+		{"unknown type", batchItem{Type: "csv", Input: "AAAA"}, "Unrecognised type"},
+		{"batch type", batchItem{Type: "batch", Input: "AAAA"}, "Unrecognised type"},
+		{"empty input", batchItem{Type: "cert"}, "Empty input"},
+		{"bad input", batchItem{Type: "cert", Input: "AAAA"}, "Unrecognised input"},
+		{"bad profile", batchItem{Type: "lintcert", Input: testcasePEM(t, "tls_ov_certificate.crt"), Profile: "no_such_profile"}, "Unrecognised profile"},
+		{"bad severity", batchItem{Type: "cert", Input: testcasePEM(t, "tls_ov_certificate.crt"), Severity: "loud"}, "Unrecognised severity"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			results := lintBatchItem(context.Background(), &tc.item, &template)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			} else if results[0].Finding != tc.want || results[0].Severity != "fatal" || results[0].Linter != linter.PKIMETAL_NAME {
+				t.Errorf("got %+v, want fatal %q", results[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestLintBatchItem_TimedOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := lintBatchItem(ctx, &batchItem{Type: "cert", Input: testcasePEM(t, "tls_ov_certificate.crt")}, &RequestInfo{})
+	if len(results) != 1 || results[0].Finding != "Batch request timed out" {
+		t.Errorf("got %+v", results)
+	}
+}
+
+// --- lintBatch ---
+
+func TestLintBatch_PreservesOrderAndIds(t *testing.T) {
+	items := []batchItem{
+		{Id: "good", Type: "cert", Input: testcasePEM(t, "tls_ov_certificate.crt")},
+		{Id: "bad", Type: "cert", Input: "AAAA"},
+		{Id: "quiet", Type: "cert", Input: testcasePEM(t, "tls_ov_certificate.crt"), Severity: "fatal"},
+	}
+	var bresp []BatchResult
+	lintBatch(context.Background(), items, &RequestInfo{minimumSeverity: linter.SEVERITY_META}, func(br BatchResult) { bresp = append(bresp, br) })
+	if len(bresp) != len(items) {
+		t.Fatalf("got %d results, want %d", len(bresp), len(items))
+	}
+	for i := range items {
+		if bresp[i].Id != items[i].Id {
+			t.Errorf("result %d: got id %q, want %q", i, bresp[i].Id, items[i].Id)
+		}
+	}
+	if len(bresp[0].Results) == 0 || !strings.HasPrefix(bresp[0].Results[0].Finding, "Profile: ") {
+		t.Errorf("good: got %+v, want a profile meta result", bresp[0].Results)
+	}
+	if len(bresp[1].Results) != 1 || bresp[1].Results[0].Finding != "Unrecognised input" {
+		t.Errorf("bad: got %+v", bresp[1].Results)
+	}
+	if bresp[2].Results == nil || len(bresp[2].Results) != 0 {
+		t.Errorf("quiet: got %#v, want an empty non-nil slice", bresp[2].Results)
+	}
+}
+
+func TestLintBatch_ItemTimedOut(t *testing.T) {
+	saved := config.Config.Server.RequestTimeout
+	t.Cleanup(func() { config.Config.Server.RequestTimeout = saved })
+	config.Config.Server.RequestTimeout = 50 * time.Millisecond
+	savedLinters := linter.Linters
+	t.Cleanup(func() { linter.Linters = savedLinters })
+	linter.Linters = linter.LinterSlice{{Name: "stuck", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 2)}} // Never responds.
+
+	// The item that runs over its own deadline is reported as timed out, without affecting the other items.
+	items := []batchItem{
+		{Id: "slow", Type: "cert", Input: testcasePEM(t, "tls_ov_certificate.crt")},
+		{Id: "bad", Type: "cert", Input: "AAAA"},
+	}
+	var bresp []BatchResult
+	lintBatch(context.Background(), items, &RequestInfo{minimumSeverity: linter.SEVERITY_META}, func(br BatchResult) { bresp = append(bresp, br) })
+	if len(bresp) != 2 {
+		t.Fatalf("got %+v", bresp)
+	} else if results := bresp[0].Results; len(results) < 2 || !strings.HasPrefix(results[0].Finding, "Profile: ") || results[len(results)-1].Finding != "Batch item timed out" || results[len(results)-1].Severity != "fatal" {
+		t.Errorf("slow: got %+v, want its partial results and a timed out result", results)
+	} else if results = bresp[1].Results; len(results) != 1 || results[0].Finding != "Unrecognised input" {
+		t.Errorf("bad: got %+v", results)
+	}
+}
+
+// --- writeBatch ---
+
+func TestWriteBatch(t *testing.T) {
+	items := []batchItem{
+		{Id: "a", Type: "cert", Input: "AAAA"},
+		{Id: "b", Type: "cert", Input: testcasePEM(t, "tls_ov_certificate.crt"), Severity: "fatal"},
+	}
+	template := RequestInfo{minimumSeverity: linter.SEVERITY_META}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var buf bytes.Buffer
+	writeBatch(ctx, cancel, bufio.NewWriter(&buf), items, &template, false)
+	var got []BatchResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("response is not valid JSON: %v: %q", err, buf.String())
+	} else if len(got) != 2 || got[0].Id != "a" || len(got[0].Results) != 1 || got[1].Id != "b" || got[1].Results == nil {
+		t.Errorf("got %+v", got)
+	}
+
+	buf.Reset()
+	writeBatch(ctx, cancel, bufio.NewWriter(&buf), items, &template, true)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	for i, line := range lines {
+		var br BatchResult
+		if err := json.Unmarshal([]byte(line), &br); err != nil {
+			t.Errorf("line %d is not valid JSON: %v", i, err)
+		} else if br.Id != items[i].Id {
+			t.Errorf("line %d: got id %q, want %q", i, br.Id, items[i].Id)
+		}
+	}
+}
+
+func TestWriteBatch_PrettyPrint(t *testing.T) {
+	saved := config.Config.Response.JsonPrettyPrint
+	t.Cleanup(func() { config.Config.Response.JsonPrettyPrint = saved })
+	config.Config.Response.JsonPrettyPrint = true
+
+	items := []batchItem{{Id: "a", Type: "cert", Input: "AAAA"}, {Id: "b", Type: "cert", Input: "AAAA"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var buf bytes.Buffer
+	writeBatch(ctx, cancel, bufio.NewWriter(&buf), items, &RequestInfo{}, false)
+	var got []BatchResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got) != 2 {
+		t.Fatalf("got %+v, %v: %q", got, err, buf.String())
+	} else if !strings.HasPrefix(buf.String(), "[\n  {\n    \"Id\": \"a\",") || !strings.HasSuffix(buf.String(), "\n  }\n]\n") {
+		t.Errorf("got %q, want it to be indented", buf.String())
+	}
+}
+
+func TestSendBatchResponse(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	if status := sendBatchResponse(ctx, []batchItem{{Id: "a", Type: "cert", Input: "AAAA"}}, &RequestInfo{}, true); status != fasthttp.StatusOK {
+		t.Errorf("got status %d, want %d", status, fasthttp.StatusOK)
+	} else if ct := string(ctx.Response.Header.ContentType()); !strings.Contains(ct, "application/x-ndjson") {
+		t.Errorf("got content type %q, want application/x-ndjson", ct)
+	} else if !ctx.Response.IsBodyStream() {
+		t.Error("want the response to be streamed")
+	}
+}
@@ -0,0 +1,321 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// A CCADB snapshot's file name records when it was published, in the same format as the ccadb_data module's versions
+// (e.g., ccadb-20260820.193505.csv).  If a data snapshot public key is configured, each snapshot must be accompanied
+// by a detached signature (e.g., ccadb-20260820.193505.csv.sig).
+const (
+	CCADB_SNAPSHOT_PREFIX     = "ccadb-"
+	CCADB_SNAPSHOT_SUFFIX     = ".csv"
+	CCADB_DATE_LAYOUT         = "20060102.150405"
+	SNAPSHOT_SIGNATURE_SUFFIX = ".sig"
+)
+
+// ccadbSnapshot is a CCADB CSV snapshot, loaded from the data directory and indexed for lookups.  It supersedes the
+// CCADB data that is compiled into pkimetal.
+type ccadbSnapshot struct {
+	file            string
+	published       time.Time
+	byKeyIdentifier map[string]*issuerCapabilities // Base64-encoded Subject Key Identifier.
+	bySHA256        map[[sha256.Size]byte]*issuerCapabilities
+}
+
+var (
+	activeCCADBSnapshot atomic.Pointer[ccadbSnapshot]
+	snapshotPublicKey   crypto.PublicKey // If set, data snapshots must be signed with the corresponding private key.
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: config.ApplicationNamespace,
+		Subsystem: "ccadb",
+		Name:      "snapshot_age_seconds",
+		Help:      "Age in seconds of the active CCADB snapshot, since it was published (0 if the CCADB data compiled into pkimetal is in use).",
+	}, func() float64 {
+		if snapshot := activeCCADBSnapshot.Load(); snapshot != nil {
+			return time.Since(snapshot.published).Seconds()
+		}
+		return 0
+	})
+	ccadbSnapshotReloadFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: config.ApplicationNamespace,
+		Subsystem: "ccadb",
+		Name:      "snapshot_reload_failures_total",
+		Help:      "Number of times that a CCADB snapshot failed format or signature validation, and so was not loaded.",
+	})
+)
+
+// The CCADB CSV columns that a snapshot must contain, and the optional columns.
+const (
+	CCADBCOLUMN_RECORDTYPE         = "Certificate Record Type"
+	CCADBCOLUMN_SHA256             = "SHA-256 Fingerprint"
+	CCADBCOLUMN_SKI                = "Subject Key Identifier"
+	CCADBCOLUMN_TLSCAPABLE         = "TLS Capable"
+	CCADBCOLUMN_TLSEVCAPABLE       = "TLS EV Capable"
+	CCADBCOLUMN_SMIMECAPABLE       = "S/MIME Capable"
+	CCADBCOLUMN_CODESIGNINGCAPABLE = "Code Signing Capable"
+	CCADBCOLUMN_VMCCAPABLE         = "VMC Capable" // Optional.
+)
+
+var ccadbRequiredColumns = []string{CCADBCOLUMN_RECORDTYPE, CCADBCOLUMN_SHA256, CCADBCOLUMN_SKI, CCADBCOLUMN_TLSCAPABLE, CCADBCOLUMN_TLSEVCAPABLE, CCADBCOLUMN_SMIMECAPABLE, CCADBCOLUMN_CODESIGNINGCAPABLE}
+
+func init() {
+	var err error
+	if snapshotPublicKey, err = loadSnapshotPublicKey(config.Config.Data.PublicKeyFile); err != nil {
+		panic(fmt.Sprintf("Invalid data snapshot public key: %v", err))
+	}
+
+	// An invalid snapshot is fatal at startup, but not when reloading.
+	if _, err = reloadCCADBSnapshot(config.Config.Data.Directory); err != nil {
+		panic(fmt.Sprintf("Invalid CCADB snapshot: %v", err))
+	}
+}
+
+// loadSnapshotPublicKey loads the PEM-encoded public key (ECDSA, Ed25519, or RSA) that verifies data snapshot
+// signatures.
+func loadSnapshotPublicKey(file string) (crypto.PublicKey, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("%s: unrecognised PEM public key", file)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	switch pub.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("%s: unrecognised public key type: %T", file, pub)
+	}
+}
+
+// verifySnapshotSignature verifies a data snapshot's detached signature, if a data snapshot public key is configured.
+// ECDSA and RSA (PKCS #1 v1.5) signatures are over the SHA-256 digest of the snapshot.
+func verifySnapshotSignature(file string, data []byte) error {
+	if snapshotPublicKey == nil {
+		return nil
+	}
+	sig, err := os.ReadFile(file + SNAPSHOT_SIGNATURE_SUFFIX)
+	if err != nil {
+		return fmt.Errorf("missing signature: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	var ok bool
+	switch pub := snapshotPublicKey.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, data, sig)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// parseCCADBSnapshot validates and indexes a CCADB CSV snapshot.
+func parseCCADBSnapshot(r io.Reader) (*ccadbSnapshot, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unrecognised header: %w", err)
+	}
+	column := make(map[string]int, len(header))
+	for i, name := range header {
+		column[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i // CCADB reports may begin with a BOM.
+	}
+	for _, name := range ccadbRequiredColumns {
+		if _, ok := column[name]; !ok {
+			return nil, fmt.Errorf("missing column: %s", name)
+		}
+	}
+
+	snapshot := &ccadbSnapshot{
+		byKeyIdentifier: make(map[string]*issuerCapabilities),
+		bySHA256:        make(map[[sha256.Size]byte]*issuerCapabilities),
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		ic := &issuerCapabilities{Source: ISSUERSOURCE_CCADB, DefaultProfile: -1}
+		switch record[column[CCADBCOLUMN_RECORDTYPE]] {
+		case "Root Certificate":
+			ic.IsRoot = true
+		case "Intermediate Certificate":
+		default:
+			return nil, fmt.Errorf("line %d: unrecognised %s: %s", line, CCADBCOLUMN_RECORDTYPE, record[column[CCADBCOLUMN_RECORDTYPE]])
+		}
+		for name, capable := range map[string]*bool{
+			CCADBCOLUMN_TLSCAPABLE:         &ic.TlsCapable,
+			CCADBCOLUMN_TLSEVCAPABLE:       &ic.TlsEvCapable,
+			CCADBCOLUMN_SMIMECAPABLE:       &ic.SmimeCapable,
+			CCADBCOLUMN_CODESIGNINGCAPABLE: &ic.CodeSigningCapable,
+			CCADBCOLUMN_VMCCAPABLE:         &ic.HasVMCAudit,
+		} {
+			if i, ok := column[name]; ok && record[i] != "" {
+				if *capable, err = strconv.ParseBool(record[i]); err != nil {
+					return nil, fmt.Errorf("line %d: unrecognised %s: %s", line, name, record[i])
+				}
+			}
+		}
+
+		var fingerprint [sha256.Size]byte
+		if b, err := hex.DecodeString(strings.ReplaceAll(record[column[CCADBCOLUMN_SHA256]], ":", "")); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("line %d: unrecognised %s: %s", line, CCADBCOLUMN_SHA256, record[column[CCADBCOLUMN_SHA256]])
+		} else {
+			copy(fingerprint[:], b)
+		}
+		snapshot.bySHA256[fingerprint] = ic
+		if keyIdentifier := record[column[CCADBCOLUMN_SKI]]; keyIdentifier != "" {
+			if _, err := base64.StdEncoding.DecodeString(keyIdentifier); err != nil {
+				return nil, fmt.Errorf("line %d: unrecognised %s: %s", line, CCADBCOLUMN_SKI, keyIdentifier)
+			}
+			snapshot.byKeyIdentifier[keyIdentifier] = ic
+		}
+	}
+
+	if len(snapshot.bySHA256) == 0 {
+		return nil, errors.New("no certificate records")
+	}
+	return snapshot, nil
+}
+
+// newestCCADBSnapshot finds the most recently published CCADB snapshot in the data directory, if any.
+func newestCCADBSnapshot(directory string) (file string, published time.Time, err error) {
+	entries, err := os.ReadDir(directory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", time.Time{}, nil
+	} else if err != nil {
+		return "", time.Time{}, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if datetime, ok := strings.CutPrefix(name, CCADB_SNAPSHOT_PREFIX); ok && !entry.IsDir() {
+			if datetime, ok = strings.CutSuffix(datetime, CCADB_SNAPSHOT_SUFFIX); ok {
+				if t, err := time.Parse(CCADB_DATE_LAYOUT, datetime); err == nil && t.After(published) {
+					file, published = filepath.Join(directory, name), t
+				}
+			}
+		}
+	}
+	return file, published, nil
+}
+
+// reloadCCADBSnapshot loads the most recently published CCADB snapshot from the data directory, if it was published
+// after the active snapshot.  An invalid snapshot is not loaded, so the previously active data remains in use.
+func reloadCCADBSnapshot(directory string) (bool, error) {
+	if directory == "" {
+		return false, nil
+	}
+	file, published, err := newestCCADBSnapshot(directory)
+	if err != nil {
+		return false, err
+	} else if file == "" {
+		return false, nil
+	} else if active := activeCCADBSnapshot.Load(); active != nil && !published.After(active.published) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	var snapshot *ccadbSnapshot
+	if err = verifySnapshotSignature(file, data); err == nil {
+		snapshot, err = parseCCADBSnapshot(bytes.NewReader(data))
+	}
+	if err != nil {
+		ccadbSnapshotReloadFailures.Inc()
+		return false, fmt.Errorf("%s: %w", file, err)
+	}
+	snapshot.file, snapshot.published = file, published
+	activeCCADBSnapshot.Store(snapshot)
+	logger.Logger.Info("Loaded CCADB snapshot", zap.String("file", file), zap.Time("published", snapshot.published), zap.Int("num_records", len(snapshot.bySHA256)))
+	return true, nil
+}
+
+// WatchDataSnapshots periodically reloads the data snapshots from the data directory, until the context is done.
+func WatchDataSnapshots(ctx context.Context) {
+	if config.Config.Data.Directory == "" || config.Config.Data.ReloadInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(config.Config.Data.ReloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := reloadCCADBSnapshot(config.Config.Data.Directory); err != nil {
+					logger.Logger.Error("Failed to reload CCADB snapshot", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// ccadbDataSnapshot describes the CCADB data that is in use.
+func ccadbDataSnapshot() linter.DataSnapshot {
+	if snapshot := activeCCADBSnapshot.Load(); snapshot != nil {
+		return linter.DataSnapshot{Name: "ccadb", Source: snapshot.file, Date: snapshot.published}
+	}
+	return linter.DataSnapshot{Name: "ccadb", Source: linter.DATASOURCE_EMBEDDED, Date: embeddedCCADBDate(linter.GetPackageVersion("github.com/crtsh/ccadb_data"))}
+}
+
+// embeddedCCADBDate extracts the publication date from a ccadb_data module version (e.g., v1.20260820.193505).
+func embeddedCCADBDate(version string) time.Time {
+	if _, datetime, ok := strings.Cut(version, "."); ok {
+		if t, err := time.Parse(CCADB_DATE_LAYOUT, datetime); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
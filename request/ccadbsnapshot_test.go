@@ -0,0 +1,235 @@
+package request
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testCCADBSnapshotHeader = "\ufeffCertificate Record Type,SHA-256 Fingerprint,Subject Key Identifier,TLS Capable,TLS EV Capable,S/MIME Capable,Code Signing Capable,VMC Capable\n"
+	testCCADBSnapshot       = testCCADBSnapshotHeader +
+		"Root Certificate,00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF,iCSoZQ==,true,false,true,false,\n" +
+		"Intermediate Certificate,FF00112233445566778899AABBCCDDEEFF00112233445566778899AABBCCDDEE,,False,,,,TRUE\n"
+)
+
+// withCCADBSnapshot deactivates any loaded CCADB snapshot for the duration of the test.
+func withCCADBSnapshot(t *testing.T) {
+	t.Helper()
+	saved := activeCCADBSnapshot.Load()
+	t.Cleanup(func() { activeCCADBSnapshot.Store(saved) })
+	activeCCADBSnapshot.Store(nil)
+}
+
+// withSnapshotPublicKey replaces the data snapshot public key for the duration of the test.
+func withSnapshotPublicKey(t *testing.T, pub crypto.PublicKey) {
+	t.Helper()
+	saved := snapshotPublicKey
+	t.Cleanup(func() { snapshotPublicKey = saved })
+	snapshotPublicKey = pub
+}
+
+func testFingerprint(t *testing.T, s string) (fingerprint [sha256.Size]byte) {
+	t.Helper()
+	if b, err := hex.DecodeString(strings.ReplaceAll(s, ":", "")); err != nil || len(b) != sha256.Size {
+		t.Fatalf("bad fingerprint: %s", s)
+	} else {
+		copy(fingerprint[:], b)
+	}
+	return fingerprint
+}
+
+func TestParseCCADBSnapshot(t *testing.T) {
+	snapshot, err := parseCCADBSnapshot(strings.NewReader(testCCADBSnapshot))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(snapshot.bySHA256) != 2 || len(snapshot.byKeyIdentifier) != 1 {
+		t.Fatalf("got %d, %d records", len(snapshot.bySHA256), len(snapshot.byKeyIdentifier))
+	}
+	if ic := snapshot.byKeyIdentifier["iCSoZQ=="]; ic == nil || ic.Source != ISSUERSOURCE_CCADB || !ic.IsRoot || !ic.TlsCapable || ic.TlsEvCapable || !ic.SmimeCapable || ic.HasVMCAudit {
+		t.Errorf("got %+v", ic)
+	} else if ic != snapshot.bySHA256[testFingerprint(t, "00112233445566778899AABBCCDDEEFF00112233445566778899AABBCCDDEEFF")] {
+		t.Errorf("want the root to be indexed by both its SKI and its fingerprint")
+	}
+	if ic := snapshot.bySHA256[testFingerprint(t, "FF00112233445566778899AABBCCDDEEFF00112233445566778899AABBCCDDEE")]; ic == nil || ic.IsRoot || ic.TlsCapable || !ic.HasVMCAudit {
+		t.Errorf("got %+v", ic)
+	}
+}
+
+func TestParseCCADBSnapshot_Invalid(t *testing.T) {
+	validFingerprint := strings.Repeat("00", sha256.Size)
+	for _, tc := range []struct {
+		name, csv string
+	}{
+		{"empty", ""},
+		{"missing column", "Certificate Record Type,SHA-256 Fingerprint\n"},
+		{"no records", testCCADBSnapshotHeader},
+		{"bad record type", testCCADBSnapshotHeader + "Leaf Certificate," + validFingerprint + ",,,,,,\n"},
+		{"bad fingerprint", testCCADBSnapshotHeader + "Root Certificate,0011,,,,,,\n"},
+		{"bad SKI", testCCADBSnapshotHeader + "Root Certificate," + validFingerprint + ",!!,,,,,\n"},
+		{"bad capability", testCCADBSnapshotHeader + "Root Certificate," + validFingerprint + ",,maybe,,,,\n"},
+		{"wrong arity", testCCADBSnapshotHeader + "Root Certificate," + validFingerprint + "\n"},
+	} {
+		if _, err := parseCCADBSnapshot(strings.NewReader(tc.csv)); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+// writeCCADBSnapshot writes a CCADB snapshot, published at the specified time, to the data directory.
+func writeCCADBSnapshot(t *testing.T, directory string, published time.Time, content string) string {
+	t.Helper()
+	file := filepath.Join(directory, CCADB_SNAPSHOT_PREFIX+published.Format(CCADB_DATE_LAYOUT)+CCADB_SNAPSHOT_SUFFIX)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestReloadCCADBSnapshot(t *testing.T) {
+	withCCADBSnapshot(t)
+	withIssuerRegistry(t)
+	withSnapshotPublicKey(t, nil)
+	directory := t.TempDir()
+
+	// Without a snapshot, the CCADB data that is compiled into pkimetal is used.
+	if loaded, err := reloadCCADBSnapshot(filepath.Join(directory, "missing")); loaded || err != nil {
+		t.Fatalf("got %v, %v; want a missing data directory to be ignored", loaded, err)
+	} else if loaded, err = reloadCCADBSnapshot(directory); loaded || err != nil {
+		t.Fatalf("got %v, %v; want nothing to be loaded", loaded, err)
+	} else if ds := ccadbDataSnapshot(); ds.Source != "embedded" {
+		t.Errorf("got %+v", ds)
+	}
+
+	// The most recently published valid snapshot is loaded, and supersedes the CCADB data that is compiled into
+	// pkimetal.  Files that are not named like snapshots are ignored.
+	published := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	writeCCADBSnapshot(t, directory, published.Add(-time.Hour), testCCADBSnapshotHeader)
+	file := writeCCADBSnapshot(t, directory, published, testCCADBSnapshot)
+	if err := os.WriteFile(filepath.Join(directory, "ccadb-latest.csv"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := reloadCCADBSnapshot(directory); !loaded || err != nil {
+		t.Fatalf("got %v, %v; want the snapshot to be loaded", loaded, err)
+	} else if ic := lookupIssuerByKeyIdentifier("iCSoZQ=="); ic == nil || !ic.TlsCapable {
+		t.Errorf("got %+v", ic)
+	} else if ds := ccadbDataSnapshot(); ds.Source != file || !ds.Date.Equal(published) {
+		t.Errorf("got %+v", ds)
+	}
+
+	// A snapshot that is not newer than the active snapshot is not reloaded, even if its file has been modified.
+	if err := os.Chtimes(file, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	} else if loaded, err := reloadCCADBSnapshot(directory); loaded || err != nil {
+		t.Errorf("got %v, %v; want nothing to be reloaded", loaded, err)
+	}
+
+	// An invalid snapshot is not loaded, and the previous snapshot remains active.
+	writeCCADBSnapshot(t, directory, published.Add(time.Hour), testCCADBSnapshotHeader)
+	if loaded, err := reloadCCADBSnapshot(directory); loaded || err == nil {
+		t.Errorf("got %v, %v; want an error", loaded, err)
+	} else if ic := lookupIssuerByKeyIdentifier("iCSoZQ=="); ic == nil {
+		t.Errorf("want the previous snapshot to remain active")
+	}
+
+	// A newer snapshot is loaded.
+	published = published.Add(2 * time.Hour)
+	writeCCADBSnapshot(t, directory, published, strings.Replace(testCCADBSnapshot, "iCSoZQ==,true", "iCSoZQ==,false", 1))
+	if loaded, err := reloadCCADBSnapshot(directory); !loaded || err != nil {
+		t.Fatalf("got %v, %v; want the snapshot to be reloaded", loaded, err)
+	} else if ic := lookupIssuerByKeyIdentifier("iCSoZQ=="); ic == nil || ic.TlsCapable {
+		t.Errorf("got %+v", ic)
+	} else if ic = lookupIssuerByKeyIdentifier("AAAA"); ic != nil {
+		t.Errorf("got %+v, want issuers that are absent from the snapshot not to be found", ic)
+	} else if ds := ccadbDataSnapshot(); !ds.Date.Equal(published) {
+		t.Errorf("got %+v", ds)
+	}
+}
+
+func TestReloadCCADBSnapshot_Signature(t *testing.T) {
+	withCCADBSnapshot(t)
+	withIssuerRegistry(t)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := loadSnapshotPublicKey(writeTempFile(t, "snapshot.pem", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	withSnapshotPublicKey(t, key)
+	directory := t.TempDir()
+	published := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// An unsigned snapshot is not loaded.
+	file := writeCCADBSnapshot(t, directory, published, testCCADBSnapshot)
+	if loaded, err := reloadCCADBSnapshot(directory); loaded || err == nil {
+		t.Errorf("got %v, %v; want an error", loaded, err)
+	}
+
+	// A snapshot with an invalid signature is not loaded.
+	if err = os.WriteFile(file+SNAPSHOT_SIGNATURE_SUFFIX, ed25519.Sign(priv, []byte(testCCADBSnapshotHeader)), 0o600); err != nil {
+		t.Fatal(err)
+	} else if loaded, err := reloadCCADBSnapshot(directory); loaded || err == nil {
+		t.Errorf("got %v, %v; want an error", loaded, err)
+	} else if ds := ccadbDataSnapshot(); ds.Source != "embedded" {
+		t.Errorf("got %+v", ds)
+	}
+
+	// A correctly signed snapshot is loaded.
+	if err = os.WriteFile(file+SNAPSHOT_SIGNATURE_SUFFIX, ed25519.Sign(priv, []byte(testCCADBSnapshot)), 0o600); err != nil {
+		t.Fatal(err)
+	} else if loaded, err := reloadCCADBSnapshot(directory); !loaded || err != nil {
+		t.Errorf("got %v, %v; want the snapshot to be loaded", loaded, err)
+	} else if ds := ccadbDataSnapshot(); ds.Source != file {
+		t.Errorf("got %+v", ds)
+	}
+}
+
+func TestLoadSnapshotPublicKey_Invalid(t *testing.T) {
+	dhKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(dhKey.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		file string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.pem")},
+		{"not PEM", writeTempFile(t, "notpem.pem", "not PEM")},
+		{"wrong PEM type", writeTempFile(t, "cert.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))},
+		{"malformed key", writeTempFile(t, "malformed.pem", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{0x30}})))},
+		{"unsupported key type", writeTempFile(t, "x25519.pem", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))},
+	} {
+		if _, err := loadSnapshotPublicKey(tc.file); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestEmbeddedCCADBDate(t *testing.T) {
+	if got := embeddedCCADBDate("v1.20260820.193505"); !got.Equal(time.Date(2026, 8, 20, 19, 35, 5, 0, time.UTC)) {
+		t.Errorf("got %v", got)
+	} else if got = embeddedCCADBDate("[not installed]"); !got.IsZero() {
+		t.Errorf("got %v", got)
+	}
+}
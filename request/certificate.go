@@ -1,6 +1,7 @@
 package request
 
 import (
+	"bytes"
 	"encoding/pem"
 	"fmt"
 	"math/big"
@@ -67,3 +68,45 @@ func (ri *RequestInfo) makeDummyCertificate() error {
 	ri.decodedInput, err = dummySign(ri.decodedInput, tbs.SignatureAlgorithm)
 	return err
 }
+
+// parseIssuerInput decodes and parses the optional PEM or Base64 issuer certificate
+// input.  PEM input may be followed by the remainder of the certificate chain.
+func (ri *RequestInfo) parseIssuerInput(b64Issuer []byte) (err error) {
+	if len(b64Issuer) == 0 {
+		return nil
+	} else if ri.endpoint != ENDPOINT_LINTCERT {
+		return fmt.Errorf("issuer is only supported for certificate input")
+	}
+
+	// Decode each certificate in the chain.
+	var ders [][]byte
+	if rest := bytes.TrimSpace(b64Issuer); bytes.HasPrefix(rest, []byte("-----BEGIN")) {
+		for len(rest) > 0 {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil || block.Type != "CERTIFICATE" {
+				return fmt.Errorf("invalid PEM certificate chain")
+			}
+			ders = append(ders, block.Bytes)
+			rest = bytes.TrimSpace(rest)
+		}
+	} else if der, err := utils.DecodePEMOrBase64(rest, "CERTIFICATE"); err != nil {
+		return err
+	} else {
+		ders = append(ders, der)
+	}
+
+	// Parse the certificates, recovering from any panics that may occur during parsing.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Recovered from panic while parsing issuer certificate: %v", r)
+		}
+	}()
+	chain := make([]*x509.Certificate, len(ders))
+	for i, der := range ders {
+		if chain[i], err = x509.ParseCertificate(der); err != nil {
+			return err
+		}
+	}
+	ri.issuer, ri.chain = chain[0], chain[1:]
+	return nil
+}
@@ -0,0 +1,37 @@
+package request
+
+import (
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkimetal/pkimetal/utils"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+func (ri *RequestInfo) parseCSRInput() (csr *x509.CertificateRequest, err error) {
+	// Decode the PEM or Base64 CSR input, accepting the legacy "NEW CERTIFICATE REQUEST" PEM label too.
+	if ri.b64Input == nil {
+		err = fmt.Errorf("no CSR provided")
+		return
+	} else if block, _ := pem.Decode(ri.b64Input); block != nil && block.Type == "NEW CERTIFICATE REQUEST" {
+		ri.decodedInput = block.Bytes
+	} else if ri.decodedInput, err = utils.DecodePEMOrBase64(ri.b64Input, "CERTIFICATE REQUEST"); err != nil {
+		return
+	}
+
+	// Update the Base64 input field from the decoded input, which will ensure that PEM encapsulation boundaries are present.
+	ri.b64Input = pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: ri.decodedInput,
+	})
+
+	// Parse the CSR, recovering from any panics that may occur during parsing.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Recovered from panic while parsing CSR: %v", r)
+		}
+	}()
+	csr, err = x509.ParseCertificateRequest(ri.decodedInput)
+	return
+}
@@ -0,0 +1,101 @@
+package request
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/zmap/zcrypto/encoding/asn1"
+	"github.com/zmap/zcrypto/x509"
+)
+
+// customProfileRule is a custom profile's autodetection criteria, compiled for matching.
+type customProfileRule struct {
+	profileId  linter.ProfileId
+	issuerSKIs [][]byte
+	policyOIDs []string
+}
+
+var (
+	customProfileRules []customProfileRule
+	oidRegexp          = regexp.MustCompile(`^[0-2](\.[0-9]+)+$`)
+)
+
+func init() {
+	var err error
+	if customProfileRules, err = compileCustomProfileRules(config.Config.Profiles.Custom); err != nil {
+		panic(err)
+	}
+}
+
+// compileCustomProfileRules validates and compiles the autodetection criteria of the custom profiles.  Custom
+// profiles without any criteria are only used when explicitly requested.
+func compileCustomProfileRules(cps []config.CustomProfile) ([]customProfileRule, error) {
+	var compiled []customProfileRule
+	for _, cp := range cps {
+		if len(cp.Autodetect.IssuerSKIs) == 0 && len(cp.Autodetect.PolicyOIDs) == 0 {
+			continue
+		}
+		rule := customProfileRule{policyOIDs: cp.Autodetect.PolicyOIDs}
+		var ok bool
+		if rule.profileId, ok = linter.GetProfileId(cp.Name); !ok {
+			return nil, fmt.Errorf("custom profile %s: not registered", cp.Name)
+		} else if slices.Contains(linter.NonCertificateProfileIDs, linter.BaseProfileId(rule.profileId)) {
+			return nil, fmt.Errorf("custom profile %s: autodetection is only supported for certificate profiles", cp.Name)
+		}
+		for _, s := range cp.Autodetect.IssuerSKIs {
+			ski, err := hex.DecodeString(strings.ReplaceAll(s, ":", ""))
+			if err != nil {
+				return nil, fmt.Errorf("custom profile %s: unrecognised issuer SKI: %w", cp.Name, err)
+			}
+			rule.issuerSKIs = append(rule.issuerSKIs, ski)
+		}
+		for _, oid := range cp.Autodetect.PolicyOIDs {
+			if !oidRegexp.MatchString(oid) {
+				return nil, fmt.Errorf("custom profile %s: unrecognised policy OID: %s", cp.Name, oid)
+			}
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// matches determines whether or not the certificate matches all of the rule's specified criteria.  Each criterion
+// is satisfied by any one of its values.
+func (rule *customProfileRule) matches(cert *x509.Certificate) bool {
+	if len(rule.issuerSKIs) > 0 && !slices.ContainsFunc(rule.issuerSKIs, func(ski []byte) bool { return bytes.Equal(ski, cert.AuthorityKeyId) }) {
+		return false
+	} else if len(rule.policyOIDs) > 0 && !slices.ContainsFunc(cert.PolicyIdentifiers, func(oid asn1.ObjectIdentifier) bool { return slices.Contains(rule.policyOIDs, oid.String()) }) {
+		return false
+	}
+	return true
+}
+
+// detectCustomProfile returns the first custom profile whose autodetection criteria match the certificate.
+func (ri *RequestInfo) detectCustomProfile() (linter.ProfileId, bool) {
+	if ids := ri.matchingCustomProfiles(); len(ids) > 0 {
+		return ids[0], true
+	}
+	return -1, false
+}
+
+// matchingCustomProfiles returns every custom profile whose autodetection criteria match the certificate, in
+// configuration order.  Only certificate inputs are matched.
+func (ri *RequestInfo) matchingCustomProfiles() []linter.ProfileId {
+	var ids []linter.ProfileId
+	if ri.cert == nil || (ri.endpoint != ENDPOINT_LINTCERT && ri.endpoint != ENDPOINT_LINTTBSCERT) {
+		return ids
+	}
+	for i := range customProfileRules {
+		if customProfileRules[i].matches(ri.cert) {
+			ids = append(ids, customProfileRules[i].profileId)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,134 @@
+package request
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+// withCustomProfiles registers custom profiles, and compiles their autodetection rules, for the duration of the
+// test.
+func withCustomProfiles(t *testing.T, cps ...config.CustomProfile) {
+	t.Helper()
+	saved := customProfileRules
+	t.Cleanup(func() {
+		customProfileRules = saved
+		_ = linter.RegisterCustomProfiles(config.Config.Profiles.Custom)
+	})
+
+	var err error
+	if err = linter.RegisterCustomProfiles(cps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if customProfileRules, err = compileCustomProfileRules(cps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// autodetectedCustomProfile returns a custom profile with the specified autodetection criteria.
+func autodetectedCustomProfile(name, baseProfile string, issuerSKIs, policyOIDs []string) config.CustomProfile {
+	cp := config.CustomProfile{Name: name, BaseProfile: baseProfile}
+	cp.Autodetect.IssuerSKIs, cp.Autodetect.PolicyOIDs = issuerSKIs, policyOIDs
+	return cp
+}
+
+func TestCompileCustomProfileRules(t *testing.T) {
+	t.Cleanup(func() { _ = linter.RegisterCustomProfiles(config.Config.Profiles.Custom) })
+	for _, tc := range []struct {
+		name    string
+		profile config.CustomProfile
+		wantErr bool
+	}{
+		{"valid", autodetectedCustomProfile("internal_tls", "rfc5280_leaf_tlsserver", []string{"0102"}, []string{"1.2.3"}), false},
+		{"bad issuer SKI", autodetectedCustomProfile("internal_tls", "rfc5280_leaf_tlsserver", []string{"xyz"}, nil), true},
+		{"bad policy OID", autodetectedCustomProfile("internal_tls", "rfc5280_leaf_tlsserver", nil, []string{"1.2.x"}), true},
+		{"CRL profile", autodetectedCustomProfile("internal_crl", "rfc5280_crl", []string{"0102"}, nil), true},
+	} {
+		cps := []config.CustomProfile{tc.profile}
+		if err := linter.RegisterCustomProfiles(cps); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		} else if _, err = compileCustomProfileRules(cps); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestGetProfile_CustomProfile(t *testing.T) {
+	cert, err := x509.ParseCertificate(testcaseDER(t, "tls_ov_certificate.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first custom profile's criteria don't match; the second's do.
+	withCustomProfiles(t,
+		autodetectedCustomProfile("other_tls", "rfc5280_leaf_tlsserver", []string{"0102"}, nil),
+		autodetectedCustomProfile("sectigo_ov", "tbr_leaf_tlsserver_ov", []string{"e3:66:74:bb:70:68:8d:2c:5d:4e:0e:a6:4a:8f:9b:37:22:9c:82:92"}, []string{"2.23.140.1.2.2"}),
+		config.CustomProfile{Name: "explicit_only", BaseProfile: "rfc5280_leaf"},
+	)
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+	if !ri.GetProfile("") {
+		t.Fatal("expected a profile to be autodetected")
+	} else if name := linter.AllProfiles[ri.profileId].Name; name != "sectigo_ov" || !ri.autodetected {
+		t.Errorf("got %s, want sectigo_ov", name)
+	}
+
+	// A custom profile can be requested explicitly, but only on an endpoint that supports its base profile.
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+	if !ri.GetProfile("explicit_only") || linter.AllProfiles[ri.profileId].Name != "explicit_only" {
+		t.Errorf("expected explicit_only to be selected")
+	}
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCSR}
+	if ri.GetProfile("explicit_only") {
+		t.Errorf("expected explicit_only to be rejected for a CSR")
+	}
+}
+
+func TestLint_CustomProfile(t *testing.T) {
+	withCustomProfiles(t, config.CustomProfile{
+		Name:            "internal_ocsp",
+		BaseProfile:     "rfc6960_ocspresponse",
+		DisabledLinters: []string{"absent"},
+		Include:         []string{"e_*"},
+		Exclude:         []string{"e_ext_*"},
+	})
+	saved := linter.Linters
+	t.Cleanup(func() { linter.Linters = saved })
+	fake := &linter.Linter{Name: "fake", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 1)}
+	linter.Linters = linter.LinterSlice{{Name: "absent"}, fake}
+	lreqChan := make(chan linter.LintingRequest, 1)
+	go func() {
+		lreq := <-fake.ReqChannel
+		lreqChan <- lreq
+		lreq.RespChannel <- linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_META, Structured: true, Timing: &linter.LintingTiming{}}
+		lreq.RespChannel <- linter.LintingResult{LinterName: linter.PKIMETAL_NAME, Severity: linter.SEVERITY_META, Finding: linter.PKIMETAL_ENDOFRESULTS}
+	}()
+
+	// The request's include narrows the custom profile's, rather than replacing it.
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, checksAdded: []string{"*"}, checksDisabled: []string{"e_foo"}}
+	if !ri.GetProfile("internal_ocsp") {
+		t.Fatal("expected internal_ocsp to be selected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lrespFiltered := ri.lint(ctx)
+
+	lreq := <-lreqChan
+	if lreq.ProfileId != linter.RFC6960_OCSPRESPONSE {
+		t.Errorf("got profile %d, want the base profile", lreq.ProfileId)
+	} else if !slices.Equal(lreq.ChecksAdded, []string{"e_*"}) || !slices.Equal(lreq.ChecksDisabled, []string{"e_ext_*", "e_foo"}) {
+		t.Errorf("got checks %v, %v", lreq.ChecksAdded, lreq.ChecksDisabled)
+	} else if !lreq.IsCheckSelected("e_a") || lreq.IsCheckSelected("w_a") || lreq.IsCheckSelected("e_ext_a") {
+		t.Errorf("got checks %v narrowed by %v; want the request to only narrow the custom profile's checks", lreq.ChecksAdded, lreq.ChecksNarrowed)
+	}
+	if len(lrespFiltered) == 0 || lrespFiltered[0].Finding != "Profile: internal_ocsp; Version: "+linter.VersionString(config.PkimetalVersion) {
+		t.Errorf("got %+v, want the custom profile to be reported", lrespFiltered)
+	} else if ri.linterReports[0].Status != LINTERSTATUS_NOTAPPLICABLE {
+		t.Errorf("got %+v, want the disabled linter to be not applicable", ri.linterReports[0])
+	}
+}
@@ -0,0 +1,125 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	VERDICT_PASS   = "pass"
+	VERDICT_REJECT = "reject"
+)
+
+// decision is the outcome of evaluating the configured decision policy for a request.
+type decision struct {
+	verdict     string
+	maxSeverity linter.SeverityLevel
+	reasons     []string // Why the verdict is VERDICT_REJECT.
+}
+
+func (d *decision) reject(format string, args ...any) {
+	d.verdict = VERDICT_REJECT
+	d.reasons = append(d.reasons, fmt.Sprintf(format, args...))
+}
+
+// decisionThresholds holds the severity levels at or above which a finding causes rejection.
+type decisionThresholds struct {
+	threshold        linter.SeverityLevel
+	linterThresholds map[string]linter.SeverityLevel
+}
+
+var thresholds decisionThresholds
+
+func init() {
+	var err error
+	if thresholds, err = compileDecisionThresholds(config.Config.Decision.Threshold, config.Config.Decision.LinterThresholds); err != nil {
+		panic(err)
+	}
+}
+
+// compileDecisionThresholds validates and compiles the decision policy's severity thresholds.
+func compileDecisionThresholds(threshold string, linterThresholds map[string]string) (decisionThresholds, error) {
+	compiled := decisionThresholds{linterThresholds: make(map[string]linter.SeverityLevel, len(linterThresholds))}
+	var ok bool
+	if compiled.threshold, ok = linter.Severity[threshold]; !ok {
+		return decisionThresholds{}, fmt.Errorf("decision threshold: unrecognised severity: %s", threshold)
+	}
+	for name, s := range linterThresholds {
+		if compiled.linterThresholds[name], ok = linter.Severity[s]; !ok {
+			return decisionThresholds{}, fmt.Errorf("decision threshold for %s: unrecognised severity: %s", name, s)
+		}
+	}
+	return compiled, nil
+}
+
+// CheckDecisionPolicyLinters verifies that each linter named by the decision policy is a registered linter.  It must
+// be called once all of the linters have been registered.
+func CheckDecisionPolicyLinters() error {
+	for _, name := range slices.Concat(slices.Sorted(maps.Keys(config.Config.Decision.LinterThresholds)), config.Config.Decision.RequiredLinters) {
+		if !slices.ContainsFunc(linter.Linters, func(l *linter.Linter) bool { return l.Name == name }) {
+			return fmt.Errorf("decision policy: unrecognised linter: %s", name)
+		}
+	}
+	return nil
+}
+
+// decisionThreshold returns the severity level at or above which a finding from the specified linter causes
+// rejection.
+func decisionThreshold(linterName string) linter.SeverityLevel {
+	if threshold, ok := thresholds.linterThresholds[linterName]; ok {
+		return threshold
+	}
+	return thresholds.threshold
+}
+
+// decide evaluates the configured decision policy against every unwaived result, irrespective of the requested
+// minimum severity level, and against the outcome of each linter.  Any linter that crashed or timed out causes rejection,
+// as does any required linter that was applicable but did not run.  Findings that are not yet effective are ignored.
+func (ri *RequestInfo) decide(lresp []linter.LintingResult) decision {
+	d := decision{verdict: VERDICT_PASS, maxSeverity: linter.SEVERITY_META}
+	for _, lres := range lresp {
+		if lres.Structured || lres.Waived != nil || lres.NotYetEffective != "" {
+			continue
+		}
+		d.maxSeverity = max(d.maxSeverity, lres.Severity)
+		if lres.Severity >= decisionThreshold(lres.LinterName) {
+			what := lres.Code
+			if what == "" {
+				what = lres.Finding
+			}
+			d.reject("%s: %s [%s]", lres.LinterName, what, linter.SeverityString[lres.Severity])
+		}
+	}
+
+	for _, report := range ri.linterReports {
+		switch report.Status {
+		case LINTERSTATUS_FAILED, LINTERSTATUS_TIMEDOUT:
+			d.reject("%s: %s", report.Name, report.Status)
+		case LINTERSTATUS_NOTAVAILABLE:
+			if slices.Contains(config.Config.Decision.RequiredLinters, report.Name) {
+				d.reject("%s: required but %s", report.Name, report.Status)
+			}
+		}
+	}
+
+	return d
+}
+
+// setDecisionHeaders adds the verdict headers to the response, and returns the HTTP status code to use.
+func (d *decision) setDecisionHeaders(fhctx *fasthttp.RequestCtx, status int) int {
+	fhctx.Response.Header.Set("Access-Control-Expose-Headers", "X-Pkimetal-Verdict, X-Pkimetal-Max-Severity, X-Pkimetal-Verdict-Reason")
+	fhctx.Response.Header.Set("X-Pkimetal-Verdict", d.verdict)
+	fhctx.Response.Header.Set("X-Pkimetal-Max-Severity", linter.SeverityString[d.maxSeverity])
+	if d.verdict == VERDICT_REJECT {
+		fhctx.Response.Header.Set("X-Pkimetal-Verdict-Reason", strings.NewReplacer("\r", " ", "\n", " ").Replace(strings.Join(d.reasons, "; ")))
+		return fasthttp.StatusUnprocessableEntity
+	}
+	return status
+}
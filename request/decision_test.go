@@ -0,0 +1,166 @@
+package request
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+)
+
+// withDecisionPolicy replaces the configured decision policy for the duration of the test.
+func withDecisionPolicy(t *testing.T, threshold string, linterThresholds map[string]string, requiredLinters ...string) {
+	t.Helper()
+	saved := config.Config.Decision
+	t.Cleanup(func() { config.Config.Decision = saved })
+
+	savedThresholds := thresholds
+	t.Cleanup(func() { thresholds = savedThresholds })
+
+	config.Config.Decision.Threshold = threshold
+	config.Config.Decision.LinterThresholds = linterThresholds
+	config.Config.Decision.RequiredLinters = requiredLinters
+	var err error
+	if thresholds, err = compileDecisionThresholds(threshold, linterThresholds); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lintForDecision lints an OCSP response using the fake linters, with decision mode enabled.
+func lintForDecision(t *testing.T, minimumSeverity linter.SeverityLevel) *decision {
+	t.Helper()
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, minimumSeverity: minimumSeverity, decisionMode: true}
+	if !ri.GetProfile("") {
+		t.Fatal("expected the OCSP profile to be autodetected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ri.lint(ctx)
+	if ri.decision == nil {
+		t.Fatal("expected a decision")
+	}
+	return ri.decision
+}
+
+func TestDecide_Pass(t *testing.T) {
+	withDecisionPolicy(t, "error", nil)
+	withFakeLinters(t, linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_WARNING, Finding: "w_a", Code: "w_a"})
+
+	d := lintForDecision(t, linter.SEVERITY_META)
+	if d.verdict != VERDICT_PASS || d.maxSeverity != linter.SEVERITY_WARNING || len(d.reasons) != 0 {
+		t.Errorf("got %+v, want pass with max severity warning", d)
+	}
+}
+
+func TestDecide_RejectIgnoresMinimumSeverity(t *testing.T) {
+	withDecisionPolicy(t, "error", nil)
+	withFakeLinters(t, linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_a", Code: "e_a"})
+
+	d := lintForDecision(t, linter.SEVERITY_FATAL)
+	if d.verdict != VERDICT_REJECT || d.maxSeverity != linter.SEVERITY_ERROR {
+		t.Errorf("got %+v, want reject with max severity error", d)
+	} else if want := "fake: e_a [error]"; len(d.reasons) != 1 || d.reasons[0] != want {
+		t.Errorf("got reasons %q, want [%q]", d.reasons, want)
+	}
+}
+
+func TestDecide_LinterThreshold(t *testing.T) {
+	withDecisionPolicy(t, "error", map[string]string{"fake": "warning"})
+	withFakeLinters(t, linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_WARNING, Finding: "w_a"})
+
+	if d := lintForDecision(t, linter.SEVERITY_META); d.verdict != VERDICT_REJECT {
+		t.Errorf("got %+v, want reject", d)
+	}
+}
+
+func TestDecide_RequiredLinterNotAvailable(t *testing.T) {
+	withDecisionPolicy(t, "error", nil, "absent")
+	withFakeLinters(t)
+
+	d := lintForDecision(t, linter.SEVERITY_META)
+	if d.verdict != VERDICT_REJECT {
+		t.Fatalf("got %+v, want reject", d)
+	}
+	if reasons := strings.Join(d.reasons, "; "); reasons != "absent: required but not_available" {
+		t.Errorf("got reasons %q", reasons)
+	}
+}
+
+func TestDecide_LinterFailed(t *testing.T) {
+	withDecisionPolicy(t, "error", nil)
+
+	ri := RequestInfo{linterReports: []linterReport{{Name: "fake", Status: LINTERSTATUS_FAILED}, {Name: "slow", Status: LINTERSTATUS_TIMEDOUT}, {Name: "other", Status: LINTERSTATUS_NOTAPPLICABLE}}}
+	if d := ri.decide(nil); d.verdict != VERDICT_REJECT || len(d.reasons) != 2 {
+		t.Errorf("got %+v, want reject for the failed and timed out linters", d)
+	}
+}
+
+func TestDecide_MaxSeverityIgnoresWaivedAndStructured(t *testing.T) {
+	withDecisionPolicy(t, "error", nil)
+
+	ri := RequestInfo{}
+	d := ri.decide([]linter.LintingResult{
+		{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_a", Waived: &linter.Waived{Justification: "j"}},
+		{LinterName: "fake", Severity: linter.SEVERITY_FATAL, Finding: "meta", Structured: true},
+		{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_b", NotYetEffective: "2030-01-01"},
+		{LinterName: "fake", Severity: linter.SEVERITY_NOTICE, Finding: "n_a"},
+	})
+	if d.verdict != VERDICT_PASS || d.maxSeverity != linter.SEVERITY_NOTICE {
+		t.Errorf("got %+v, want pass with max severity notice", d)
+	}
+}
+
+func TestCompileDecisionThresholds(t *testing.T) {
+	for _, tc := range []struct {
+		threshold        string
+		linterThresholds map[string]string
+		wantErr          bool
+	}{
+		{"error", map[string]string{"zlint": "warning"}, false},
+		{"eror", nil, true},
+		{"error", map[string]string{"zlint": "warn"}, true},
+	} {
+		if _, err := compileDecisionThresholds(tc.threshold, tc.linterThresholds); (err != nil) != tc.wantErr {
+			t.Errorf("compileDecisionThresholds(%q, %v): got error %v", tc.threshold, tc.linterThresholds, err)
+		}
+	}
+}
+
+func TestCheckDecisionPolicyLinters(t *testing.T) {
+	withFakeLinters(t)
+	for _, tc := range []struct {
+		linterThresholds map[string]string
+		requiredLinters  []string
+		wantErr          bool
+	}{
+		{map[string]string{"fake": "warning"}, []string{"absent"}, false},
+		{map[string]string{"nonexistent": "warning"}, nil, true},
+		{nil, []string{"fake", "nonexistent"}, true},
+	} {
+		withDecisionPolicy(t, "error", tc.linterThresholds, tc.requiredLinters...)
+		if err := CheckDecisionPolicyLinters(); (err != nil) != tc.wantErr {
+			t.Errorf("%v, %v: got error %v", tc.linterThresholds, tc.requiredLinters, err)
+		}
+	}
+}
+
+func TestParseDecisionMode(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		want    bool
+		wantErr bool
+	}{
+		{"", false, false},
+		{"true", true, false},
+		{"1", true, false},
+		{"false", false, false},
+		{"maybe", false, true},
+	} {
+		if got, err := parseDecisionMode(tc.input); got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("parseDecisionMode(%q) = %v, %v", tc.input, got, err)
+		}
+	}
+}
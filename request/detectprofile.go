@@ -0,0 +1,105 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+
+	"go.uber.org/zap"
+)
+
+type DetectProfileResult struct {
+	Profile    string // The profile that autodetection selects, i.e. the top-ranked candidate.
+	Candidates []ProfileCandidate
+}
+
+type ProfileCandidate struct {
+	Rank    int
+	Profile string
+	Reasons []string
+}
+
+// DetectProfilePOST runs profile autodetection on the input, without linting it, and reports the ranked candidate
+// profiles along with the reasons for each one.
+func DetectProfilePOST(fhctx *fasthttp.RequestCtx) {
+	var ri RequestInfo
+	var err error
+	var errorMessage string
+	var result DetectProfileResult
+	inputType := paramS(fhctx, "type")
+	if inputType == "" {
+		inputType = "cert"
+	}
+	if !ri.GetPOSTEndpoint("lint" + strings.TrimPrefix(strings.ToLower(inputType), "lint")) {
+		errorMessage = "Unrecognised type"
+	} else if requestBody := fhctx.Request.Body(); len(requestBody) == 0 {
+		errorMessage = "Empty request body"
+	} else if err = ri.GetInput(fhctx); err != nil {
+		errorMessage = "Unrecognised input"
+	} else if err = ri.parseIssuerInput(paramB(fhctx, "b64issuer")); err != nil {
+		errorMessage = "Unrecognised issuer"
+	} else {
+		result.Candidates = ri.rankProfileCandidates()
+		result.Profile = result.Candidates[0].Profile
+	}
+
+	// Add Cross-Origin Resource Sharing (CORS) response header.
+	fhctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+
+	if errorMessage != "" {
+		logger.SetDetails(fhctx, zap.InfoLevel, "Profile Detection Request with Error", fmt.Errorf("%s", errorMessage), []zap.Field{
+			zap.Error(err),
+		})
+		fhctx.SetContentType("text/plain; charset=UTF-8")
+		fhctx.SetBodyString(errorMessage + "\n")
+		fhctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	// Encode and send the results as JSON.
+	j := json.NewEncoder(fhctx)
+	j.SetEscapeHTML(false)
+	if config.Config.Response.JsonPrettyPrint {
+		j.SetIndent("", "  ")
+	}
+	if err = j.Encode(result); err != nil {
+		logger.SetDetails(fhctx, zap.ErrorLevel, "Failed to encode JSON", nil, nil)
+		fhctx.SetStatusCode(fasthttp.StatusInternalServerError)
+	} else {
+		logger.SetDetails(fhctx, zap.InfoLevel, "Profile Detection Request", nil, []zap.Field{
+			zap.String("profile", result.Profile),
+		})
+		fhctx.SetContentType("application/json; charset=UTF-8")
+		fhctx.SetStatusCode(fasthttp.StatusOK)
+	}
+}
+
+// rankProfileCandidates returns the candidate profiles for the input, best match first: any custom profiles whose
+// autodetection criteria match, in configuration order; then the autodetected built-in profile; then the more
+// general profiles that it refines.
+func (ri *RequestInfo) rankProfileCandidates() []ProfileCandidate {
+	var candidates []ProfileCandidate
+	for _, id := range ri.matchingCustomProfiles() {
+		candidates = append(candidates, ProfileCandidate{Profile: id.String(), Reasons: []string{fmt.Sprintf("Matches the autodetection criteria of custom profile %s", id)}})
+	}
+
+	ri.detectionReasons = nil
+	id := ri.detectBuiltInProfile()
+	candidates = append(candidates, ProfileCandidate{Profile: id.String(), Reasons: ri.detectionReasons})
+	for child := linter.AllProfiles[id]; child.Parent != ""; {
+		parentId, _ := linter.GetProfileId(child.Parent)
+		candidates = append(candidates, ProfileCandidate{Profile: child.Parent, Reasons: []string{fmt.Sprintf("More general profile that %s refines", child.Name)}})
+		child = linter.AllProfiles[parentId]
+	}
+
+	for i := range candidates {
+		candidates[i].Rank = i + 1
+	}
+	return candidates
+}
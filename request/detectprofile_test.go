@@ -0,0 +1,81 @@
+package request
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/pkimetal/pkimetal/config"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+	"github.com/zmap/zcrypto/x509"
+)
+
+func TestDetectProfilePOST(t *testing.T) {
+	form := url.Values{"b64input": {testcasePEM(t, "tls_ov_certificate.crt")}}
+	fhctx := newPostCtx("application/x-www-form-urlencoded", []byte(form.Encode()))
+	DetectProfilePOST(fhctx)
+	if status := fhctx.Response.StatusCode(); status != fasthttp.StatusOK {
+		t.Fatalf("got status %d: %s", status, fhctx.Response.Body())
+	}
+
+	var result DetectProfileResult
+	if err := json.Unmarshal(fhctx.Response.Body(), &result); err != nil {
+		t.Fatal(err)
+	} else if result.Profile != "tbr_leaf_tlsserver_ov" {
+		t.Errorf("got profile %s, want tbr_leaf_tlsserver_ov", result.Profile)
+	}
+	var got []string
+	for i, c := range result.Candidates {
+		if c.Rank != i+1 || len(c.Reasons) == 0 {
+			t.Errorf("candidate %d: got %+v", i, c)
+		}
+		got = append(got, c.Profile)
+	}
+	if want := []string{"tbr_leaf_tlsserver_ov", "rfc5280_leaf_tlsserver", "rfc5280_leaf"}; !slices.Equal(got, want) {
+		t.Errorf("got candidates %v, want %v", got, want)
+	}
+}
+
+func TestDetectProfilePOST_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		query string
+		body  string
+		want  string
+	}{
+		{"bad type", "?type=foo", "b64input=AAAA", "Unrecognised type"},
+		{"empty body", "", "", "Empty request body"},
+		{"bad input", "?type=cert", "b64input=AAAA", "Unrecognised input"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fhctx := newPostCtx("application/x-www-form-urlencoded", []byte(tc.body))
+			fhctx.Request.SetRequestURI("/detectprofile" + tc.query)
+			DetectProfilePOST(fhctx)
+			if status := fhctx.Response.StatusCode(); status != fasthttp.StatusBadRequest {
+				t.Errorf("got status %d, want 400", status)
+			} else if body := string(fhctx.Response.Body()); body != tc.want+"\n" {
+				t.Errorf("got %q, want %q", body, tc.want)
+			}
+		})
+	}
+}
+
+func TestRankProfileCandidates_CustomProfiles(t *testing.T) {
+	withCustomProfiles(t,
+		autodetectedCustomProfile("internal_ov", "tbr_leaf_tlsserver_ov", nil, []string{"2.23.140.1.2.2"}),
+		autodetectedCustomProfile("other_tls", "rfc5280_leaf_tlsserver", []string{"0102"}, nil),
+		config.CustomProfile{Name: "explicit_only", BaseProfile: "rfc5280_leaf"},
+	)
+	cert, err := x509.ParseCertificate(testcaseDER(t, "tls_ov_certificate.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+
+	candidates := ri.rankProfileCandidates()
+	if len(candidates) != 4 || candidates[0].Profile != "internal_ov" || candidates[1].Profile != "tbr_leaf_tlsserver_ov" {
+		t.Errorf("got %+v, want the matching custom profile to be ranked first", candidates)
+	}
+}
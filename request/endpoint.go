@@ -1,5 +1,7 @@
 package request
 
+import "github.com/pkimetal/pkimetal/linter"
+
 type Endpoint int
 
 const (
@@ -10,6 +12,12 @@ const (
 	ENDPOINTSTRING_LINTTBSCRL  = "linttbscrl"
 	ENDPOINTSTRING_LINTOCSP    = "lintocsp"
 	ENDPOINTSTRING_LINTTBSOCSP = "linttbsocsp"
+	ENDPOINTSTRING_LINTCSR     = "lintcsr"
+	ENDPOINTSTRING_LINTKEY     = "lintkey"
+
+	// POST (API).
+	ENDPOINTSTRING_LINTBATCH     = "lintbatch"
+	ENDPOINTSTRING_DETECTPROFILE = "detectprofile"
 
 	// GET.
 	ENDPOINTSTRING_FRONTPAGE = ""
@@ -34,6 +42,8 @@ const (
 	ENDPOINT_LINTTBSCRL
 	ENDPOINT_LINTOCSP
 	ENDPOINT_LINTTBSOCSP
+	ENDPOINT_LINTCSR
+	ENDPOINT_LINTKEY
 )
 
 var postEndpoint = map[string]Endpoint{
@@ -43,6 +53,20 @@ var postEndpoint = map[string]Endpoint{
 	ENDPOINTSTRING_LINTTBSCRL:  ENDPOINT_LINTTBSCRL,
 	ENDPOINTSTRING_LINTOCSP:    ENDPOINT_LINTOCSP,
 	ENDPOINTSTRING_LINTTBSOCSP: ENDPOINT_LINTTBSOCSP,
+	ENDPOINTSTRING_LINTCSR:     ENDPOINT_LINTCSR,
+	ENDPOINTSTRING_LINTKEY:     ENDPOINT_LINTKEY,
+}
+
+// endpointDocumentType is the type of document that each POST endpoint lints.
+var endpointDocumentType = map[Endpoint]linter.DocumentType{
+	ENDPOINT_LINTCERT:    linter.DOCUMENTTYPE_CERTIFICATE,
+	ENDPOINT_LINTTBSCERT: linter.DOCUMENTTYPE_CERTIFICATE,
+	ENDPOINT_LINTCRL:     linter.DOCUMENTTYPE_CRL,
+	ENDPOINT_LINTTBSCRL:  linter.DOCUMENTTYPE_CRL,
+	ENDPOINT_LINTOCSP:    linter.DOCUMENTTYPE_OCSPRESPONSE,
+	ENDPOINT_LINTTBSOCSP: linter.DOCUMENTTYPE_OCSPRESPONSE,
+	ENDPOINT_LINTCSR:     linter.DOCUMENTTYPE_CSR,
+	ENDPOINT_LINTKEY:     linter.DOCUMENTTYPE_PUBLICKEY,
 }
 
 func (ri *RequestInfo) GetPOSTEndpoint(endpointString string) (ok bool) {
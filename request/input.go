@@ -17,6 +17,8 @@ const (
 	INPUTSTRING_B64TBSCRL  = "b64tbscrl"
 	INPUTSTRING_B64OCSP    = "b64ocsp"
 	INPUTSTRING_B64TBSOCSP = "b64tbsocsp"
+	INPUTSTRING_B64CSR     = "b64csr"
+	INPUTSTRING_B64KEY     = "b64key"
 )
 
 var input = map[Endpoint]string{
@@ -26,6 +28,8 @@ var input = map[Endpoint]string{
 	ENDPOINT_LINTTBSCRL:  INPUTSTRING_B64TBSCRL,
 	ENDPOINT_LINTOCSP:    INPUTSTRING_B64OCSP,
 	ENDPOINT_LINTTBSOCSP: INPUTSTRING_B64TBSOCSP,
+	ENDPOINT_LINTCSR:     INPUTSTRING_B64CSR,
+	ENDPOINT_LINTKEY:     INPUTSTRING_B64KEY,
 }
 
 func (ri *RequestInfo) GetInput(fhctx *fasthttp.RequestCtx) error {
@@ -59,8 +63,18 @@ func (ri *RequestInfo) GetInput(fhctx *fasthttp.RequestCtx) error {
 			return fmt.Errorf("invalid endpoint for OCSP response input")
 		}
 
+	case "application/pkcs10":
+		if ri.endpoint != ENDPOINT_LINTCSR {
+			return fmt.Errorf("invalid endpoint for CSR input")
+		}
+
+	case "application/jwk+json":
+		if ri.endpoint != ENDPOINT_LINTKEY {
+			return fmt.Errorf("invalid endpoint for JWK input")
+		}
+
 	case "application/octet-stream":
-		if ri.endpoint != ENDPOINT_LINTTBSCERT && ri.endpoint != ENDPOINT_LINTTBSCRL && ri.endpoint != ENDPOINT_LINTTBSOCSP {
+		if ri.endpoint != ENDPOINT_LINTTBSCERT && ri.endpoint != ENDPOINT_LINTTBSCRL && ri.endpoint != ENDPOINT_LINTTBSOCSP && ri.endpoint != ENDPOINT_LINTKEY {
 			return fmt.Errorf("invalid content type for this endpoint")
 		}
 
@@ -73,6 +87,11 @@ func (ri *RequestInfo) GetInput(fhctx *fasthttp.RequestCtx) error {
 		base64.StdEncoding.Encode(ri.b64Input, fhctx.PostBody())
 	}
 
+	return ri.parseInput()
+}
+
+// parseInput decodes and parses the PEM or Base64 input according to the endpoint.
+func (ri *RequestInfo) parseInput() error {
 	var err error
 	switch ri.endpoint {
 	case ENDPOINT_LINTCERT, ENDPOINT_LINTTBSCERT:
@@ -81,6 +100,10 @@ func (ri *RequestInfo) GetInput(fhctx *fasthttp.RequestCtx) error {
 		err = ri.parseCRLInput()
 	case ENDPOINT_LINTOCSP, ENDPOINT_LINTTBSOCSP:
 		err = ri.parseOCSPResponseInput()
+	case ENDPOINT_LINTCSR:
+		ri.csr, err = ri.parseCSRInput()
+	case ENDPOINT_LINTKEY:
+		ri.key, err = ri.parseKeyInput()
 	}
 	return err
 }
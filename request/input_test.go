@@ -1,9 +1,23 @@
 package request
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
 	"net/url"
+	"strings"
 	"testing"
+
+	zrsa "github.com/zmap/zcrypto/rsa"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestGetPOSTEndpoint(t *testing.T) {
@@ -85,3 +99,172 @@ func TestGetInput_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIssuerInput(t *testing.T) {
+	issuerPEM, chainPEM := testcasePEM(t, "tls_ov_certificate.crt"), testcasePEM(t, "codesigning_ov.crt")
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCERT}
+	if err := ri.parseIssuerInput(nil); err != nil || ri.issuer != nil {
+		t.Errorf("no issuer: got issuer %v, err %v", ri.issuer, err)
+	}
+	if err := ri.parseIssuerInput([]byte(issuerPEM + "\n" + chainPEM)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if ri.issuer == nil || len(ri.chain) != 1 {
+		t.Fatalf("got issuer %v and %d chain certificates, want an issuer and 1", ri.issuer, len(ri.chain))
+	}
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCERT}
+	if err := ri.parseIssuerInput([]byte(base64.StdEncoding.EncodeToString(testcaseDER(t, "tls_ov_certificate.crt")))); err != nil || ri.issuer == nil || len(ri.chain) != 0 {
+		t.Errorf("base64 issuer: got issuer %v, %d chain certificates, err %v", ri.issuer, len(ri.chain), err)
+	}
+}
+
+func TestParseIssuerInput_Errors(t *testing.T) {
+	issuerPEM := testcasePEM(t, "tls_ov_certificate.crt")
+
+	cases := []struct {
+		name     string
+		endpoint Endpoint
+		input    string
+	}{
+		{"non-certificate endpoint", ENDPOINT_LINTCRL, issuerPEM},
+		{"tbs certificate endpoint", ENDPOINT_LINTTBSCERT, issuerPEM},
+		{"trailing garbage", ENDPOINT_LINTCERT, issuerPEM + "garbage"},
+		{"not a certificate", ENDPOINT_LINTCERT, "AAAA"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ri := RequestInfo{endpoint: c.endpoint}
+			if err := ri.parseIssuerInput([]byte(c.input)); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+// testCSRDER creates a DER-encoded CSR for a freshly generated ECDSA P-256 key.
+func testCSRDER(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := stdx509.CreateCertificateRequest(rand.Reader, &stdx509.CertificateRequest{Subject: pkix.Name{CommonName: "example.com"}, DNSNames: []string{"example.com"}}, key)
+	if err != nil {
+		t.Fatalf("creating CSR: %v", err)
+	}
+	return der
+}
+
+func TestGetInput_PKCS10(t *testing.T) {
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCSR}
+	if err := ri.GetInput(newPostCtx("application/pkcs10", testCSRDER(t))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ri.csr == nil || len(ri.csr.DNSNames) != 1 {
+		t.Fatalf("expected a parsed CSR, got %+v", ri.csr)
+	}
+	if block, _ := pem.Decode(ri.b64Input); block == nil || block.Type != "CERTIFICATE REQUEST" {
+		t.Errorf("expected the input to be re-encoded as a PEM CERTIFICATE REQUEST, got %q", ri.b64Input)
+	}
+
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCERT}
+	if err := ri.GetInput(newPostCtx("application/pkcs10", testCSRDER(t))); err == nil {
+		t.Error("expected an error for CSR input on the certificate endpoint")
+	}
+}
+
+func TestParseCSRInput(t *testing.T) {
+	der := testCSRDER(t)
+	for _, label := range []string{"CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST"} {
+		ri := RequestInfo{endpoint: ENDPOINT_LINTCSR, b64Input: pem.EncodeToMemory(&pem.Block{Type: label, Bytes: der})}
+		if csr, err := ri.parseCSRInput(); err != nil || csr == nil {
+			t.Errorf("%s: got CSR %v, err %v", label, csr, err)
+		}
+	}
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCSR, b64Input: []byte(base64.StdEncoding.EncodeToString([]byte("not a CSR")))}
+	if _, err := ri.parseCSRInput(); err == nil {
+		t.Error("expected an error for a malformed CSR")
+	}
+}
+
+func TestGetInput_JWK(t *testing.T) {
+	ri := RequestInfo{endpoint: ENDPOINT_LINTKEY}
+	jwk := `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	if err := ri.GetInput(newPostCtx("application/jwk+json", []byte(jwk))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := ri.key.(ed25519.PublicKey); !ok {
+		t.Fatalf("expected an Ed25519 public key, got %T", ri.key)
+	}
+
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCERT}
+	if err := ri.GetInput(newPostCtx("application/jwk+json", []byte(jwk))); err == nil {
+		t.Error("expected an error for JWK input on the certificate endpoint")
+	}
+}
+
+func TestParseKeyInput(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	spki, err := stdx509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatalf("marshalling key: %v", err)
+	}
+	sshKey, err := ssh.NewPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatalf("converting key: %v", err)
+	}
+	b64url := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	ecJWK := fmt.Sprintf(`{"kty":"EC","crv":"P-256","x":"%s","y":"%s"}`, b64url(ecKey.X.Bytes()), b64url(ecKey.Y.Bytes()))
+	spkiPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+
+	rsaKey := &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 2047), E: 65537}
+	rsaJWK := fmt.Sprintf(`{"kty":"RSA","n":"%s","e":"AQAB"}`, b64url(rsaKey.N.Bytes()))
+
+	for name, input := range map[string]string{
+		"SPKI PEM":             spkiPEM,
+		"SPKI Base64":          base64.StdEncoding.EncodeToString(spki),
+		"JWK":                  ecJWK,
+		"JWK Base64":           base64.StdEncoding.EncodeToString([]byte(ecJWK)),
+		"OpenSSH":              string(ssh.MarshalAuthorizedKey(sshKey)),
+		"OpenSSH Base64":       base64.StdEncoding.EncodeToString(ssh.MarshalAuthorizedKey(sshKey)),
+		"SPKI PEM Base64":      base64.StdEncoding.EncodeToString([]byte(spkiPEM)),
+		"OpenSSH with comment": strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(sshKey)), "\n") + " hsm-ceremony\n",
+	} {
+		ri := RequestInfo{endpoint: ENDPOINT_LINTKEY, b64Input: []byte(input)}
+		if key, err := ri.parseKeyInput(); err != nil || key == nil {
+			t.Errorf("%s: got key %v, err %v", name, key, err)
+		} else if string(ri.decodedInput) != string(spki) {
+			t.Errorf("%s: decoded input is not the expected SubjectPublicKeyInfo", name)
+		} else if string(ri.b64Input) != spkiPEM {
+			t.Errorf("%s: got b64Input %q, want %q", name, ri.b64Input, spkiPEM)
+		}
+	}
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTKEY, b64Input: []byte(rsaJWK)}
+	if key, err := ri.parseKeyInput(); err != nil {
+		t.Errorf("RSA JWK: unexpected error: %v", err)
+	} else if pub, ok := key.(*zrsa.PublicKey); !ok || pub.N.Cmp(rsaKey.N) != 0 || pub.E.Int64() != int64(rsaKey.E) {
+		t.Errorf("RSA JWK: got %v", key)
+	}
+}
+
+func TestParseKeyInput_Errors(t *testing.T) {
+	for name, input := range map[string]string{
+		"private JWK":     `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"}`,
+		"unknown kty":     `{"kty":"oct","k":"AAAA"}`,
+		"unknown curve":   `{"kty":"EC","crv":"secp256k1","x":"AAAA","y":"AAAA"}`,
+		"point off curve": `{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}`,
+		"malformed JSON":  `{"kty":`,
+		"bad OpenSSH":     "ssh-ed25519 AAAA",
+		"not a key":       base64.StdEncoding.EncodeToString([]byte("not a key")),
+	} {
+		ri := RequestInfo{endpoint: ENDPOINT_LINTKEY, b64Input: []byte(input)}
+		if _, err := ri.parseKeyInput(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
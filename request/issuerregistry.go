@@ -0,0 +1,159 @@
+package request
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/crtsh/ccadb_data"
+	"github.com/zmap/zcrypto/x509"
+)
+
+const (
+	ISSUERSOURCE_REGISTRY = "issuer registry"
+	ISSUERSOURCE_CCADB    = "CCADB"
+)
+
+// issuerCapabilities is the information about a CA that autodetection uses, from either the issuer registry or the
+// CCADB data.
+type issuerCapabilities struct {
+	Source             string
+	IsRoot             bool
+	TlsCapable         bool
+	TlsEvCapable       bool
+	SmimeCapable       bool
+	CodeSigningCapable bool
+	HasVMCAudit        bool
+	DefaultProfile     linter.ProfileId // -1 if not specified.
+}
+
+// issuerRegistry is the loaded issuer registry, indexed for lookups.
+type issuerRegistry struct {
+	byKeyIdentifier map[string]*issuerCapabilities // Base64-encoded Subject Key Identifier.
+	bySHA256        map[[sha256.Size]byte]*issuerCapabilities
+}
+
+var registeredIssuers issuerRegistry
+
+func init() {
+	var err error
+	if registeredIssuers, err = compileIssuerRegistry(config.RegisteredIssuers); err != nil {
+		panic(err)
+	}
+}
+
+// compileIssuerRegistry validates and indexes the CAs loaded from the issuer registry file.
+func compileIssuerRegistry(cris []config.RegisteredIssuer) (issuerRegistry, error) {
+	registry := issuerRegistry{
+		byKeyIdentifier: make(map[string]*issuerCapabilities),
+		bySHA256:        make(map[[sha256.Size]byte]*issuerCapabilities),
+	}
+	for i, cri := range cris {
+		ic := &issuerCapabilities{
+			Source:             ISSUERSOURCE_REGISTRY,
+			IsRoot:             cri.Root,
+			TlsCapable:         cri.TlsCapable,
+			TlsEvCapable:       cri.TlsEvCapable,
+			SmimeCapable:       cri.SmimeCapable,
+			CodeSigningCapable: cri.CodeSigningCapable,
+			HasVMCAudit:        cri.HasVMCAudit,
+			DefaultProfile:     -1,
+		}
+		var ski []byte
+		var err error
+		if cri.SKI != "" {
+			if ski, err = hex.DecodeString(strings.ReplaceAll(cri.SKI, ":", "")); err != nil || len(ski) == 0 {
+				return registry, fmt.Errorf("issuer %d: unrecognised SKI: %s", i, cri.SKI)
+			}
+		}
+		if cri.Certificate != "" {
+			block, _ := pem.Decode([]byte(cri.Certificate))
+			if block == nil {
+				return registry, fmt.Errorf("issuer %d: unrecognised certificate", i)
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return registry, fmt.Errorf("issuer %d: unrecognised certificate: %w", i, err)
+			} else if !cert.IsCA {
+				return registry, fmt.Errorf("issuer %d: not a CA certificate", i)
+			}
+			if ski == nil {
+				ski = cert.SubjectKeyId
+			}
+			ic.IsRoot = ic.IsRoot || isRootCertificate(cert)
+			registry.bySHA256[sha256.Sum256(cert.Raw)] = ic
+		}
+		if len(ski) == 0 {
+			return registry, fmt.Errorf("issuer %d: SKI must be specified", i)
+		}
+		if cri.DefaultProfile != "" {
+			var ok bool
+			if ic.DefaultProfile, ok = linter.GetProfileId(cri.DefaultProfile); !ok || ic.DefaultProfile == linter.AUTODETECT {
+				return registry, fmt.Errorf("issuer %d: unrecognised default profile: %s", i, cri.DefaultProfile)
+			}
+		}
+		registry.byKeyIdentifier[base64.StdEncoding.EncodeToString(ski)] = ic
+	}
+	return registry, nil
+}
+
+// lookupIssuerByKeyIdentifier returns the capabilities of the CA with the specified Base64-encoded Subject Key
+// Identifier, from the issuer registry or else from the CCADB data (a loaded CCADB snapshot supersedes the CCADB data
+// that is compiled into pkimetal).
+func lookupIssuerByKeyIdentifier(keyIdentifier string) *issuerCapabilities {
+	if ic, ok := registeredIssuers.byKeyIdentifier[keyIdentifier]; ok {
+		return ic
+	} else if snapshot := activeCCADBSnapshot.Load(); snapshot != nil {
+		return snapshot.byKeyIdentifier[keyIdentifier]
+	} else if cic := ccadb_data.GetIssuerCapabilitiesByKeyIdentifier(keyIdentifier); cic != nil {
+		return &issuerCapabilities{
+			Source:             ISSUERSOURCE_CCADB,
+			IsRoot:             cic.CertificateRecordType == ccadb_data.CCADB_RECORD_ROOT,
+			TlsCapable:         cic.TlsCapable,
+			TlsEvCapable:       cic.TlsEvCapable,
+			SmimeCapable:       cic.SmimeCapable,
+			CodeSigningCapable: cic.CodeSigningCapable,
+			DefaultProfile:     -1,
+		}
+	}
+	return nil
+}
+
+// lookupCACertBySHA256 returns the capabilities of the CA certificate with the specified SHA-256 fingerprint, from
+// the issuer registry (in which the certificate may also be registered by its Subject Key Identifier) or else from
+// the CCADB data.
+func lookupCACertBySHA256(fingerprint [sha256.Size]byte, subjectKeyId []byte) *issuerCapabilities {
+	if ic, ok := registeredIssuers.bySHA256[fingerprint]; ok {
+		return ic
+	} else if ic, ok = registeredIssuers.byKeyIdentifier[base64.StdEncoding.EncodeToString(subjectKeyId)]; ok && len(subjectKeyId) > 0 {
+		return ic
+	} else if snapshot := activeCCADBSnapshot.Load(); snapshot != nil {
+		return snapshot.bySHA256[fingerprint]
+	} else if cic := ccadb_data.GetCACertCapabilitiesBySHA256(fingerprint); cic != nil {
+		return &issuerCapabilities{
+			Source:             ISSUERSOURCE_CCADB,
+			TlsCapable:         cic.TlsCapable,
+			TlsEvCapable:       cic.TlsEvCapable,
+			SmimeCapable:       cic.SmimeCapable,
+			CodeSigningCapable: cic.CodeSigningCapable,
+			HasVMCAudit:        cic.HasVMCAudit,
+			DefaultProfile:     -1,
+		}
+	}
+	return nil
+}
+
+// issuerDefaultProfile returns the CA's default profile, if it has one that applies to the input.
+func (ri *RequestInfo) issuerDefaultProfile(ic *issuerCapabilities) (linter.ProfileId, bool) {
+	if ic.DefaultProfile == -1 || !ri.isApplicableProfile(linter.AllProfiles[ic.DefaultProfile]) {
+		return -1, false
+	}
+	ri.explain("Issuer's default profile: %s", ic.DefaultProfile)
+	return ic.DefaultProfile, true
+}
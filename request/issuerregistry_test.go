@@ -0,0 +1,99 @@
+package request
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+// withIssuerRegistry replaces the loaded issuer registry for the duration of the test.
+func withIssuerRegistry(t *testing.T, cris ...config.RegisteredIssuer) {
+	t.Helper()
+	saved := registeredIssuers
+	t.Cleanup(func() { registeredIssuers = saved })
+
+	var err error
+	if registeredIssuers, err = compileIssuerRegistry(cris); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadIssuerRegistry(t *testing.T) {
+	file := writeTempFile(t, "issuers.yaml", `issuers:
+  - ski: "88:24:a8:65"
+    tlsCapable: true
+    defaultProfile: tbr_leaf_tlsserver_dv
+`)
+
+	cris, err := config.LoadIssuerRegistry(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(cris) != 1 || cris[0].SKI != "88:24:a8:65" || !cris[0].TlsCapable || cris[0].TlsEvCapable || cris[0].DefaultProfile != "tbr_leaf_tlsserver_dv" {
+		t.Fatalf("got %+v", cris)
+	}
+	registry, err := compileIssuerRegistry(cris)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if ic := registry.byKeyIdentifier["iCSoZQ=="]; ic == nil || ic.Source != ISSUERSOURCE_REGISTRY || !ic.TlsCapable || ic.DefaultProfile != linter.TBR_LEAF_TLSSERVER_DV {
+		t.Errorf("got %+v", registry)
+	}
+}
+
+func TestCompileIssuerRegistry(t *testing.T) {
+	leafPEM := testcasePEM(t, "vmc_certificate.crt")
+	for _, tc := range []struct {
+		name    string
+		issuer  config.RegisteredIssuer
+		wantErr bool
+	}{
+		{"valid", config.RegisteredIssuer{SKI: "0102", DefaultProfile: "rfc5280_leaf"}, false},
+		{"no identification", config.RegisteredIssuer{TlsCapable: true}, true},
+		{"bad SKI", config.RegisteredIssuer{SKI: "xyz"}, true},
+		{"bad certificate", config.RegisteredIssuer{Certificate: "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"}, true},
+		{"not a CA", config.RegisteredIssuer{Certificate: leafPEM}, true},
+		{"bad default profile", config.RegisteredIssuer{SKI: "0102", DefaultProfile: "this_profile_does_not_exist"}, true},
+		{"autodetect", config.RegisteredIssuer{SKI: "0102", DefaultProfile: "autodetect"}, true},
+	} {
+		if _, err := compileIssuerRegistry([]config.RegisteredIssuer{tc.issuer}); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestGetProfile_IssuerRegistry(t *testing.T) {
+	for _, tc := range []struct {
+		file           string
+		defaultProfile string
+		want           string
+	}{
+		// An issuer that is absent from the CCADB data is found in the issuer registry.
+		{"vmc_certificate.crt", "", "bimigroup_leaf_verifiedmark"},
+		// The issuer's default profile is used when its capabilities don't determine a more specific profile...
+		{"docsigning.crt", "rfc5280_leaf_smime", "rfc5280_leaf_smime"},
+		// ...but only if it applies to the certificate.
+		{"docsigning.crt", "rfc5280_subordinate", "rfc5280_leaf_documentsigning"},
+	} {
+		t.Run(tc.file+" "+tc.defaultProfile, func(t *testing.T) {
+			der := testcaseDER(t, tc.file)
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			withIssuerRegistry(t, config.RegisteredIssuer{SKI: hex.EncodeToString(cert.AuthorityKeyId), HasVMCAudit: true, DefaultProfile: tc.defaultProfile})
+
+			ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, decodedInput: der, cert: cert}
+			if !ri.GetProfile("") {
+				t.Fatal("expected a profile to be autodetected")
+			} else if name := ri.profileId.String(); name != tc.want {
+				t.Errorf("got %s, want %s", name, tc.want)
+			} else if !strings.Contains(strings.Join(ri.detectionReasons, "; "), "found in the issuer registry; capabilities: VMC audit") {
+				t.Errorf("got %q, want the issuer registry lookup to be explained", ri.detectionReasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package request
+
+import (
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+
+	"go.uber.org/zap"
+)
+
+const (
+	JSON2_VERSION      = 2
+	JSON2_CONTENT_TYPE = "application/vnd.pkimetal.v2+json"
+)
+
+const (
+	LINTERSTATUS_COMPLETED     = "completed"
+	LINTERSTATUS_FAILED        = "failed"
+	LINTERSTATUS_TIMEDOUT      = "timed_out"
+	LINTERSTATUS_NOTAVAILABLE  = "not_available"
+	LINTERSTATUS_NOTAPPLICABLE = "not_applicable"
+)
+
+// json2Response is the versioned "json2" response envelope, which reports the meta information that the flat
+// "json" response encodes as meta findings as typed fields instead.
+type json2Response struct {
+	Version         int            `json:"version"`
+	PkimetalVersion string         `json:"pkimetal_version"`
+	Profile         *json2Profile  `json:"profile"`
+	AsOf            string         `json:"as_of,omitempty"` // When time-dependent requirements were evaluated (RFC3339).
+	Linters         []linterReport `json:"linters"`
+	Summary         map[string]int `json:"summary"`
+	// Findings that are only suppressed because their requirements are not yet effective as of as_of.
+	NotYetEffective []json2Finding `json:"not_yet_effective,omitempty"`
+}
+
+type json2Profile struct {
+	Id           string   `json:"id"` // The profile's stable identifier, which is its name.
+	Name         string   `json:"name"`
+	Autodetected bool     `json:"autodetected"`
+	Reasons      []string `json:"reasons,omitempty"` // The autodetection decision trail.
+}
+
+type linterReport struct {
+	Name      string         `json:"name"`
+	Version   string         `json:"version"`
+	Status    string         `json:"status"`
+	QueuedNs  int64          `json:"queued_ns"`
+	RuntimeNs int64          `json:"runtime_ns"`
+	Findings  []json2Finding `json:"findings"`
+}
+
+type json2Finding struct {
+	Linter   string       `json:"linter,omitempty"` // Only set in the not_yet_effective section.
+	Severity string       `json:"severity"`
+	Finding  string       `json:"finding"`
+	Field    string       `json:"field,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Waived   *json2Waived `json:"waived,omitempty"`
+	// Set if a configured severity mapping changed the severity reported by the linter.
+	OriginalSeverity string `json:"original_severity,omitempty"`
+	EffectiveDate    string `json:"effective_date,omitempty"` // Only set in the not_yet_effective section.
+}
+
+type json2Waived struct {
+	Justification    string `json:"justification"`
+	Ticket           string `json:"ticket,omitempty"`
+	Expires          string `json:"expires,omitempty"`
+	OriginalSeverity string `json:"original_severity,omitempty"`
+}
+
+// makeJSON2Response groups the results by linter, omitting the meta findings that are reported as typed fields.
+// Findings that are not yet effective are reported separately.  The profile and as-of date are only reported if
+// linting took place.
+func (ri *RequestInfo) makeJSON2Response(lrespFiltered []LintResult) json2Response {
+	jresp := json2Response{
+		Version:         JSON2_VERSION,
+		PkimetalVersion: linter.VersionString(config.PkimetalVersion),
+		Linters:         []linterReport{{Name: linter.PKIMETAL_NAME, Version: linter.VersionString(config.PkimetalVersion), Status: LINTERSTATUS_COMPLETED}},
+		Summary:         make(map[string]int, len(linter.SeverityString)),
+	}
+	if ri.linterReports != nil {
+		jresp.Profile = &json2Profile{Id: ri.profileId.String(), Name: ri.profileId.String(), Autodetected: ri.autodetected}
+		if ri.autodetected {
+			jresp.Profile.Reasons = ri.detectionReasons
+		}
+		jresp.Linters = append(jresp.Linters, ri.linterReports...)
+		if !ri.asOf.IsZero() {
+			jresp.AsOf = ri.asOf.UTC().Format(time.RFC3339)
+		}
+	}
+	for _, severity := range linter.SeverityString {
+		jresp.Summary[severity] = 0
+	}
+
+	reportIndex := make(map[string]int, len(jresp.Linters))
+	for i := range jresp.Linters {
+		jresp.Linters[i].Findings = []json2Finding{}
+		reportIndex[jresp.Linters[i].Name] = i
+	}
+	for _, lres := range lrespFiltered {
+		if lres.structured {
+			continue
+		}
+		finding := json2Finding{
+			Severity:         lres.Severity,
+			Finding:          lres.Finding,
+			Field:            lres.Field,
+			Code:             lres.Code,
+			OriginalSeverity: lres.OriginalSeverity,
+		}
+		if lres.Waived != nil {
+			finding.Waived = &json2Waived{
+				Justification:    lres.Waived.Justification,
+				Ticket:           lres.Waived.Ticket,
+				Expires:          lres.Waived.Expires,
+				OriginalSeverity: lres.Waived.OriginalSeverity,
+			}
+		}
+		if lres.NotYetEffective != "" {
+			finding.Linter, finding.EffectiveDate = lres.Linter, lres.NotYetEffective
+			jresp.NotYetEffective = append(jresp.NotYetEffective, finding)
+			continue
+		}
+
+		i, ok := reportIndex[lres.Linter]
+		if !ok {
+			i = len(jresp.Linters)
+			reportIndex[lres.Linter] = i
+			jresp.Linters = append(jresp.Linters, linterReport{Name: lres.Linter, Status: LINTERSTATUS_COMPLETED, Findings: []json2Finding{}})
+		}
+		jresp.Linters[i].Findings = append(jresp.Linters[i].Findings, finding)
+		jresp.Summary[lres.Severity]++
+	}
+
+	return jresp
+}
+
+func (ri *RequestInfo) sendJSON2Response(fhctx *fasthttp.RequestCtx, lrespFiltered []LintResult) int {
+	// Encode and send the results as a json2 envelope.
+	fhctx.SetContentType(JSON2_CONTENT_TYPE + "; charset=UTF-8")
+
+	j := json.NewEncoder(fhctx)
+	j.SetEscapeHTML(false)
+	if config.Config.Response.JsonPrettyPrint {
+		j.SetIndent("", "  ")
+	}
+	if err := j.Encode(ri.makeJSON2Response(lrespFiltered)); err != nil {
+		logger.SetDetails(fhctx, zap.ErrorLevel, "Failed to encode JSON", nil, nil)
+	}
+
+	return fasthttp.StatusOK
+}
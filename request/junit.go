@@ -0,0 +1,134 @@
+package request
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	"github.com/valyala/fasthttp"
+
+	"go.uber.org/zap"
+)
+
+// JUnit XML report structures, as understood by Jenkins and GitLab.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out,omitempty"`
+	runtime   time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSeconds formats a duration as the decimal number of seconds used by JUnit time attributes.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+var junitFailureSeverity linter.SeverityLevel
+
+func init() {
+	var ok bool
+	if junitFailureSeverity, ok = linter.Severity[config.Config.Response.JunitFailureSeverity]; !ok {
+		panic(fmt.Errorf("response.junitFailureSeverity: unrecognised severity: %s", config.Config.Response.JunitFailureSeverity))
+	}
+}
+
+// makeJUnitReport converts the results into a JUnit report, with one testsuite per linter and one testcase per
+// finding.  Findings at or above the failure severity are reported as failures, unless they are not yet effective.
+// Meta findings are reported as the testsuite's output, and the linter's runtime becomes the testsuite's time.
+func makeJUnitReport(lrespFiltered []LintResult, failureSeverity linter.SeverityLevel) junitTestSuites {
+	report := junitTestSuites{Name: linter.PKIMETAL_NAME}
+	suiteIndex := make(map[string]int)
+	for _, lres := range lrespFiltered {
+		s, ok := suiteIndex[lres.Linter]
+		if !ok {
+			s = len(report.Suites)
+			suiteIndex[lres.Linter] = s
+			report.Suites = append(report.Suites, junitTestSuite{Name: lres.Linter})
+		}
+		suite := &report.Suites[s]
+
+		finding := lres.Finding
+		if lres.Field != "" {
+			finding += " [" + lres.Field + "]"
+		}
+		severity := linter.Severity[lres.Severity]
+		if severity == linter.SEVERITY_META {
+			if lres.timing != nil {
+				suite.runtime += lres.timing.Runtime
+			}
+			suite.SystemOut += finding + "\n"
+			continue
+		}
+
+		testCase := junitTestCase{Name: lres.Code, ClassName: linter.PKIMETAL_NAME + "." + lres.Linter}
+		if testCase.Name == "" {
+			testCase.Name = lres.Finding
+		}
+		if lres.NotYetEffective != "" {
+			finding += " (not yet effective: effective from " + lres.NotYetEffective + ")"
+		}
+		if severity >= failureSeverity && lres.NotYetEffective == "" {
+			testCase.Failure = &junitFailure{Message: lres.Finding, Type: strings.ToUpper(lres.Severity), Text: finding}
+			suite.Failures++
+		} else {
+			testCase.SystemOut = strings.ToUpper(lres.Severity) + ": " + finding
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	var total time.Duration
+	for i := range report.Suites {
+		report.Suites[i].Time = junitSeconds(report.Suites[i].runtime)
+		report.Tests += report.Suites[i].Tests
+		report.Failures += report.Suites[i].Failures
+		total += report.Suites[i].runtime
+	}
+	report.Time = junitSeconds(total)
+	return report
+}
+
+func sendJUNITResponse(fhctx *fasthttp.RequestCtx, lrespFiltered []LintResult) int {
+	// Encode and send the results as a JUnit XML report.
+	fhctx.SetContentType("application/xml; charset=UTF-8")
+
+	fhctx.WriteString(xml.Header)
+	x := xml.NewEncoder(fhctx)
+	x.Indent("", "  ")
+	if err := x.Encode(makeJUnitReport(lrespFiltered, junitFailureSeverity)); err != nil {
+		logger.SetDetails(fhctx, zap.ErrorLevel, "Failed to encode JUnit XML", nil, nil)
+	}
+	fhctx.WriteString("\n")
+
+	return fasthttp.StatusOK
+}
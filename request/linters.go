@@ -16,18 +16,31 @@ type linterInfo struct {
 	Instances int
 	Version   string
 	Url       string
+	// The datasets that the linter depends on, and when they were published.
+	DataSnapshots []linter.DataSnapshot `json:",omitempty"`
 }
 
 func Linters(fhctx *fasthttp.RequestCtx) {
-	// Create a slice of linter names/versions.
-	linterInfos := []linterInfo{}
+	// Create a slice of linter names/versions, starting with pkimetal itself (which uses the CCADB data for profile
+	// autodetection).
+	linterInfos := []linterInfo{{
+		Name:          linter.PKIMETAL_NAME,
+		Instances:     1,
+		Version:       linter.VersionString(config.PkimetalVersion),
+		Url:           "https://github.com/pkimetal/pkimetal",
+		DataSnapshots: []linter.DataSnapshot{ccadbDataSnapshot()},
+	}}
 	for _, l := range linter.Linters {
-		linterInfos = append(linterInfos, linterInfo{
+		li := linterInfo{
 			Name:      l.Name,
-			Instances: l.NumInstances,
+			Instances: l.RunningInstances(),
 			Version:   linter.VersionString(l.Version),
 			Url:       l.Url,
-		})
+		}
+		if l.DataSnapshots != nil {
+			li.DataSnapshots = l.DataSnapshots()
+		}
+		linterInfos = append(linterInfos, li)
 	}
 
 	// Encode and send the results as JSON.
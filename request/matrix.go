@@ -0,0 +1,142 @@
+package request
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+
+	"go.uber.org/zap"
+)
+
+const PROFILES_ALL = "all"
+
+// ComplianceMatrix reports the outcome of linting the same input against each of several profiles.
+type ComplianceMatrix struct {
+	Linters  []string // The matrix's columns, starting with pkimetal itself.
+	Profiles []ProfileCompliance
+}
+
+// ProfileCompliance is a row of the compliance matrix.
+type ProfileCompliance struct {
+	Profile string
+	// For each linter, the severity of its most severe unwaived finding ("meta" if none), irrespective of the
+	// requested minimum severity level; or, if the linter did not complete, its status (e.g., "not_applicable").
+	MaxSeverity map[string]string
+	Results     []LintResult
+}
+
+// GetComplianceProfiles selects compliance matrix mode, and determines the profiles to lint against: either every
+// profile that applies to the input (if profiles is "all"), or the profiles in the comma-separated list.
+func (ri *RequestInfo) GetComplianceProfiles(profiles string) bool {
+	var ids []linter.ProfileId
+	if strings.EqualFold(profiles, PROFILES_ALL) {
+		for _, id := range slices.Sorted(maps.Keys(linter.AllProfiles)) {
+			if ri.isApplicableProfile(linter.AllProfiles[id]) {
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		for _, name := range strings.Split(profiles, ",") {
+			// Autodetection is never performed in compliance matrix mode.
+			probe := RequestInfo{endpoint: ri.endpoint}
+			name = strings.TrimSpace(name)
+			if id, ok := linter.GetProfileId(name); !ok || id == linter.AUTODETECT || !probe.GetProfile(name) {
+				return false
+			} else if !slices.Contains(ids, probe.profileId) {
+				ids = append(ids, probe.profileId)
+			}
+		}
+	}
+	ri.complianceProfileIds = ids
+	return len(ids) > 0
+}
+
+// isApplicableProfile determines whether or not the profile applies to the type of input, and, for certificates, to
+// its position in the PKI hierarchy and whether or not it is a precertificate.
+func (ri *RequestInfo) isApplicableProfile(profile linter.Profile) bool {
+	if profile.Type != endpointDocumentType[ri.endpoint] {
+		return false
+	} else if ri.cert == nil {
+		return true
+	}
+
+	hierarchy := linter.HIERARCHY_LEAF
+	if isRootCertificate(ri.cert) {
+		hierarchy = linter.HIERARCHY_ROOT
+	} else if ri.cert.BasicConstraintsValid && ri.cert.IsCA {
+		hierarchy = linter.HIERARCHY_SUBORDINATE
+	}
+	return profile.Hierarchy == hierarchy && profile.Precertificate == hasPrecertificatePoison(ri.cert)
+}
+
+// lintMatrix lints the input against each of the profiles, running up to the configured number of profiles
+// concurrently, and returns the compliance matrix.
+func (ri *RequestInfo) lintMatrix(ctx context.Context, ids []linter.ProfileId) ComplianceMatrix {
+	matrix := ComplianceMatrix{
+		Linters:  []string{linter.PKIMETAL_NAME},
+		Profiles: make([]ProfileCompliance, len(ids)),
+	}
+	for _, l := range linter.Linters {
+		matrix.Linters = append(matrix.Linters, l.Name)
+	}
+
+	semaphore := make(chan struct{}, max(config.Config.Server.MatrixConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			pri := *ri
+			pri.profileId, pri.autodetected, pri.detectionReasons = id, false, nil
+			results := pri.lint(ctx)
+			if results == nil {
+				results = []LintResult{}
+			}
+			matrix.Profiles[i] = ProfileCompliance{Profile: id.String(), MaxSeverity: pri.matrixRow(), Results: results}
+		}()
+	}
+	wg.Wait()
+
+	return matrix
+}
+
+// matrixRow summarises the outcome of lint() for each linter.
+func (ri *RequestInfo) matrixRow() map[string]string {
+	row := map[string]string{linter.PKIMETAL_NAME: linter.SeverityString[ri.maxSeverities[linter.PKIMETAL_NAME]]}
+	for _, report := range ri.linterReports {
+		if report.Status == LINTERSTATUS_COMPLETED {
+			row[report.Name] = linter.SeverityString[ri.maxSeverities[report.Name]]
+		} else {
+			row[report.Name] = report.Status
+		}
+	}
+	return row
+}
+
+func sendMatrixResponse(fhctx *fasthttp.RequestCtx, matrix ComplianceMatrix) int {
+	// Encode and send the compliance matrix as JSON.
+	fhctx.SetContentType("application/json; charset=UTF-8")
+	j := json.NewEncoder(fhctx)
+	j.SetEscapeHTML(false)
+	if config.Config.Response.JsonPrettyPrint {
+		j.SetIndent("", "  ")
+	}
+	if err := j.Encode(matrix); err != nil {
+		logger.SetDetails(fhctx, zap.ErrorLevel, "Failed to encode JSON", nil, nil)
+	}
+
+	return fasthttp.StatusOK
+}
@@ -0,0 +1,136 @@
+package request
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+func TestGetComplianceProfiles(t *testing.T) {
+	cert, err := x509.ParseCertificate(testcaseDER(t, "tls_ov_certificate.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every applicable profile: leaf certificate profiles that are not precertificate profiles.
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+	if !ri.GetComplianceProfiles("all") {
+		t.Fatal("expected profiles to be selected")
+	}
+	for _, id := range []linter.ProfileId{linter.RFC5280_LEAF, linter.TBR_LEAF_TLSSERVER_OV} {
+		if !slices.Contains(ri.complianceProfileIds, id) {
+			t.Errorf("expected %s to be selected", id)
+		}
+	}
+	for _, id := range ri.complianceProfileIds {
+		if profile := linter.AllProfiles[id]; profile.Hierarchy != linter.HIERARCHY_LEAF || profile.Precertificate {
+			t.Errorf("did not expect %s to be selected", id)
+		}
+	}
+
+	// An explicit list, in which duplicates are ignored.
+	ri = RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+	if !ri.GetComplianceProfiles("rfc5280_leaf, tbr_leaf_tlsserver_ov,rfc5280_leaf") || !slices.Equal(ri.complianceProfileIds, []linter.ProfileId{linter.RFC5280_LEAF, linter.TBR_LEAF_TLSSERVER_OV}) {
+		t.Errorf("got %v", ri.complianceProfileIds)
+	}
+
+	for _, profiles := range []string{"rfc5280_leaf,", "autodetect", "rfc5280_leaf,this_profile_does_not_exist", "rfc2986_csr"} {
+		ri = RequestInfo{endpoint: ENDPOINT_LINTCERT, cert: cert}
+		if ri.GetComplianceProfiles(profiles) {
+			t.Errorf("%s: expected false", profiles)
+		}
+	}
+}
+
+func TestLintMatrix(t *testing.T) {
+	saved := linter.Linters
+	t.Cleanup(func() { linter.Linters = saved })
+	fake := &linter.Linter{Name: "fake", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 2), Unsupported: []linter.ProfileId{linter.RFC5280_ARL}}
+	linter.Linters = linter.LinterSlice{{Name: "absent"}, fake}
+	go func() {
+		for lreq := range fake.ReqChannel {
+			if lreq.ProfileId == linter.TBR_CRL {
+				lreq.RespChannel <- linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_a", Code: "e_a"}
+			}
+			lreq.RespChannel <- linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_META, Structured: true, Timing: &linter.LintingTiming{}}
+			lreq.RespChannel <- linter.LintingResult{LinterName: linter.PKIMETAL_NAME, Severity: linter.SEVERITY_META, Finding: linter.PKIMETAL_ENDOFRESULTS}
+		}
+	}()
+	t.Cleanup(func() { close(fake.ReqChannel) })
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCRL, minimumSeverity: linter.SEVERITY_ERROR}
+	if !ri.GetComplianceProfiles("rfc5280_crl,tbr_crl,rfc5280_arl") {
+		t.Fatal("expected profiles to be selected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	matrix := ri.lintMatrix(ctx, ri.complianceProfileIds)
+
+	if !slices.Equal(matrix.Linters, []string{"pkimetal", "absent", "fake"}) {
+		t.Errorf("got linters %v", matrix.Linters)
+	}
+	for i, want := range []struct {
+		profile  string
+		fake     string
+		nresults int
+	}{
+		{"rfc5280_crl", "meta", 0},
+		{"tbr_crl", "error", 1},
+		{"rfc5280_arl", LINTERSTATUS_NOTAPPLICABLE, 0},
+	} {
+		row := matrix.Profiles[i]
+		if row.Profile != want.profile || row.MaxSeverity["fake"] != want.fake || row.MaxSeverity["absent"] != LINTERSTATUS_NOTAVAILABLE || row.MaxSeverity["pkimetal"] != "meta" {
+			t.Errorf("row %d: got %+v", i, row)
+		} else if len(row.Results) != want.nresults {
+			t.Errorf("row %d: got results %+v", i, row.Results)
+		}
+	}
+}
+
+func TestLintMatrix_Concurrency(t *testing.T) {
+	savedConcurrency := config.Config.Server.MatrixConcurrency
+	t.Cleanup(func() { config.Config.Server.MatrixConcurrency = savedConcurrency })
+	config.Config.Server.MatrixConcurrency = 2
+
+	saved := linter.Linters
+	t.Cleanup(func() { linter.Linters = saved })
+	fake := &linter.Linter{Name: "fake", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 8)}
+	linter.Linters = linter.LinterSlice{fake}
+	var mutex sync.Mutex
+	var inFlight, maxInFlight int
+	go func() {
+		for lreq := range fake.ReqChannel {
+			go func() {
+				mutex.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mutex.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mutex.Lock()
+				inFlight--
+				mutex.Unlock()
+				lreq.RespChannel <- linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_META, Structured: true, Timing: &linter.LintingTiming{}}
+				lreq.RespChannel <- linter.LintingResult{LinterName: linter.PKIMETAL_NAME, Severity: linter.SEVERITY_META, Finding: linter.PKIMETAL_ENDOFRESULTS}
+			}()
+		}
+	}()
+	t.Cleanup(func() { close(fake.ReqChannel) })
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTCRL, minimumSeverity: linter.SEVERITY_ERROR}
+	if !ri.GetComplianceProfiles("all") || len(ri.complianceProfileIds) <= 2 {
+		t.Fatalf("got profiles %v, want more than 2", ri.complianceProfileIds)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ri.lintMatrix(ctx, ri.complianceProfileIds)
+	if maxInFlight != 2 {
+		t.Errorf("got up to %d profiles linted concurrently, want 2", maxInFlight)
+	}
+}
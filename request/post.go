@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,13 +23,30 @@ import (
 )
 
 type RequestInfo struct {
-	endpoint        Endpoint
-	profileId       linter.ProfileId
-	minimumSeverity linter.SeverityLevel
+	endpoint             Endpoint
+	profileId            linter.ProfileId
+	autodetected         bool               // Whether profileId was autodetected.
+	detectionReasons     []string           // Autodetection decision trail, populated by GetProfile().
+	complianceProfileIds []linter.ProfileId // Compliance matrix mode: the profiles to lint against, or nil.
+	minimumSeverity      linter.SeverityLevel
+	checksAdded          []string  // Check codes/globs to include.
+	checksDisabled       []string  // Check codes/globs to exclude.
+	asOf                 time.Time // Evaluate time-dependent requirements as of this time (if zero, as of now).
+	priority             linter.PriorityClass
 	// Input(s), in various original/processed forms.
 	b64Input     []byte // PEM or base64-encoded string.
 	decodedInput []byte
 	cert         *x509.Certificate
+	issuer       *x509.Certificate
+	chain        []*x509.Certificate
+	csr          *x509.CertificateRequest
+	key          any
+	// Outcome of each linter, and each linter's most severe unwaived finding, populated by lint().
+	linterReports []linterReport
+	maxSeverities map[string]linter.SeverityLevel
+	// Pass/fail decision, populated by lint() if decisionMode is set.
+	decisionMode bool
+	decision     *decision
 }
 
 type LintResult struct {
@@ -37,6 +55,14 @@ type LintResult struct {
 	Field    string `json:"Field,omitempty"`
 	Code     string `json:"Code,omitempty"`
 	Severity string
+	Waived   *linter.Waived `json:"Waived,omitempty"`
+	// Set if a configured severity mapping changed the severity reported by the linter.
+	OriginalSeverity string `json:"OriginalSeverity,omitempty"`
+	// Set (to the date from which the requirement is effective) if the finding is not yet effective as of the
+	// request's as-of date.
+	NotYetEffective string                `json:"NotYetEffective,omitempty"`
+	structured      bool                  // See linter.LintingResult.Structured.
+	timing          *linter.LintingTiming // See linter.LintingResult.Timing.
 }
 
 func getResponseFormat(fhctx *fasthttp.RequestCtx) config.ResponseFormat {
@@ -50,6 +76,16 @@ func getResponseFormat(fhctx *fasthttp.RequestCtx) config.ResponseFormat {
 			return config.RESPONSEFORMAT_JSON
 		case "text/plain":
 			return config.RESPONSEFORMAT_TEXT
+		case "application/sarif+json":
+			return config.RESPONSEFORMAT_SARIF
+		case "application/xml":
+			return config.RESPONSEFORMAT_JUNIT
+		case JSON2_CONTENT_TYPE:
+			return config.RESPONSEFORMAT_JSON2
+		case "application/x-ndjson":
+			return config.RESPONSEFORMAT_NDJSON
+		case "text/event-stream":
+			return config.RESPONSEFORMAT_SSE
 		}
 	}
 
@@ -70,97 +106,60 @@ func POST(fhctx *fasthttp.RequestCtx, path string) int {
 		var responseFormat config.ResponseFormat
 		var errorMessage string
 		var lrespFiltered []LintResult
+		var profiles string
+		var matrix ComplianceMatrix
+		var retryAfter time.Duration
+		admitted := true
 		if !ri.GetPOSTEndpoint(path) {
 			status = fasthttp.StatusNotFound
 			logger.SetDetails(fhctx, zap.InfoLevel, "Invalid endpoint", nil, nil)
 		} else if responseFormat = getResponseFormat(fhctx); responseFormat == -1 {
 			errorMessage = "Unrecognised response format"
+		} else if ri.decisionMode, err = parseDecisionMode(paramS(fhctx, "decision")); err != nil {
+			errorMessage = "Unrecognised decision mode"
+		} else if ri.decisionMode && isStreamingResponseFormat(responseFormat) {
+			errorMessage = "Decision mode is not supported with streaming response formats"
 		} else if requestBody := fhctx.Request.Body(); len(requestBody) == 0 {
 			errorMessage = "Empty request body"
 		} else if err = ri.GetInput(fhctx); err != nil {
 			errorMessage = "Unrecognised input"
-		} else if !ri.GetProfile(paramS(fhctx, "profile")) {
+		} else if err = ri.parseIssuerInput(paramB(fhctx, "b64issuer")); err != nil {
+			errorMessage = "Unrecognised issuer"
+		} else if profiles = paramS(fhctx, "profiles"); profiles != "" && paramS(fhctx, "profile") != "" {
+			errorMessage = "The profile and profiles parameters are mutually exclusive"
+		} else if profiles != "" && responseFormat != config.RESPONSEFORMAT_JSON {
+			errorMessage = "Compliance matrix mode only supports the json response format"
+		} else if profiles != "" && ri.decisionMode {
+			errorMessage = "Decision mode is not supported with compliance matrix mode"
+		} else if profiles != "" && !ri.GetComplianceProfiles(profiles) {
+			errorMessage = "Unrecognised profiles"
+		} else if profiles == "" && !ri.GetProfile(paramS(fhctx, "profile")) {
 			errorMessage = "Unrecognised profile"
 		} else if ri.minimumSeverity, ok = linter.Severity[paramS(fhctx, "severity")]; !ok {
 			errorMessage = "Unrecognised severity"
-		} else {
-			// Construct the linting request.
-			lreq := linter.LintingRequest{
-				Ctx:          ctxWithDeadline,
-				B64Input:     utils.B2S(ri.b64Input),
-				DecodedInput: ri.decodedInput,
-				Cert:         ri.cert,
-				ProfileId:    ri.profileId,
-				QueuedAt:     time.Now(),
-				RespChannel:  make(chan linter.LintingResult),
-			}
-
-			// Send the linting request to (one of) each linter's backend(s), for each linter that is both available and applicable.
-			var lresp []linter.LintingResult
-			nlresp := 0
-			for _, l := range linter.Linters {
-				if isApplicable := !slices.Contains(l.Unsupported, lreq.ProfileId); isApplicable && (l.NumInstances > 0) {
-					l.ReqChannel <- lreq
-					nlresp++
-				} else {
-					lresp = append(lresp, linter.LintingResult{
-						LinterName: l.Name,
-						Severity:   linter.SEVERITY_META,
-						Finding:    fmt.Sprintf("%s: Not used [Available:%t, Applicable:%t]", l.Name, (l.NumInstances > 0), isApplicable),
-					})
-				}
-			}
-
-			// Wait for all of the used linters to finish writing results to the
-			// response channel, or for the request deadline to be exceeded.
-			for nlresp > 0 {
-				select {
-				case resp := <-lreq.RespChannel:
-					if resp.LinterName == linter.PKIMETAL_NAME && resp.Finding == linter.PKIMETAL_ENDOFRESULTS {
-						nlresp--
-					} else {
-						lresp = append(lresp, resp)
-					}
-				case <-ctxWithDeadline.Done():
-					// The linter backends observe the same deadline and will stop
-					// writing to the response channel, so abandon the wait.
-					nlresp = 0
-				}
-			}
-
-			// Sort the results by Linter Name, then Severity (most severe first), then Finding description.
-			sort.Slice(lresp, func(i, j int) bool {
-				if lresp[i].LinterName != lresp[j].LinterName {
-					return lresp[i].LinterName < lresp[j].LinterName
-				} else if lresp[i].Severity != lresp[j].Severity {
-					return lresp[i].Severity > lresp[j].Severity
-				} else {
-					return lresp[i].Finding < lresp[j].Finding
-				}
-			})
-
-			// Prepend a meta result with the selected profile and the pkimetal version.
-			lresp = append([]linter.LintingResult{{
-				LinterName: linter.PKIMETAL_NAME,
-				Severity:   linter.SEVERITY_META,
-				Finding:    fmt.Sprintf("Profile: %s; Version: %s", linter.AllProfiles[lreq.ProfileId].Name, linter.VersionString(config.PkimetalVersion)),
-			}}, lresp...)
-
-			// Filter out results that are below the requested minimum severity level.
-			for _, lres := range lresp {
-				if lres.Severity >= ri.minimumSeverity {
-					lrespFiltered = append(lrespFiltered, LintResult{
-						Linter:   lres.LinterName,
-						Finding:  lres.Finding,
-						Field:    lres.Field,
-						Code:     lres.Code,
-						Severity: linter.SeverityString[lres.Severity],
-					})
-				}
-			}
+		} else if ri.checksAdded, err = linter.ParseCheckSelection(paramS(fhctx, "include")); err != nil {
+			errorMessage = "Unrecognised check inclusion"
+		} else if ri.checksDisabled, err = linter.ParseCheckSelection(paramS(fhctx, "exclude")); err != nil {
+			errorMessage = "Unrecognised check exclusion"
+		} else if err = ri.GetAsOf(paramS(fhctx, "asof")); err != nil {
+			errorMessage = "Unrecognised asof"
+		} else if ri.priority, err = getPriority(fhctx, linter.PRIORITY_INTERACTIVE); err != nil {
+			errorMessage = "Unrecognised priority"
+		} else if retryAfter, admitted = ri.admit(); !admitted {
+			errorMessage = ADMISSION_REJECTED
+		} else if ri.complianceProfileIds != nil {
+			matrix = ri.lintMatrix(ctxWithDeadline, ri.complianceProfileIds)
+		} else if !isStreamingResponseFormat(responseFormat) {
+			lrespFiltered = ri.lint(ctxWithDeadline)
 		}
 
-		if errorMessage == "" {
+		if errorMessage == "" && isStreamingResponseFormat(responseFormat) {
+			logger.SetDetails(fhctx, zap.InfoLevel, "Streaming Linting Request", nil, nil)
+		} else if errorMessage == "" && ri.complianceProfileIds != nil {
+			logger.SetDetails(fhctx, zap.InfoLevel, "Compliance Matrix Linting Request", nil, []zap.Field{
+				zap.Int("num_profiles", len(matrix.Profiles)),
+			})
+		} else if errorMessage == "" {
 			logger.SetDetails(fhctx, zap.InfoLevel, "Linting Request", nil, []zap.Field{
 				zap.Int("num_results", len(lrespFiltered)),
 			})
@@ -173,6 +172,9 @@ func POST(fhctx *fasthttp.RequestCtx, path string) int {
 				Finding:  errorMessage,
 				Severity: linter.SeverityString[linter.SEVERITY_FATAL],
 			})
+			if ri.decisionMode {
+				ri.decision = &decision{verdict: VERDICT_REJECT, maxSeverity: linter.SEVERITY_FATAL, reasons: []string{errorMessage}}
+			}
 		}
 
 		// Add Cross-Origin Resource Sharing (CORS) response header.
@@ -183,9 +185,31 @@ func POST(fhctx *fasthttp.RequestCtx, path string) int {
 		case config.RESPONSEFORMAT_HTML:
 			status = sendHTMLResponse(fhctx, lrespFiltered)
 		case config.RESPONSEFORMAT_JSON:
-			status = sendJSONResponse(fhctx, lrespFiltered)
+			if errorMessage == "" && ri.complianceProfileIds != nil {
+				status = sendMatrixResponse(fhctx, matrix)
+			} else {
+				status = sendJSONResponse(fhctx, lrespFiltered)
+			}
 		case config.RESPONSEFORMAT_TEXT:
 			status = sendTEXTResponse(fhctx, lrespFiltered)
+		case config.RESPONSEFORMAT_SARIF:
+			status = sendSARIFResponse(fhctx, lrespFiltered)
+		case config.RESPONSEFORMAT_JUNIT:
+			status = sendJUNITResponse(fhctx, lrespFiltered)
+		case config.RESPONSEFORMAT_JSON2:
+			status = ri.sendJSON2Response(fhctx, lrespFiltered)
+		case config.RESPONSEFORMAT_NDJSON, config.RESPONSEFORMAT_SSE:
+			if errorMessage != "" {
+				status = sendStreamError(fhctx, responseFormat, lrespFiltered)
+			} else {
+				status = ri.sendStreamResponse(fhctx, responseFormat)
+			}
+		}
+		if ri.decision != nil {
+			status = ri.decision.setDecisionHeaders(fhctx, status)
+		}
+		if !admitted {
+			status = setTooManyRequests(fhctx, retryAfter, ri.priority)
 		}
 		fhctx.SetStatusCode(status)
 		doneChan <- 0
@@ -194,6 +218,225 @@ func POST(fhctx *fasthttp.RequestCtx, path string) int {
 	return health.CompleteRequest(ctxWithDeadline, doneChan)
 }
 
+// lint sends a linting request to (one of) each applicable linter's backend(s),
+// and returns the sorted results that meet the requested minimum severity level.
+func (ri *RequestInfo) lint(ctx context.Context) []LintResult {
+	return ri.lintEach(ctx, nil)
+}
+
+// lintEach is lint, but also calls linterDone (if not nil) with each linter's
+// sorted and filtered results as soon as that linter has finished.  The returned
+// results include every result, whether or not it was passed to linterDone.
+func (ri *RequestInfo) lintEach(ctx context.Context, linterDone func(linterName string, lrespFiltered []LintResult)) []LintResult {
+	// Construct the linting request.  The linters are only ever sent the base profile of a custom profile, along with
+	// the custom profile's check selection, which the request's own check selection can narrow but not widen.
+	checksAdded, checksDisabled := linter.ProfileChecks(ri.profileId)
+	lreq := linter.LintingRequest{
+		Ctx:            ctx,
+		B64Input:       utils.B2S(ri.b64Input),
+		DecodedInput:   ri.decodedInput,
+		Cert:           ri.cert,
+		Issuer:         ri.issuer,
+		Chain:          ri.chain,
+		Csr:            ri.csr,
+		Key:            ri.key,
+		ProfileId:      linter.BaseProfileId(ri.profileId),
+		AsOf:           ri.asOf,
+		Priority:       ri.priority,
+		QueuedAt:       time.Now(),
+		ChecksAdded:    checksAdded,
+		ChecksNarrowed: ri.checksAdded,
+		ChecksDisabled: slices.Concat(checksDisabled, ri.checksDisabled),
+		RespChannel:    make(chan linter.LintingResult),
+	}
+	if ri.key != nil {
+		lreq.RawKey = ri.decodedInput
+	}
+
+	// Send the linting request to (one of) each linter's backend(s), for each linter that is both available and applicable.
+	var lresp []linter.LintingResult
+	nlresp := 0
+	ri.linterReports = make([]linterReport, 0, len(linter.Linters))
+	reportIndex := make(map[string]int, len(linter.Linters))
+	for _, l := range linter.Linters {
+		report := linterReport{Name: l.Name, Version: linter.VersionString(l.Version)}
+		if isApplicable := l.IsApplicable(ri.profileId); isApplicable && (l.NumInstances > 0) {
+			report.Status = LINTERSTATUS_TIMEDOUT // Until its timing result arrives.
+			select {
+			case l.Queue(ri.priority) <- lreq:
+				nlresp++
+			case <-ctx.Done(): // The queue remained full until the request deadline.
+			}
+		} else {
+			notUsed := linter.LintingResult{
+				LinterName: l.Name,
+				Severity:   linter.SEVERITY_META,
+				Finding:    fmt.Sprintf("%s: Not used [Available:%t, Applicable:%t]", l.Name, (l.NumInstances > 0), isApplicable),
+				Structured: true,
+			}
+			lresp = append(lresp, notUsed)
+			if linterDone != nil {
+				linterDone(l.Name, ri.filterResults([]linter.LintingResult{notUsed}))
+			}
+			if !isApplicable {
+				report.Status = LINTERSTATUS_NOTAPPLICABLE
+			} else {
+				report.Status = LINTERSTATUS_NOTAVAILABLE
+			}
+		}
+		reportIndex[l.Name] = len(ri.linterReports)
+		ri.linterReports = append(ri.linterReports, report)
+	}
+
+	// Wait for all of the used linters to finish writing results to the
+	// response channel, or for the request deadline to be exceeded.  Each
+	// linter's timing result is the last result that it writes before its
+	// end-of-results sentinel.
+	pending := make(map[string][]linter.LintingResult)
+	for nlresp > 0 {
+		select {
+		case resp := <-lreq.RespChannel:
+			if resp.LinterName == linter.PKIMETAL_NAME && resp.Finding == linter.PKIMETAL_ENDOFRESULTS {
+				nlresp--
+			} else {
+				ri.applyPolicy(&resp)
+				if i, ok := reportIndex[resp.LinterName]; ok && resp.Timing != nil {
+					ri.linterReports[i].Status = LINTERSTATUS_COMPLETED
+					if resp.Timing.Failed {
+						ri.linterReports[i].Status = LINTERSTATUS_FAILED
+					}
+					ri.linterReports[i].QueuedNs = resp.Timing.QueuedFor.Nanoseconds()
+					ri.linterReports[i].RuntimeNs = resp.Timing.Runtime.Nanoseconds()
+				}
+				lresp = append(lresp, resp)
+				if linterDone != nil && resp.LinterName != linter.PKIMETAL_NAME {
+					if pending[resp.LinterName] = append(pending[resp.LinterName], resp); resp.Timing != nil {
+						linterDone(resp.LinterName, ri.filterResults(sortResults(pending[resp.LinterName])))
+						delete(pending, resp.LinterName)
+					}
+				}
+			}
+		case <-ctx.Done():
+			// The linter backends observe the same deadline and will stop
+			// writing to the response channel, so abandon the wait.
+			nlresp = 0
+		}
+	}
+
+	// Add pkimetal's own chain consistency findings, if an issuer certificate was provided.
+	lresp = append(lresp, ri.applyPolicyAll(lreq.ChainFindings())...)
+
+	// Add pkimetal's own CSR findings, if the input is a CSR.
+	lresp = append(lresp, ri.applyPolicyAll(lreq.CSRFindings())...)
+
+	// Add pkimetal's own public key findings, if the input is a public key.
+	lresp = append(lresp, ri.applyPolicyAll(lreq.PublicKeyFindings())...)
+
+	// Sort the results.
+	lresp = sortResults(lresp)
+
+	// Prepend a meta result with the selected profile and the pkimetal version.
+	lresp = append([]linter.LintingResult{{
+		LinterName: linter.PKIMETAL_NAME,
+		Severity:   linter.SEVERITY_META,
+		Finding:    fmt.Sprintf("Profile: %s; Version: %s", linter.AllProfiles[ri.profileId].Name, linter.VersionString(config.PkimetalVersion)),
+		Structured: true,
+	}}, lresp...)
+
+	// Explain how the profile was autodetected.
+	if ri.autodetected && len(ri.detectionReasons) > 0 {
+		lresp = slices.Insert(lresp, 1, linter.LintingResult{
+			LinterName: linter.PKIMETAL_NAME,
+			Severity:   linter.SEVERITY_META,
+			Finding:    "Autodetection: " + strings.Join(ri.detectionReasons, "; "),
+			Structured: true,
+		})
+	}
+
+	// Record each linter's most severe unwaived, effective finding, irrespective of the requested minimum severity
+	// level.
+	ri.maxSeverities = make(map[string]linter.SeverityLevel)
+	for _, lres := range lresp {
+		if !lres.Structured && lres.Waived == nil && lres.NotYetEffective == "" {
+			ri.maxSeverities[lres.LinterName] = max(ri.maxSeverities[lres.LinterName], lres.Severity)
+		}
+	}
+
+	// Evaluate the decision policy, which considers every result irrespective of the requested minimum severity level.
+	if ri.decisionMode {
+		d := ri.decide(lresp)
+		ri.decision = &d
+	}
+
+	return ri.filterResults(lresp)
+}
+
+// applyPolicy applies the configured severity mappings, and then the waivers, to the result.
+func (ri *RequestInfo) applyPolicy(lres *linter.LintingResult) {
+	ri.remapSeverity(lres)
+	ri.waive(lres)
+}
+
+// applyPolicyAll calls applyPolicy for each of the results.
+func (ri *RequestInfo) applyPolicyAll(lresp []linter.LintingResult) []linter.LintingResult {
+	for i := range lresp {
+		ri.applyPolicy(&lresp[i])
+	}
+	return lresp
+}
+
+// sortResults sorts the results by Linter Name, then Severity (most severe first), then Finding description, except
+// that findings that are not yet effective are sorted into a separate section after all of the other results.
+func sortResults(lresp []linter.LintingResult) []linter.LintingResult {
+	sort.Slice(lresp, func(i, j int) bool {
+		if (lresp[i].NotYetEffective == "") != (lresp[j].NotYetEffective == "") {
+			return lresp[i].NotYetEffective == ""
+		} else if lresp[i].LinterName != lresp[j].LinterName {
+			return lresp[i].LinterName < lresp[j].LinterName
+		} else if lresp[i].Severity != lresp[j].Severity {
+			return lresp[i].Severity > lresp[j].Severity
+		} else {
+			return lresp[i].Finding < lresp[j].Finding
+		}
+	})
+	return lresp
+}
+
+// filterResults filters out results that are below the requested minimum severity level.
+func (ri *RequestInfo) filterResults(lresp []linter.LintingResult) []LintResult {
+	var lrespFiltered []LintResult
+	for _, lres := range lresp {
+		if lres.Severity >= ri.minimumSeverity {
+			var originalSeverity string
+			if lres.Remapped != nil {
+				originalSeverity = linter.SeverityString[*lres.Remapped]
+			}
+			lrespFiltered = append(lrespFiltered, LintResult{
+				Linter:           lres.LinterName,
+				Finding:          lres.Finding,
+				Field:            lres.Field,
+				Code:             lres.Code,
+				Severity:         linter.SeverityString[lres.Severity],
+				Waived:           lres.Waived,
+				OriginalSeverity: originalSeverity,
+				NotYetEffective:  lres.NotYetEffective,
+				structured:       lres.Structured,
+				timing:           lres.Timing,
+			})
+		}
+	}
+
+	return lrespFiltered
+}
+
+// parseDecisionMode parses the "decision" parameter, which defaults to false.
+func parseDecisionMode(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
 func paramS(fhctx *fasthttp.RequestCtx, name string) string {
 	return utils.B2S(paramB(fhctx, name))
 }
@@ -212,6 +455,26 @@ func paramB(fhctx *fasthttp.RequestCtx, name string) []byte {
 	return nil
 }
 
+// annotationSuffix returns the annotations that the HTML and text response formats append to a finding whose
+// severity was remapped, that was waived, and/or that is not yet effective.
+func annotationSuffix(lres LintResult) string {
+	var suffix string
+	if lres.OriginalSeverity != "" {
+		suffix += " (remapped from " + strings.ToUpper(lres.OriginalSeverity) + ")"
+	}
+	switch {
+	case lres.Waived == nil:
+	case lres.Waived.Ticket != "":
+		suffix += " (waived: " + lres.Waived.Ticket + ")"
+	default:
+		suffix += " (waived)"
+	}
+	if lres.NotYetEffective != "" {
+		suffix += " (not yet effective: effective from " + lres.NotYetEffective + ")"
+	}
+	return suffix
+}
+
 func sendHTMLResponse(fhctx *fasthttp.RequestCtx, lrespFiltered []LintResult) int {
 	// Encode and send the results as an HTML webpage.
 	fhctx.SetContentType("text/html; charset=UTF-8")
@@ -270,7 +533,7 @@ func sendHTMLResponse(fhctx *fasthttp.RequestCtx, lrespFiltered []LintResult) in
 		<TR style="` + style + `">
 		  <TD>` + lres.Linter + `</TD>
 		  <TD>` + strings.ToUpper(lres.Severity) + `</TD>
-		  <TD>` + lres.Finding + `</TD>
+		  <TD>` + lres.Finding + annotationSuffix(lres) + `</TD>
 		  <TD>` + lres.Field + `</TD>
 		  <TD>` + lres.Code + `</TD>
 		</TR>`)
@@ -318,6 +581,7 @@ func sendTEXTResponse(fhctx *fasthttp.RequestCtx, lrespFiltered []LintResult) in
 		if lres.Field != "" {
 			finding += " [" + lres.Field + "]"
 		}
+		finding += annotationSuffix(lres)
 		response.WriteString(fmt.Sprintf("%s\t%s\t%s\n", lres.Linter, strings.ToUpper(lres.Severity), finding))
 	}
 
@@ -0,0 +1,52 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/utils"
+
+	"github.com/valyala/fasthttp"
+)
+
+var apiKeyPriorities map[string]linter.PriorityClass
+
+func init() {
+	var err error
+	if apiKeyPriorities, err = compileAPIKeyPriorities(config.Config.Priority.APIKeys); err != nil {
+		panic(err)
+	}
+}
+
+// compileAPIKeyPriorities validates the priority classes assigned to API keys.
+func compileAPIKeyPriorities(caks []config.PriorityAPIKey) (map[string]linter.PriorityClass, error) {
+	compiled := make(map[string]linter.PriorityClass, len(caks))
+	for i, cak := range caks {
+		priority, ok := linter.Priority[cak.Priority]
+		if cak.Key == "" {
+			return nil, fmt.Errorf("API key %d: key must be specified", i)
+		} else if !ok {
+			return nil, fmt.Errorf("API key %d: unrecognised priority: %s", i, cak.Priority)
+		} else if _, ok = compiled[cak.Key]; ok {
+			return nil, fmt.Errorf("API key %d: duplicate key", i)
+		}
+		compiled[cak.Key] = priority
+	}
+	return compiled, nil
+}
+
+// getPriority determines the request's priority class.  An API key (in the X-Api-Key header) that is assigned a
+// priority class takes precedence over the X-Pkimetal-Priority header, which takes precedence over the endpoint's
+// default priority class.
+func getPriority(fhctx *fasthttp.RequestCtx, defaultPriority linter.PriorityClass) (linter.PriorityClass, error) {
+	if priority, ok := apiKeyPriorities[utils.B2S(fhctx.Request.Header.Peek("X-Api-Key"))]; ok {
+		return priority, nil
+	} else if s := utils.B2S(fhctx.Request.Header.Peek("X-Pkimetal-Priority")); s == "" {
+		return defaultPriority, nil
+	} else if priority, ok = linter.Priority[s]; !ok {
+		return -1, fmt.Errorf("unrecognised priority: %s", s)
+	} else {
+		return priority, nil
+	}
+}
@@ -0,0 +1,93 @@
+package request
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+)
+
+func TestCompileAPIKeyPriorities(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		keys    []config.PriorityAPIKey
+		wantErr bool
+	}{
+		{"valid", []config.PriorityAPIKey{{Key: "k1", Priority: "bulk"}, {Key: "k2", Priority: "interactive"}}, false},
+		{"no key", []config.PriorityAPIKey{{Priority: "bulk"}}, true},
+		{"bad priority", []config.PriorityAPIKey{{Key: "k1", Priority: "urgent"}}, true},
+		{"no priority", []config.PriorityAPIKey{{Key: "k1"}}, true},
+		{"duplicate key", []config.PriorityAPIKey{{Key: "k1", Priority: "bulk"}, {Key: "k1", Priority: "interactive"}}, true},
+	} {
+		if _, err := compileAPIKeyPriorities(tc.keys); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestGetPriority(t *testing.T) {
+	saved := apiKeyPriorities
+	t.Cleanup(func() { apiKeyPriorities = saved })
+	var err error
+	if apiKeyPriorities, err = compileAPIKeyPriorities([]config.PriorityAPIKey{
+		{Key: "ca-issuance", Priority: "interactive"},
+		{Key: "nightly-relint", Priority: "bulk"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tc := range []struct {
+		name, apiKey, header string
+		defaultPriority      linter.PriorityClass
+		want                 linter.PriorityClass
+	}{
+		{"endpoint default", "", "", linter.PRIORITY_BULK, linter.PRIORITY_BULK},
+		{"header", "", "interactive", linter.PRIORITY_BULK, linter.PRIORITY_INTERACTIVE},
+		{"API key", "nightly-relint", "", linter.PRIORITY_INTERACTIVE, linter.PRIORITY_BULK},
+		{"API key beats header", "ca-issuance", "bulk", linter.PRIORITY_BULK, linter.PRIORITY_INTERACTIVE},
+		{"unknown API key", "other", "bulk", linter.PRIORITY_INTERACTIVE, linter.PRIORITY_BULK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fhctx := newPostCtx("", nil)
+			if tc.apiKey != "" {
+				fhctx.Request.Header.Set("X-Api-Key", tc.apiKey)
+			}
+			if tc.header != "" {
+				fhctx.Request.Header.Set("X-Pkimetal-Priority", tc.header)
+			}
+			if priority, err := getPriority(fhctx, tc.defaultPriority); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if priority != tc.want {
+				t.Errorf("got %s, want %s", linter.PriorityString[priority], linter.PriorityString[tc.want])
+			}
+		})
+	}
+
+	fhctx := newPostCtx("", nil)
+	fhctx.Request.Header.Set("X-Pkimetal-Priority", "urgent")
+	if _, err := getPriority(fhctx, linter.PRIORITY_INTERACTIVE); err == nil {
+		t.Error("expected an unrecognised priority to be rejected")
+	}
+}
+
+func TestLint_BulkPriority(t *testing.T) {
+	saved := linter.Linters
+	t.Cleanup(func() { linter.Linters = saved })
+	fake := &linter.Linter{Name: "fake", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 1), BulkReqChannel: make(chan linter.LintingRequest, 1)}
+	linter.Linters = linter.LinterSlice{fake}
+	go func() {
+		lreq := <-fake.BulkReqChannel
+		lreq.RespChannel <- linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_META, Structured: true, Timing: &linter.LintingTiming{}}
+		lreq.RespChannel <- linter.LintingResult{LinterName: linter.PKIMETAL_NAME, Severity: linter.SEVERITY_META, Finding: linter.PKIMETAL_ENDOFRESULTS}
+	}()
+
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, priority: linter.PRIORITY_BULK}
+	ri.GetProfile("")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ri.lint(ctx)
+	if ri.linterReports[0].Status == LINTERSTATUS_TIMEDOUT {
+		t.Errorf("got %+v, want the request to be sent to the bulk queue", ri.linterReports[0])
+	}
+}
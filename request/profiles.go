@@ -1,6 +1,9 @@
 package request
 
 import (
+	"slices"
+	"strconv"
+
 	"github.com/pkimetal/pkimetal/config"
 	"github.com/pkimetal/pkimetal/linter"
 	"github.com/pkimetal/pkimetal/logger"
@@ -12,20 +15,65 @@ import (
 )
 
 func Profiles(fhctx *fasthttp.RequestCtx) {
+	profiles, errorMessage := filterProfiles(fhctx.QueryArgs())
+	if errorMessage != "" {
+		logger.SetDetails(fhctx, zap.InfoLevel, "Profile Information with Error", nil, []zap.Field{
+			zap.String("error", errorMessage),
+		})
+		fhctx.SetContentType("text/plain; charset=UTF-8")
+		fhctx.SetBodyString(errorMessage + "\n")
+		fhctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
 	// Encode and send the results as JSON.
 	j := json.NewEncoder(fhctx)
 	j.SetEscapeHTML(false)
 	if config.Config.Response.JsonPrettyPrint {
 		j.SetIndent("", "  ")
 	}
-	if err := j.Encode(linter.AllProfilesOrdered); err != nil {
+	if err := j.Encode(profiles); err != nil {
 		logger.SetDetails(fhctx, zap.ErrorLevel, "Failed to encode JSON", nil, nil)
 		fhctx.SetStatusCode(fasthttp.StatusInternalServerError)
 	} else {
 		logger.SetDetails(fhctx, zap.InfoLevel, "Profile Information", nil, []zap.Field{
-			zap.Int("num_results", len(linter.AllProfilesOrdered)),
+			zap.Int("num_results", len(profiles)),
 		})
 		fhctx.SetContentType("application/json; charset=UTF-8")
 		fhctx.SetStatusCode(fasthttp.StatusOK)
 	}
 }
+
+// filterProfiles returns the profiles that have all of the attributes specified by the "type", "hierarchy",
+// "standard", and "precertificate" query parameters, or else an error message.
+func filterProfiles(args *fasthttp.Args) ([]linter.Profile, string) {
+	docType := linter.DocumentType(args.Peek("type"))
+	hierarchy := linter.Hierarchy(args.Peek("hierarchy"))
+	standard := linter.Standard(args.Peek("standard"))
+	var precertificate *bool
+	if docType != "" && !slices.Contains(linter.DocumentTypes, docType) {
+		return nil, "Unrecognised type"
+	} else if hierarchy != "" && !slices.Contains(linter.Hierarchies, hierarchy) {
+		return nil, "Unrecognised hierarchy"
+	} else if standard != "" && !slices.Contains(linter.Standards, standard) {
+		return nil, "Unrecognised standard"
+	} else if s := args.Peek("precertificate"); len(s) > 0 {
+		b, err := strconv.ParseBool(string(s))
+		if err != nil {
+			return nil, "Unrecognised precertificate flag"
+		}
+		precertificate = &b
+	}
+
+	// The autodetect pseudo-profile has no attributes, so it is only listed when no filters are specified.
+	filtered := docType != "" || hierarchy != "" || standard != "" || precertificate != nil
+	profiles := []linter.Profile{}
+	for _, profile := range linter.AllProfilesOrdered {
+		if filtered && profile.Type == "" {
+			continue
+		} else if (docType == "" || profile.Type == docType) && (hierarchy == "" || profile.Hierarchy == hierarchy) && (standard == "" || profile.Standard == standard) && (precertificate == nil || profile.Precertificate == *precertificate) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, ""
+}
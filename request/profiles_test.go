@@ -0,0 +1,59 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestFilterProfiles(t *testing.T) {
+	saved := linter.AllProfilesOrdered
+	t.Cleanup(func() { linter.AllProfilesOrdered = saved })
+	linter.AllProfilesOrdered = nil
+	for id := linter.AUTODETECT; id < linter.FIRST_CUSTOM_PROFILE; id++ {
+		linter.AllProfilesOrdered = append(linter.AllProfilesOrdered, linter.AllProfiles[id])
+	}
+
+	for _, tc := range []struct {
+		query        string
+		want         []string
+		errorMessage string
+	}{
+		{"type=crl&standard=tbr", []string{"tbr_crl", "tbr_arl"}, ""},
+		{"standard=tevg&hierarchy=leaf&precertificate=true", []string{"tevg_leaf_tlsserver_ev_precertificate"}, ""},
+		{"type=publickey", []string{"rfc5280_publickey"}, ""},
+		{"type=crt", nil, "Unrecognised type"},
+		{"hierarchy=intermediate", nil, "Unrecognised hierarchy"},
+		{"standard=abc", nil, "Unrecognised standard"},
+		{"precertificate=maybe", nil, "Unrecognised precertificate flag"},
+	} {
+		var args fasthttp.Args
+		args.Parse(tc.query)
+		profiles, errorMessage := filterProfiles(&args)
+		if errorMessage != tc.errorMessage {
+			t.Errorf("%s: got error %q, want %q", tc.query, errorMessage, tc.errorMessage)
+			continue
+		}
+		var names []string
+		for _, profile := range profiles {
+			names = append(names, profile.Name)
+		}
+		if len(names) != len(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.query, names, tc.want)
+			continue
+		}
+		for i := range names {
+			if names[i] != tc.want[i] {
+				t.Errorf("%s: got %v, want %v", tc.query, names, tc.want)
+				break
+			}
+		}
+	}
+
+	var args fasthttp.Args
+	if profiles, _ := filterProfiles(&args); len(profiles) != len(linter.AllProfilesOrdered) {
+		t.Errorf("got %d profiles, want all %d", len(profiles), len(linter.AllProfilesOrdered))
+	}
+}
@@ -0,0 +1,172 @@
+package request
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	stdx509 "crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/pkimetal/pkimetal/utils"
+
+	json "github.com/goccy/go-json"
+	"github.com/zmap/zcrypto/x509"
+	"golang.org/x/crypto/ssh"
+)
+
+// jsonWebKey holds the JWK (RFC7517) members needed to construct an RSA, EC, or OKP public key.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	D   string `json:"d"`
+}
+
+func (ri *RequestInfo) parseKeyInput() (key any, err error) {
+	// Decode the PEM or Base64 SubjectPublicKeyInfo, JWK, or OpenSSH public key input.
+	if ri.b64Input == nil {
+		err = fmt.Errorf("no public key provided")
+		return
+	}
+	input := bytes.TrimSpace(ri.b64Input)
+	if !isTextKeyInput(input) {
+		// Raw request bodies arrive here Base64-encoded, so they need decoding before their format can be determined.
+		if decoded, err := utils.DecodePEMOrBase64(input, "PUBLIC KEY"); err == nil && isTextKeyInput(bytes.TrimSpace(decoded)) {
+			input = bytes.TrimSpace(decoded)
+		}
+	}
+
+	var publicKey any
+	switch {
+	case bytes.HasPrefix(input, []byte("{")):
+		publicKey, err = parseJWK(input)
+	case isOpenSSHKeyInput(input):
+		publicKey, err = parseOpenSSHKey(input)
+	default:
+		ri.decodedInput, err = utils.DecodePEMOrBase64(input, "PUBLIC KEY")
+	}
+	if err != nil {
+		return
+	}
+
+	// Re-encode JWK and OpenSSH public keys as a DER SubjectPublicKeyInfo, so that every linter sees the same input.
+	if publicKey != nil {
+		if ri.decodedInput, err = stdx509.MarshalPKIXPublicKey(publicKey); err != nil {
+			return
+		}
+	}
+
+	// Update the Base64 input field from the decoded input, which will ensure that PEM encapsulation boundaries are present.
+	ri.b64Input = pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: ri.decodedInput,
+	})
+
+	// Parse the public key, recovering from any panics that may occur during parsing.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Recovered from panic while parsing public key: %v", r)
+		}
+	}()
+	key, err = x509.ParsePKIXPublicKey(ri.decodedInput)
+	return
+}
+
+// isTextKeyInput reports whether the input is a PEM, JWK, or OpenSSH public key, rather than Base64.
+func isTextKeyInput(input []byte) bool {
+	return bytes.HasPrefix(input, []byte("-----BEGIN")) || bytes.HasPrefix(input, []byte("{")) || isOpenSSHKeyInput(input)
+}
+
+func isOpenSSHKeyInput(input []byte) bool {
+	return bytes.HasPrefix(input, []byte("ssh-")) || bytes.HasPrefix(input, []byte("ecdsa-sha2-")) || bytes.HasPrefix(input, []byte("sk-"))
+}
+
+func parseOpenSSHKey(input []byte) (any, error) {
+	sshKey, _, _, _, err := ssh.ParseAuthorizedKey(input)
+	if err != nil {
+		return nil, err
+	} else if cryptoKey, ok := sshKey.(ssh.CryptoPublicKey); !ok {
+		return nil, fmt.Errorf("unsupported OpenSSH key type: %s", sshKey.Type())
+	} else {
+		return cryptoKey.CryptoPublicKey(), nil
+	}
+}
+
+func parseJWK(input []byte) (any, error) {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(input, &jwk); err != nil {
+		return nil, err
+	} else if jwk.D != "" {
+		return nil, fmt.Errorf("JWK contains private key material")
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil {
+			return nil, err
+		} else if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("JWK RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported JWK curve: %s", jwk.Crv)
+		}
+		x, err := decodeJWKInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported JWK curve: %s", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		} else if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid JWK Ed25519 public key length: %d", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type: %s", jwk.Kty)
+	}
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing JWK parameter")
+	} else if b, err := base64.RawURLEncoding.DecodeString(s); err != nil {
+		return nil, err
+	} else {
+		return new(big.Int).SetBytes(b), nil
+	}
+}
@@ -1,9 +1,14 @@
 package request
 
 import (
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/linter"
 
 	"github.com/valyala/fasthttp"
 )
@@ -94,3 +99,177 @@ func TestSendHTMLResponse_NonEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestSendSARIFResponse(t *testing.T) {
+	results := []LintResult{
+		{Linter: "pkimetal", Finding: "Profile: rfc5280_leaf", Severity: "meta"},
+		{Linter: "zlint", Finding: "e_one", Field: "extensions", Code: "e_one", Severity: "error"},
+		{Linter: "zlint", Finding: "w_two", Code: "w_two", Severity: "warning"},
+		{Linter: "zlint", Finding: "e_one again", Code: "e_one", Severity: "fatal"},
+	}
+	ctx := &fasthttp.RequestCtx{}
+	if status := sendSARIFResponse(ctx, results); status != fasthttp.StatusOK {
+		t.Errorf("got status %d, want %d", status, fasthttp.StatusOK)
+	}
+	if ct := string(ctx.Response.Header.ContentType()); !strings.Contains(ct, "application/sarif+json") {
+		t.Errorf("got content type %q, want application/sarif+json", ct)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(ctx.Response.Body(), &got); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	if got.Version != "2.1.0" || got.Schema == "" {
+		t.Errorf("got version %q, schema %q", got.Version, got.Schema)
+	}
+	if len(got.Runs) != 2 || got.Runs[0].Tool.Driver.Name != "pkimetal" || got.Runs[1].Tool.Driver.Name != "zlint" {
+		t.Fatalf("got runs %+v", got.Runs)
+	}
+	zlint := got.Runs[1]
+	if len(zlint.Tool.Driver.Rules) != 2 || zlint.Tool.Driver.Rules[0].Id != "e_one" || zlint.Tool.Driver.Rules[1].Id != "w_two" {
+		t.Errorf("got rules %+v", zlint.Tool.Driver.Rules)
+	}
+	wantLevels := []string{"error", "warning", "error"}
+	wantRuleIndexes := []int{0, 1, 0}
+	for i, sres := range zlint.Results {
+		if sres.Level != wantLevels[i] || sres.RuleIndex == nil || *sres.RuleIndex != wantRuleIndexes[i] {
+			t.Errorf("result %d: got %+v", i, sres)
+		}
+	}
+	if len(zlint.Results[0].Locations) != 1 || zlint.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName != "extensions" {
+		t.Errorf("got locations %+v, want the extensions field", zlint.Results[0].Locations)
+	}
+	if zlint.Results[2].Properties.Severity != "fatal" {
+		t.Errorf("got severity property %q, want fatal", zlint.Results[2].Properties.Severity)
+	}
+	if meta := got.Runs[0].Results[0]; meta.Level != "none" || meta.RuleId != "" || meta.Locations != nil {
+		t.Errorf("got meta result %+v", meta)
+	}
+}
+
+func TestSendSARIFResponse_Empty(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	sendSARIFResponse(ctx, nil)
+	if body := string(ctx.Response.Body()); !strings.Contains(body, `"runs":[]`) {
+		t.Errorf("expected an empty runs array, got %q", body)
+	}
+}
+
+func TestMakeJUnitReport(t *testing.T) {
+	results := []LintResult{
+		{Linter: "pkimetal", Finding: "Profile: rfc5280_leaf", Severity: "meta"},
+		{Linter: "zlint", Finding: "Queued: 1ms; Runtime: 1.5s; Version: v3", Severity: "meta", timing: &linter.LintingTiming{QueuedFor: time.Millisecond, Runtime: 1500 * time.Millisecond}},
+		{Linter: "zlint", Finding: "e_one", Field: "extensions", Code: "e_one", Severity: "error"},
+		{Linter: "zlint", Finding: "w_two", Code: "w_two", Severity: "warning"},
+		{Linter: "zlint", Finding: "No code", Severity: "info"},
+	}
+	report := makeJUnitReport(results, linter.SEVERITY_WARNING)
+	if report.Tests != 3 || report.Failures != 2 || report.Time != "1.500" {
+		t.Errorf("got tests=%d failures=%d time=%s", report.Tests, report.Failures, report.Time)
+	}
+	if len(report.Suites) != 2 || report.Suites[0].Name != "pkimetal" || report.Suites[1].Name != "zlint" {
+		t.Fatalf("got suites %+v", report.Suites)
+	}
+	if pkimetal := report.Suites[0]; pkimetal.Tests != 0 || !strings.Contains(pkimetal.SystemOut, "Profile: rfc5280_leaf") {
+		t.Errorf("got pkimetal suite %+v", pkimetal)
+	}
+	zlint := report.Suites[1]
+	if zlint.Time != "1.500" || zlint.Tests != 3 || zlint.Failures != 2 {
+		t.Errorf("got zlint suite time=%s tests=%d failures=%d", zlint.Time, zlint.Tests, zlint.Failures)
+	}
+	if tc := zlint.TestCases[0]; tc.Name != "e_one" || tc.ClassName != "pkimetal.zlint" || tc.Failure == nil || tc.Failure.Type != "ERROR" || tc.Failure.Text != "e_one [extensions]" {
+		t.Errorf("got testcase %+v", tc)
+	}
+	if tc := zlint.TestCases[2]; tc.Name != "No code" || tc.Failure != nil || tc.SystemOut != "INFO: No code" {
+		t.Errorf("got testcase %+v", tc)
+	}
+}
+
+func TestSendJUNITResponse(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	if status := sendJUNITResponse(ctx, []LintResult{{Linter: "zlint", Finding: "a < b", Code: "e_x", Severity: "error"}}); status != fasthttp.StatusOK {
+		t.Errorf("got status %d, want %d", status, fasthttp.StatusOK)
+	}
+	if ct := string(ctx.Response.Header.ContentType()); !strings.Contains(ct, "application/xml") {
+		t.Errorf("got content type %q, want application/xml", ct)
+	}
+	var got junitTestSuites
+	if err := xml.Unmarshal(ctx.Response.Body(), &got); err != nil {
+		t.Fatalf("response is not valid XML: %v", err)
+	}
+	if len(got.Suites) != 1 || len(got.Suites[0].TestCases) != 1 || got.Suites[0].TestCases[0].Failure.Message != "a < b" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestMakeJSON2Response(t *testing.T) {
+	ri := RequestInfo{
+		profileId:    linter.RFC5280_LEAF,
+		autodetected: true,
+		linterReports: []linterReport{
+			{Name: "zlint", Version: "v3", Status: LINTERSTATUS_COMPLETED, QueuedNs: 1000, RuntimeNs: 2000},
+			{Name: "certlint", Version: "v1", Status: LINTERSTATUS_NOTAPPLICABLE},
+		},
+	}
+	jresp := ri.makeJSON2Response([]LintResult{
+		{Linter: "pkimetal", Finding: "Profile: rfc5280_leaf", Severity: "meta", structured: true},
+		{Linter: "pkimetal", Finding: "e_chain_issuer_not_ca", Field: "issuer", Code: "e_chain_issuer_not_ca", Severity: "error"},
+		{Linter: "zlint", Finding: "Queued: 1µs; Runtime: 2µs; Version: v3", Severity: "meta", structured: true},
+		{Linter: "zlint", Finding: "w_finding", Code: "w_finding", Severity: "warning"},
+		{Linter: "certlint", Finding: "certlint: Not used [Available:true, Applicable:false]", Severity: "meta", structured: true},
+	})
+
+	if jresp.Version != 2 || jresp.Profile == nil || jresp.Profile.Name != "rfc5280_leaf" || jresp.Profile.Id != "rfc5280_leaf" || !jresp.Profile.Autodetected {
+		t.Errorf("got version %d, profile %+v", jresp.Version, jresp.Profile)
+	}
+	if len(jresp.Linters) != 3 || jresp.Linters[0].Name != "pkimetal" || jresp.Linters[1].Name != "zlint" || jresp.Linters[2].Name != "certlint" {
+		t.Fatalf("got linters %+v", jresp.Linters)
+	}
+	if pkimetal := jresp.Linters[0]; len(pkimetal.Findings) != 1 || pkimetal.Findings[0].Code != "e_chain_issuer_not_ca" || pkimetal.Findings[0].Field != "issuer" {
+		t.Errorf("got pkimetal findings %+v", pkimetal.Findings)
+	}
+	if zlint := jresp.Linters[1]; zlint.QueuedNs != 1000 || zlint.RuntimeNs != 2000 || len(zlint.Findings) != 1 {
+		t.Errorf("got zlint report %+v", zlint)
+	}
+	if certlint := jresp.Linters[2]; certlint.Status != LINTERSTATUS_NOTAPPLICABLE || certlint.Findings == nil || len(certlint.Findings) != 0 {
+		t.Errorf("got certlint report %+v", certlint)
+	}
+	if jresp.Summary["error"] != 1 || jresp.Summary["warning"] != 1 || jresp.Summary["meta"] != 0 || len(jresp.Summary) != len(linter.SeverityString) {
+		t.Errorf("got summary %v", jresp.Summary)
+	}
+}
+
+func TestMakeJSON2Response_NotLinted(t *testing.T) {
+	var ri RequestInfo
+	jresp := ri.makeJSON2Response([]LintResult{{Linter: "pkimetal", Finding: "Unrecognised input", Severity: "fatal"}})
+	if jresp.Profile != nil {
+		t.Errorf("got profile %+v, want none", jresp.Profile)
+	}
+	if len(jresp.Linters) != 1 || len(jresp.Linters[0].Findings) != 1 || jresp.Summary["fatal"] != 1 {
+		t.Errorf("got %+v", jresp)
+	}
+}
+
+func TestSendJSON2Response_Lint(t *testing.T) {
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP}
+	if !ri.GetProfile("") {
+		t.Fatal("expected the OCSP profile to be autodetected")
+	}
+	ctx := &fasthttp.RequestCtx{}
+	ri.sendJSON2Response(ctx, ri.lint(context.Background()))
+	if ct := string(ctx.Response.Header.ContentType()); !strings.Contains(ct, JSON2_CONTENT_TYPE) {
+		t.Errorf("got content type %q, want %s", ct, JSON2_CONTENT_TYPE)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(ctx.Response.Body(), &got); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	profile, _ := got["profile"].(map[string]any)
+	if profile["name"] != "rfc6960_ocspresponse" || profile["autodetected"] != true {
+		t.Errorf("got profile %v", got["profile"])
+	}
+	if linters, _ := got["linters"].([]any); len(linters) != 1 || len(linters[0].(map[string]any)["findings"].([]any)) != 0 {
+		t.Errorf("got linters %v, want only pkimetal, without its profile meta finding", got["linters"])
+	}
+}
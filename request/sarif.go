@@ -0,0 +1,173 @@
+package request
+
+import (
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+	"github.com/pkimetal/pkimetal/logger"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+
+	"go.uber.org/zap"
+)
+
+// SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html) log file structures.
+const (
+	SARIF_SCHEMA  = "https://json.schemastore.org/sarif-2.1.0.json"
+	SARIF_VERSION = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationUri string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleId       string             `json:"ruleId,omitempty"`
+	RuleIndex    *int               `json:"ruleIndex,omitempty"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   sarifProperties    `json:"properties"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+type sarifProperties struct {
+	Severity         string `json:"severity"`
+	OriginalSeverity string `json:"originalSeverity,omitempty"`
+	NotYetEffective  string `json:"notYetEffective,omitempty"`
+}
+
+// sarifLevel maps a pkimetal severity to the corresponding SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case linter.SEVERITYSTRING_ERROR, linter.SEVERITYSTRING_BUG, linter.SEVERITYSTRING_FATAL:
+		return "error"
+	case linter.SEVERITYSTRING_WARNING:
+		return "warning"
+	case linter.SEVERITYSTRING_INFO, linter.SEVERITYSTRING_NOTICE:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+// sarifDriverFor returns the SARIF tool driver that describes the named linter.
+func sarifDriverFor(name string) sarifDriver {
+	driver := sarifDriver{Name: name, Rules: []sarifRule{}}
+	if name == linter.PKIMETAL_NAME {
+		driver.Version = linter.VersionString(config.PkimetalVersion)
+		if packagePath := linter.GetPackagePath(); packagePath != "" {
+			driver.InformationUri = "https://" + packagePath
+		}
+	} else {
+		for _, l := range linter.Linters {
+			if l.Name == name {
+				driver.Version, driver.InformationUri = linter.VersionString(l.Version), l.Url
+				break
+			}
+		}
+	}
+	return driver
+}
+
+// makeSARIFLog converts the results into a SARIF log, with one run per linter.
+// Each distinct finding code becomes one of the linter's rules, and each field
+// becomes a logical location.
+func makeSARIFLog(lrespFiltered []LintResult) sarifLog {
+	sarif := sarifLog{Schema: SARIF_SCHEMA, Version: SARIF_VERSION, Runs: []sarifRun{}}
+	runIndex := make(map[string]int)
+	ruleIndex := make(map[string]map[string]int)
+	for _, lres := range lrespFiltered {
+		r, ok := runIndex[lres.Linter]
+		if !ok {
+			r = len(sarif.Runs)
+			runIndex[lres.Linter] = r
+			ruleIndex[lres.Linter] = make(map[string]int)
+			sarif.Runs = append(sarif.Runs, sarifRun{Tool: sarifTool{Driver: sarifDriverFor(lres.Linter)}, Results: []sarifResult{}})
+		}
+		run := &sarif.Runs[r]
+
+		sres := sarifResult{
+			Level:      sarifLevel(lres.Severity),
+			Message:    sarifMessage{Text: lres.Finding},
+			Properties: sarifProperties{Severity: lres.Severity, OriginalSeverity: lres.OriginalSeverity, NotYetEffective: lres.NotYetEffective},
+		}
+		if lres.NotYetEffective != "" {
+			sres.Level = "none" // The requirement does not yet apply.
+		}
+		if lres.Code != "" {
+			idx, ok := ruleIndex[lres.Linter][lres.Code]
+			if !ok {
+				idx = len(run.Tool.Driver.Rules)
+				ruleIndex[lres.Linter][lres.Code] = idx
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{Id: lres.Code})
+			}
+			sres.RuleId, sres.RuleIndex = lres.Code, &idx
+		}
+		if lres.Field != "" {
+			sres.Locations = []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: lres.Field}}}}
+		}
+		if lres.Waived != nil {
+			// Waivers are recorded outside of the linted input, hence "external".
+			sres.Suppressions = []sarifSuppression{{Kind: "external", Status: "accepted", Justification: lres.Waived.Justification}}
+		}
+		run.Results = append(run.Results, sres)
+	}
+	return sarif
+}
+
+func sendSARIFResponse(fhctx *fasthttp.RequestCtx, lrespFiltered []LintResult) int {
+	// Encode and send the results as a SARIF log.
+	fhctx.SetContentType("application/sarif+json; charset=UTF-8")
+
+	j := json.NewEncoder(fhctx)
+	j.SetEscapeHTML(false)
+	if config.Config.Response.JsonPrettyPrint {
+		j.SetIndent("", "  ")
+	}
+	if err := j.Encode(makeSARIFLog(lrespFiltered)); err != nil {
+		logger.SetDetails(fhctx, zap.ErrorLevel, "Failed to encode SARIF", nil, nil)
+	}
+
+	return fasthttp.StatusOK
+}
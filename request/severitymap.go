@@ -0,0 +1,92 @@
+package request
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+)
+
+// severityMapping is a config.SeverityMapping, compiled for matching.
+type severityMapping struct {
+	linterName string
+	code       string
+	finding    *regexp.Regexp
+	profile    string
+	severity   linter.SeverityLevel
+}
+
+var severityMappings []severityMapping
+
+func init() {
+	var err error
+	if severityMappings, err = compileSeverityMappings(config.Config.Severity.Mappings); err != nil {
+		panic(err)
+	}
+}
+
+// compileSeverityMappings validates and compiles the configured severity mappings.
+func compileSeverityMappings(csms []config.SeverityMapping) ([]severityMapping, error) {
+	var compiled []severityMapping
+	for i, csm := range csms {
+		sm := severityMapping{linterName: csm.Linter, code: csm.Code, profile: csm.Profile}
+		var ok bool
+		var err error
+		if csm.Linter == "" && csm.Code == "" && csm.Finding == "" {
+			return nil, fmt.Errorf("severity mapping %d: at least one of linter, code, or finding must be specified", i)
+		} else if sm.severity, ok = linter.Severity[csm.Severity]; !ok || csm.Severity == "" {
+			return nil, fmt.Errorf("severity mapping %d: unrecognised severity: %s", i, csm.Severity)
+		} else if _, err = path.Match(csm.Code, ""); err != nil {
+			return nil, fmt.Errorf("severity mapping %d: unrecognised code pattern: %w", i, err)
+		} else if _, err = path.Match(csm.Profile, ""); err != nil {
+			return nil, fmt.Errorf("severity mapping %d: unrecognised profile pattern: %w", i, err)
+		}
+		if csm.Finding != "" {
+			if sm.finding, err = regexp.Compile(csm.Finding); err != nil {
+				return nil, fmt.Errorf("severity mapping %d: unrecognised finding regular expression: %w", i, err)
+			}
+		}
+		compiled = append(compiled, sm)
+	}
+	return compiled, nil
+}
+
+// matches determines whether or not the severity mapping applies to the specified result.  A code pattern never
+// matches a result that has no code.
+func (sm *severityMapping) matches(ri *RequestInfo, lres *linter.LintingResult) bool {
+	if sm.linterName != "" && sm.linterName != lres.LinterName {
+		return false
+	} else if sm.code != "" {
+		if matched, _ := path.Match(sm.code, lres.Code); !matched || lres.Code == "" {
+			return false
+		}
+	}
+	if sm.finding != nil && !sm.finding.MatchString(lres.Finding) {
+		return false
+	} else if sm.profile != "" {
+		if matched, _ := path.Match(sm.profile, linter.AllProfiles[ri.profileId].Name); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// remapSeverity applies the first configured severity mapping that matches the result, recording the original
+// severity if it changes.  Structured meta results are never remapped.
+func (ri *RequestInfo) remapSeverity(lres *linter.LintingResult) {
+	if lres.Structured {
+		return
+	}
+	for i := range severityMappings {
+		if sm := &severityMappings[i]; sm.matches(ri, lres) {
+			if sm.severity != lres.Severity {
+				original := lres.Severity
+				lres.Remapped = &original
+				lres.Severity = sm.severity
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package request
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+)
+
+// withSeverityMappings replaces the configured severity mappings for the duration of the test.
+func withSeverityMappings(t *testing.T, csms ...config.SeverityMapping) {
+	t.Helper()
+	saved := severityMappings
+	t.Cleanup(func() { severityMappings = saved })
+
+	var err error
+	if severityMappings, err = compileSeverityMappings(csms); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCompileSeverityMappings(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		mapping config.SeverityMapping
+		wantErr bool
+	}{
+		{"valid", config.SeverityMapping{Linter: "zlint", Code: "w_ext_*", Severity: "error"}, false},
+		{"no criteria", config.SeverityMapping{Severity: "error"}, true},
+		{"no severity", config.SeverityMapping{Linter: "zlint"}, true},
+		{"bad severity", config.SeverityMapping{Linter: "zlint", Severity: "loud"}, true},
+		{"bad code", config.SeverityMapping{Code: "[", Severity: "error"}, true},
+		{"bad profile", config.SeverityMapping{Linter: "zlint", Profile: "[", Severity: "error"}, true},
+		{"bad finding", config.SeverityMapping{Finding: "(", Severity: "error"}, true},
+		{"profile only", config.SeverityMapping{Profile: "tbr_*", Severity: "error"}, true},
+	} {
+		if _, err := compileSeverityMappings([]config.SeverityMapping{tc.mapping}); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestRemapSeverity(t *testing.T) {
+	ri := RequestInfo{profileId: linter.TBR_LEAF_TLSSERVER_OV}
+	for _, tc := range []struct {
+		name    string
+		mapping config.SeverityMapping
+		lres    linter.LintingResult
+		want    linter.SeverityLevel
+	}{
+		{"linter and code glob", config.SeverityMapping{Linter: "zlint", Code: "w_ext_*", Severity: "error"}, linter.LintingResult{LinterName: "zlint", Code: "w_ext_foo", Severity: linter.SEVERITY_WARNING}, linter.SEVERITY_ERROR},
+		{"other linter", config.SeverityMapping{Linter: "pkilint", Code: "w_ext_*", Severity: "error"}, linter.LintingResult{LinterName: "zlint", Code: "w_ext_foo", Severity: linter.SEVERITY_WARNING}, linter.SEVERITY_WARNING},
+		{"code glob needs a code", config.SeverityMapping{Code: "*", Severity: "info"}, linter.LintingResult{LinterName: "certlint", Finding: "Crashed", Severity: linter.SEVERITY_FATAL}, linter.SEVERITY_FATAL},
+		{"finding regexp", config.SeverityMapping{Linter: "certlint", Finding: "^Name ", Severity: "notice"}, linter.LintingResult{LinterName: "certlint", Finding: "Name has deprecated attribute", Severity: linter.SEVERITY_WARNING}, linter.SEVERITY_NOTICE},
+		{"profile group", config.SeverityMapping{Linter: "zlint", Profile: "tbr_*", Severity: "error"}, linter.LintingResult{LinterName: "zlint", Code: "w_a", Severity: linter.SEVERITY_WARNING}, linter.SEVERITY_ERROR},
+		{"other profile group", config.SeverityMapping{Linter: "zlint", Profile: "sbr_*", Severity: "error"}, linter.LintingResult{LinterName: "zlint", Code: "w_a", Severity: linter.SEVERITY_WARNING}, linter.SEVERITY_WARNING},
+		{"structured", config.SeverityMapping{Linter: "zlint", Severity: "error"}, linter.LintingResult{LinterName: "zlint", Severity: linter.SEVERITY_META, Structured: true}, linter.SEVERITY_META},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			withSeverityMappings(t, tc.mapping)
+			lres := tc.lres
+			original := lres.Severity
+			ri.remapSeverity(&lres)
+			if lres.Severity != tc.want {
+				t.Errorf("got severity %s, want %s", linter.SeverityString[lres.Severity], linter.SeverityString[tc.want])
+			} else if changed := tc.want != original; changed != (lres.Remapped != nil) || (changed && *lres.Remapped != original) {
+				t.Errorf("got remapped %v", lres.Remapped)
+			}
+		})
+	}
+}
+
+func TestRemapSeverity_FirstMatchWins(t *testing.T) {
+	withSeverityMappings(t,
+		config.SeverityMapping{Linter: "zlint", Code: "w_special", Severity: "info"},
+		config.SeverityMapping{Linter: "zlint", Severity: "error"},
+	)
+	ri := RequestInfo{profileId: linter.TBR_LEAF_TLSSERVER_OV}
+	lres := linter.LintingResult{LinterName: "zlint", Code: "w_special", Severity: linter.SEVERITY_WARNING}
+	ri.remapSeverity(&lres)
+	if lres.Severity != linter.SEVERITY_INFO {
+		t.Errorf("got severity %s, want info", linter.SeverityString[lres.Severity])
+	}
+}
+
+func TestRemapSeverity_Lint(t *testing.T) {
+	withSeverityMappings(t, config.SeverityMapping{Linter: "fake", Code: "w_a", Severity: "error"})
+	withDecisionPolicy(t, "error", nil)
+	withFakeLinters(t, linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_WARNING, Finding: "w_a", Code: "w_a"})
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, minimumSeverity: linter.SEVERITY_ERROR, decisionMode: true}
+	ri.GetProfile("")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lrespFiltered := ri.lint(ctx)
+	if len(lrespFiltered) != 1 || lrespFiltered[0].Severity != "error" || lrespFiltered[0].OriginalSeverity != "warning" {
+		t.Fatalf("got %+v, want the finding to be remapped to error", lrespFiltered)
+	} else if ri.decision.verdict != VERDICT_REJECT {
+		t.Errorf("got %+v, want a remapped finding to cause rejection", ri.decision)
+	} else if suffix := annotationSuffix(lrespFiltered[0]); suffix != " (remapped from WARNING)" {
+		t.Errorf("got suffix %q", suffix)
+	}
+}
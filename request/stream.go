@@ -0,0 +1,143 @@
+package request
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	json "github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	STREAMEVENT_LINTER  = "linter"
+	STREAMEVENT_SUMMARY = "summary"
+)
+
+// streamLinterEvent reports one linter's results, as soon as that linter has finished.
+type streamLinterEvent struct {
+	Linter  string
+	Results []LintResult
+}
+
+// streamSummaryEvent is the final event of a streamed response.
+type streamSummaryEvent struct {
+	Profile  string `json:"Profile,omitempty"`
+	Summary  map[string]int
+	TimedOut bool
+}
+
+func isStreamingResponseFormat(responseFormat config.ResponseFormat) bool {
+	return responseFormat == config.RESPONSEFORMAT_NDJSON || responseFormat == config.RESPONSEFORMAT_SSE
+}
+
+// writeStreamEvent writes an event as a line of NDJSON or as a Server-Sent Event.
+func writeStreamEvent(w io.Writer, responseFormat config.ResponseFormat, event string, v any) error {
+	data, err := json.MarshalWithOption(v, json.DisableHTMLEscape())
+	if err != nil {
+		return err
+	}
+	if responseFormat == config.RESPONSEFORMAT_SSE {
+		_, err = io.WriteString(w, "event: "+event+"\ndata: "+string(data)+"\n\n")
+	} else {
+		_, err = io.WriteString(w, string(data)+"\n")
+	}
+	return err
+}
+
+// streamSummary counts the results at each severity level.
+func streamSummary(lrespFiltered []LintResult) map[string]int {
+	summary := make(map[string]int, len(linter.SeverityString))
+	for _, severity := range linter.SeverityString {
+		summary[severity] = 0
+	}
+	for _, lres := range lrespFiltered {
+		summary[lres.Severity]++
+	}
+	return summary
+}
+
+func setStreamContentType(fhctx *fasthttp.RequestCtx, responseFormat config.ResponseFormat) {
+	if responseFormat == config.RESPONSEFORMAT_SSE {
+		fhctx.SetContentType("text/event-stream; charset=UTF-8")
+		fhctx.Response.Header.Set("Cache-Control", "no-cache")
+	} else {
+		fhctx.SetContentType("application/x-ndjson; charset=UTF-8")
+	}
+}
+
+// sendStreamError sends the results of a request that could not be linted as a complete stream.
+func sendStreamError(fhctx *fasthttp.RequestCtx, responseFormat config.ResponseFormat, lrespFiltered []LintResult) int {
+	setStreamContentType(fhctx, responseFormat)
+	_ = writeStreamEvent(fhctx, responseFormat, STREAMEVENT_LINTER, streamLinterEvent{Linter: linter.PKIMETAL_NAME, Results: lrespFiltered})
+	_ = writeStreamEvent(fhctx, responseFormat, STREAMEVENT_SUMMARY, streamSummaryEvent{Summary: streamSummary(lrespFiltered)})
+	return fasthttp.StatusOK
+}
+
+// sendStreamResponse arranges for the request to be linted whilst the response is streamed, with each linter's
+// results flushed as soon as that linter has finished, followed by the results of pkimetal itself (and of any
+// linters that did not finish in time), and then a summary event.
+func (ri *RequestInfo) sendStreamResponse(fhctx *fasthttp.RequestCtx, responseFormat config.ResponseFormat) int {
+	setStreamContentType(fhctx, responseFormat)
+
+	// The stream writer runs after the request handler has returned, so it needs its own deadline.
+	deadline := fhctx.Time().Add(time.Duration(config.Config.Server.RequestTimeout))
+	fhctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		ri.lintStream(ctx, cancel, w, responseFormat)
+	})
+
+	return fasthttp.StatusOK
+}
+
+// lintStream lints the request, writing and flushing events to w as it goes.  If w cannot be written (e.g., because
+// the client has gone away), cancel is called so that linting is abandoned.
+func (ri *RequestInfo) lintStream(ctx context.Context, cancel context.CancelFunc, w *bufio.Writer, responseFormat config.ResponseFormat) {
+	write := func(event string, v any) {
+		if err := writeStreamEvent(w, responseFormat, event, v); err != nil {
+			cancel()
+		} else if err = w.Flush(); err != nil {
+			cancel()
+		}
+	}
+
+	streamed := make(map[string]bool)
+	lrespFiltered := ri.lintEach(ctx, func(linterName string, lrespFiltered []LintResult) {
+		streamed[linterName] = true
+		if lrespFiltered == nil {
+			lrespFiltered = []LintResult{}
+		}
+		write(STREAMEVENT_LINTER, streamLinterEvent{Linter: linterName, Results: lrespFiltered})
+	})
+	timedOut := ctx.Err() == context.DeadlineExceeded
+
+	// Send the remaining results, grouped by linter.
+	var remaining []streamLinterEvent
+	remainingIndex := make(map[string]int)
+	for _, lres := range lrespFiltered {
+		if streamed[lres.Linter] {
+			continue
+		}
+		i, ok := remainingIndex[lres.Linter]
+		if !ok {
+			i = len(remaining)
+			remainingIndex[lres.Linter] = i
+			remaining = append(remaining, streamLinterEvent{Linter: lres.Linter})
+		}
+		remaining[i].Results = append(remaining[i].Results, lres)
+	}
+	for _, event := range remaining {
+		write(STREAMEVENT_LINTER, event)
+	}
+
+	write(STREAMEVENT_SUMMARY, streamSummaryEvent{
+		Profile:  linter.AllProfiles[ri.profileId].Name,
+		Summary:  streamSummary(lrespFiltered),
+		TimedOut: timedOut,
+	})
+}
@@ -0,0 +1,98 @@
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+)
+
+// withFakeLinters replaces the registered linters with one that is not available and one whose backend
+// responds with the specified results, followed by its timing result and the end-of-results sentinel.
+func withFakeLinters(t *testing.T, results ...linter.LintingResult) {
+	t.Helper()
+	saved := linter.Linters
+	t.Cleanup(func() { linter.Linters = saved })
+
+	fake := &linter.Linter{Name: "fake", NumInstances: 1, ReqChannel: make(chan linter.LintingRequest, 1)}
+	linter.Linters = linter.LinterSlice{{Name: "absent"}, fake}
+	go func() {
+		lreq := <-fake.ReqChannel
+		for _, lres := range results {
+			lreq.RespChannel <- lres
+		}
+		lreq.RespChannel <- linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_META, Finding: "Queued: 1ms; Runtime: 2ms; Version: v1", Structured: true, Timing: &linter.LintingTiming{QueuedFor: time.Millisecond, Runtime: 2 * time.Millisecond}}
+		lreq.RespChannel <- linter.LintingResult{LinterName: linter.PKIMETAL_NAME, Severity: linter.SEVERITY_META, Finding: linter.PKIMETAL_ENDOFRESULTS}
+	}()
+}
+
+func TestLintStream_NDJSON(t *testing.T) {
+	withFakeLinters(t,
+		linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_WARNING, Finding: "w_b", Code: "w_b"},
+		linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_a", Code: "e_a"},
+	)
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP}
+	if !ri.GetProfile("") {
+		t.Fatal("expected the OCSP profile to be autodetected")
+	}
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ri.lintStream(ctx, cancel, bufio.NewWriter(&buf), config.RESPONSEFORMAT_NDJSON)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4: %q", len(lines), buf.String())
+	}
+	wantLinters := []string{"absent", "fake", "pkimetal"}
+	for i, want := range wantLinters {
+		var event streamLinterEvent
+		if err := json.Unmarshal([]byte(lines[i]), &event); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		} else if event.Linter != want {
+			t.Errorf("line %d: got linter %q, want %q", i, event.Linter, want)
+		} else if want == "fake" && (len(event.Results) != 3 || event.Results[0].Code != "e_a" || event.Results[1].Code != "w_b") {
+			t.Errorf("line %d: got results %+v, want sorted results", i, event.Results)
+		}
+	}
+	var summary streamSummaryEvent
+	if err := json.Unmarshal([]byte(lines[3]), &summary); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	} else if summary.Profile != "rfc6960_ocspresponse" || summary.TimedOut || summary.Summary["error"] != 1 || summary.Summary["warning"] != 1 || summary.Summary["meta"] != 4 {
+		t.Errorf("got summary %+v", summary)
+	}
+}
+
+func TestLintStream_SeverityFilter(t *testing.T) {
+	withFakeLinters(t, linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_INFO, Finding: "i_a"})
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, minimumSeverity: linter.SEVERITY_ERROR}
+	ri.GetProfile("")
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ri.lintStream(ctx, cancel, bufio.NewWriter(&buf), config.RESPONSEFORMAT_NDJSON)
+
+	// Each finished linter is still reported, albeit without any results.
+	want := `{"Linter":"absent","Results":[]}` + "\n" + `{"Linter":"fake","Results":[]}` + "\n"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("got %q, want prefix %q", buf.String(), want)
+	}
+}
+
+func TestWriteStreamEvent_SSE(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeStreamEvent(&buf, config.RESPONSEFORMAT_SSE, STREAMEVENT_LINTER, streamLinterEvent{Linter: "zlint", Results: []LintResult{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "event: linter\ndata: {\"Linter\":\"zlint\",\"Results\":[]}\n\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
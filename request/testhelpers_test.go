@@ -24,6 +24,16 @@ func testcaseDER(t *testing.T, name string) []byte {
 	return block.Bytes
 }
 
+// testcasePEM reads a PEM-encoded fixture from ../testcases.
+func testcasePEM(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "testcases", name))
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	return string(data)
+}
+
 // newPostCtx builds a POST *fasthttp.RequestCtx with the given content type and
 // body.
 func newPostCtx(contentType string, body []byte) *fasthttp.RequestCtx {
@@ -35,3 +45,13 @@ func newPostCtx(contentType string, body []byte) *fasthttp.RequestCtx {
 	ctx.Request.SetBody(body)
 	return ctx
 }
+
+// writeTempFile writes data to a file in a temporary directory, and returns the file's path.
+func writeTempFile(t *testing.T, name, data string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
@@ -0,0 +1,136 @@
+package request
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+)
+
+// waiver is a config.Waiver, compiled for matching.
+type waiver struct {
+	linterName    string
+	code          string
+	finding       *regexp.Regexp
+	profileId     linter.ProfileId
+	issuerSKI     []byte
+	issuerSubject string
+	expiresAt     time.Time
+	severity      linter.SeverityLevel
+	downgrade     bool
+	waived        linter.Waived
+}
+
+var waivers []waiver
+
+func init() {
+	var err error
+	if waivers, err = compileWaivers(config.Waivers); err != nil {
+		panic(err)
+	}
+}
+
+// compileWaivers validates and compiles the waivers loaded from the waiver file.
+func compileWaivers(cws []config.Waiver) ([]waiver, error) {
+	var compiled []waiver
+	for i, cw := range cws {
+		w := waiver{
+			linterName:    cw.Linter,
+			code:          cw.Code,
+			profileId:     -1,
+			issuerSubject: cw.IssuerSubject,
+			waived:        linter.Waived{Justification: cw.Justification, Ticket: cw.Ticket, Expires: cw.Expires},
+		}
+		var err error
+		if cw.Linter == "" && cw.Code == "" && cw.Finding == "" {
+			return nil, fmt.Errorf("waiver %d: at least one of linter, code, or finding must be specified", i)
+		} else if cw.Justification == "" {
+			return nil, fmt.Errorf("waiver %d: justification must be specified", i)
+		}
+		if cw.Finding != "" {
+			if w.finding, err = regexp.Compile(cw.Finding); err != nil {
+				return nil, fmt.Errorf("waiver %d: unrecognised finding regular expression: %w", i, err)
+			}
+		}
+		if cw.Profile != "" {
+			var ok bool
+			if w.profileId, ok = linter.GetProfileId(cw.Profile); !ok {
+				return nil, fmt.Errorf("waiver %d: unrecognised profile: %s", i, cw.Profile)
+			}
+		}
+		if cw.IssuerSKI != "" {
+			if w.issuerSKI, err = hex.DecodeString(strings.ReplaceAll(cw.IssuerSKI, ":", "")); err != nil {
+				return nil, fmt.Errorf("waiver %d: unrecognised issuer SKI: %w", i, err)
+			}
+		}
+		if cw.Expires != "" {
+			if w.expiresAt, err = time.Parse(time.DateOnly, cw.Expires); err != nil {
+				return nil, fmt.Errorf("waiver %d: unrecognised expiry date: %w", i, err)
+			}
+			w.expiresAt = w.expiresAt.AddDate(0, 0, 1)
+		}
+		if cw.Severity != "" {
+			var ok bool
+			if w.severity, ok = linter.Severity[cw.Severity]; !ok {
+				return nil, fmt.Errorf("waiver %d: unrecognised severity: %s", i, cw.Severity)
+			}
+			w.downgrade = true
+		}
+		compiled = append(compiled, w)
+	}
+	return compiled, nil
+}
+
+// matches determines whether or not the waiver applies to the specified result at the specified time.
+func (w *waiver) matches(ri *RequestInfo, lres *linter.LintingResult, now time.Time) bool {
+	switch {
+	case !w.expiresAt.IsZero() && !now.Before(w.expiresAt):
+		return false // An expired waiver no longer applies.
+	case w.linterName != "" && w.linterName != lres.LinterName:
+		return false
+	case w.code != "" && w.code != lres.Code:
+		return false
+	case w.finding != nil && !w.finding.MatchString(lres.Finding):
+		return false
+	case w.profileId != -1 && w.profileId != ri.profileId:
+		return false
+	}
+
+	// The issuer criteria only match certificate inputs.
+	if w.issuerSKI != nil || w.issuerSubject != "" {
+		if ri.cert == nil {
+			return false
+		} else if w.issuerSKI != nil && !bytes.Equal(w.issuerSKI, ri.cert.AuthorityKeyId) && (ri.issuer == nil || !bytes.Equal(w.issuerSKI, ri.issuer.SubjectKeyId)) {
+			return false
+		} else if w.issuerSubject != "" && !strings.EqualFold(w.issuerSubject, ri.cert.Issuer.String()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// waive annotates the result with the first unexpired waiver that matches it, downgrading its severity if the waiver
+// specifies a lower severity level.  Structured meta results are never waived.
+func (ri *RequestInfo) waive(lres *linter.LintingResult) {
+	if lres.Structured {
+		return
+	}
+	now := time.Now()
+	for i := range waivers {
+		if w := &waivers[i]; w.matches(ri, lres, now) {
+			waived := w.waived
+			if w.downgrade && w.severity < lres.Severity {
+				waived.OriginalSeverity = linter.SeverityString[lres.Severity]
+				lres.Severity = w.severity
+			}
+			lres.Waived = &waived
+			return
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package request
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkimetal/pkimetal/config"
+	"github.com/pkimetal/pkimetal/linter"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+// withWaivers replaces the loaded waivers for the duration of the test.
+func withWaivers(t *testing.T, cws ...config.Waiver) {
+	t.Helper()
+	saved := waivers
+	t.Cleanup(func() { waivers = saved })
+
+	var err error
+	if waivers, err = compileWaivers(cws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadWaivers(t *testing.T) {
+	file := writeTempFile(t, "waivers.yaml", `waivers:
+  - linter: zlint
+    code: w_ext_subject_key_identifier_missing_root
+    issuerSKI: "e3:66:74:bb"
+    justification: Legacy root
+    ticket: PKI-123
+    expires: 2030-01-31
+    severity: info
+`)
+
+	cws, err := config.LoadWaivers(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(cws) != 1 || cws[0].Code != "w_ext_subject_key_identifier_missing_root" || cws[0].IssuerSKI != "e3:66:74:bb" || cws[0].Ticket != "PKI-123" || cws[0].Expires != "2030-01-31" {
+		t.Fatalf("got %+v", cws)
+	}
+	ws, err := compileWaivers(cws)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(ws) != 1 || string(ws[0].issuerSKI) != "\xe3\x66\x74\xbb" || ws[0].severity != linter.SEVERITY_INFO || !ws[0].expiresAt.Equal(time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", ws)
+	}
+}
+
+func TestCompileWaivers(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		waiver  config.Waiver
+		wantErr bool
+	}{
+		{"valid", config.Waiver{Linter: "zlint", Code: "e_a", Justification: "j"}, false},
+		{"no criteria", config.Waiver{Justification: "j"}, true},
+		{"no justification", config.Waiver{Linter: "zlint"}, true},
+		{"bad regexp", config.Waiver{Finding: "(", Justification: "j"}, true},
+		{"bad profile", config.Waiver{Linter: "zlint", Profile: "no_such_profile", Justification: "j"}, true},
+		{"bad issuer SKI", config.Waiver{Linter: "zlint", IssuerSKI: "xyz", Justification: "j"}, true},
+		{"bad expiry", config.Waiver{Linter: "zlint", Expires: "31/01/2030", Justification: "j"}, true},
+		{"bad severity", config.Waiver{Linter: "zlint", Severity: "loud", Justification: "j"}, true},
+	} {
+		if _, err := compileWaivers([]config.Waiver{tc.waiver}); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v", tc.name, err)
+		}
+	}
+}
+
+func TestWaive(t *testing.T) {
+	cert, err := x509.ParseCertificate(testcaseDER(t, "tls_ov_certificate.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ri := RequestInfo{cert: cert, profileId: linter.TBR_LEAF_TLSSERVER_OV}
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(time.DateOnly)
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	for _, tc := range []struct {
+		name         string
+		waiver       config.Waiver
+		wantWaived   bool
+		wantSeverity linter.SeverityLevel
+	}{
+		{"code", config.Waiver{Code: "e_a", Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"finding regexp", config.Waiver{Linter: "zlint", Finding: "^Bad ", Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"other linter", config.Waiver{Linter: "certlint", Code: "e_a", Justification: "j"}, false, linter.SEVERITY_ERROR},
+		{"profile", config.Waiver{Code: "e_a", Profile: "tbr_leaf_tlsserver_ov", Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"other profile", config.Waiver{Code: "e_a", Profile: "tbr_leaf_tlsserver_dv", Justification: "j"}, false, linter.SEVERITY_ERROR},
+		{"issuer SKI", config.Waiver{Code: "e_a", IssuerSKI: "E36674BB70688D2C5D4E0EA64A8F9B37229C8292", Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"other issuer SKI", config.Waiver{Code: "e_a", IssuerSKI: "0102", Justification: "j"}, false, linter.SEVERITY_ERROR},
+		{"issuer subject", config.Waiver{Code: "e_a", IssuerSubject: "CN=Sectigo Public Server Authentication CA OV R36, O=Sectigo Limited, C=GB", Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"downgrade", config.Waiver{Code: "e_a", Severity: "notice", Justification: "j"}, true, linter.SEVERITY_NOTICE},
+		{"never upgrade", config.Waiver{Code: "e_a", Severity: "fatal", Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"unexpired", config.Waiver{Code: "e_a", Expires: tomorrow, Justification: "j"}, true, linter.SEVERITY_ERROR},
+		{"expired", config.Waiver{Code: "e_a", Expires: yesterday, Severity: "info", Justification: "j"}, false, linter.SEVERITY_ERROR},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			withWaivers(t, tc.waiver)
+			lres := linter.LintingResult{LinterName: "zlint", Code: "e_a", Finding: "Bad thing", Severity: linter.SEVERITY_ERROR}
+			ri.waive(&lres)
+			if (lres.Waived != nil) != tc.wantWaived || lres.Severity != tc.wantSeverity {
+				t.Errorf("got waived=%+v, severity=%s", lres.Waived, linter.SeverityString[lres.Severity])
+			} else if tc.wantWaived && tc.wantSeverity != linter.SEVERITY_ERROR && lres.Waived.OriginalSeverity != "error" {
+				t.Errorf("got original severity %q, want error", lres.Waived.OriginalSeverity)
+			}
+		})
+	}
+}
+
+func TestWaive_Lint(t *testing.T) {
+	withWaivers(t, config.Waiver{Linter: "fake", Code: "e_a", Justification: "Approved exception", Ticket: "PKI-1", Severity: "info"})
+	withDecisionPolicy(t, "error", nil)
+	withFakeLinters(t, linter.LintingResult{LinterName: "fake", Severity: linter.SEVERITY_ERROR, Finding: "e_a", Code: "e_a"})
+	ri := RequestInfo{endpoint: ENDPOINT_LINTOCSP, minimumSeverity: linter.SEVERITY_INFO, decisionMode: true}
+	ri.GetProfile("")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lrespFiltered := ri.lint(ctx)
+	if len(lrespFiltered) != 1 || lrespFiltered[0].Severity != "info" || lrespFiltered[0].Waived == nil || lrespFiltered[0].Waived.Ticket != "PKI-1" || lrespFiltered[0].Waived.OriginalSeverity != "error" {
+		t.Fatalf("got %+v, want the finding to be waived and downgraded", lrespFiltered)
+	} else if ri.decision.verdict != VERDICT_PASS {
+		t.Errorf("got %+v, want a waived finding not to cause rejection", ri.decision)
+	} else if suffix := annotationSuffix(lrespFiltered[0]); suffix != " (waived: PKI-1)" {
+		t.Errorf("got suffix %q", suffix)
+	}
+
+	sarif := makeSARIFLog(lrespFiltered)
+	if s := sarif.Runs[0].Results[0].Suppressions; len(s) != 1 || s[0].Justification != "Approved exception" {
+		t.Errorf("got SARIF suppressions %+v", s)
+	}
+}
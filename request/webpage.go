@@ -130,6 +130,8 @@ func FrontPage(fhctx *fasthttp.RequestCtx) {
           <LI><A href="/` + ENDPOINTSTRING_LINTTBSCRL + `">` + ENDPOINTSTRING_LINTTBSCRL + `</A> - Lint a to-be-signed CRL</LI>
           <LI><A href="/` + ENDPOINTSTRING_LINTOCSP + `">` + ENDPOINTSTRING_LINTOCSP + `</A> - Lint an OCSP Response</LI>
           <LI><A href="/` + ENDPOINTSTRING_LINTTBSOCSP + `">` + ENDPOINTSTRING_LINTTBSOCSP + `</A> - Lint a to-be-signed OCSP Response</LI>
+          <LI><A href="/` + ENDPOINTSTRING_LINTCSR + `">` + ENDPOINTSTRING_LINTCSR + `</A> - Lint a Certificate Signing Request</LI>
+          <LI><A href="/` + ENDPOINTSTRING_LINTKEY + `">` + ENDPOINTSTRING_LINTKEY + `</A> - Lint a Public Key</LI>
         </UL>
         <BR><B>Other APIs:</B>
         <UL>
@@ -212,6 +214,10 @@ func APIWebpage(fhctx *fasthttp.RequestCtx, endpoint string) {
 		inputType = `OCSP Response`
 	case ENDPOINTSTRING_LINTTBSOCSP:
 		inputType = `To-be-signed OCSP Response`
+	case ENDPOINTSTRING_LINTCSR:
+		inputType = `Certificate Signing Request`
+	case ENDPOINTSTRING_LINTKEY:
+		inputType = `Public Key`
 	}
 	response.WriteString(inputType + ` (PEM/Base64):</B>
           <BR><TEXTAREA name="b64input" cols="74" rows="18" autofocus autoCorrect="off" autoCapitalize="off" spellCheck="false" ondragover="handleDragOver(event)" ondrop="handleDrop(event)"></TEXTAREA>
@@ -244,26 +250,35 @@ func APIWebpage(fhctx *fasthttp.RequestCtx, endpoint string) {
       <TR>
         <TD><B>Profile:</B>
           <BR><SELECT name="profile" size="17">`)
-	for id, profile := range linter.AllProfilesOrdered {
+	for i, profile := range linter.AllProfilesOrdered {
+		id := linter.BaseProfileId(linter.ProfileId(i))
 		isShown := false
 		switch endpoint {
 		case ENDPOINTSTRING_LINTCERT, ENDPOINTSTRING_LINTTBSCERT:
-			if !slices.Contains(linter.NonCertificateProfileIDs, linter.ProfileId(id)) {
+			if !slices.Contains(linter.NonCertificateProfileIDs, id) {
 				isShown = true
 			}
 		case ENDPOINTSTRING_LINTCRL, ENDPOINTSTRING_LINTTBSCRL:
-			if linter.ProfileId(id) == linter.AUTODETECT || slices.Contains(linter.CrlProfileIDs, linter.ProfileId(id)) {
+			if id == linter.AUTODETECT || slices.Contains(linter.CrlProfileIDs, id) {
 				isShown = true
 			}
 		case ENDPOINTSTRING_LINTOCSP, ENDPOINTSTRING_LINTTBSOCSP:
-			if linter.ProfileId(id) == linter.AUTODETECT || slices.Contains(linter.OcspProfileIDs, linter.ProfileId(id)) {
+			if id == linter.AUTODETECT || slices.Contains(linter.OcspProfileIDs, id) {
+				isShown = true
+			}
+		case ENDPOINTSTRING_LINTCSR:
+			if id == linter.AUTODETECT || slices.Contains(linter.CsrProfileIDs, id) {
+				isShown = true
+			}
+		case ENDPOINTSTRING_LINTKEY:
+			if id == linter.AUTODETECT || slices.Contains(linter.PublicKeyProfileIDs, id) {
 				isShown = true
 			}
 		}
 		if isShown {
 			response.WriteString(`
             <OPTION value="` + profile.Name + `"`)
-			if linter.ProfileId(id) == linter.AUTODETECT {
+			if id == linter.AUTODETECT {
 				response.WriteString(` selected`)
 			}
 			response.WriteString(`>`)
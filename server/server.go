@@ -28,7 +28,7 @@ func webHandler(fhctx *fasthttp.RequestCtx) {
 			request.FrontPage(fhctx)
 		case request.ENDPOINTSTRING_CSS:
 			request.CSS(fhctx)
-		case request.ENDPOINTSTRING_LINTCERT, request.ENDPOINTSTRING_LINTTBSCERT, request.ENDPOINTSTRING_LINTCRL, request.ENDPOINTSTRING_LINTTBSCRL, request.ENDPOINTSTRING_LINTOCSP, request.ENDPOINTSTRING_LINTTBSOCSP:
+		case request.ENDPOINTSTRING_LINTCERT, request.ENDPOINTSTRING_LINTTBSCERT, request.ENDPOINTSTRING_LINTCRL, request.ENDPOINTSTRING_LINTTBSCRL, request.ENDPOINTSTRING_LINTOCSP, request.ENDPOINTSTRING_LINTTBSOCSP, request.ENDPOINTSTRING_LINTCSR, request.ENDPOINTSTRING_LINTKEY:
 			request.APIWebpage(fhctx, endpoint)
 		case request.ENDPOINTSTRING_LINTERS:
 			request.Linters(fhctx)
@@ -44,7 +44,15 @@ func webHandler(fhctx *fasthttp.RequestCtx) {
 		}
 
 	} else if fhctx.IsPost() {
-		if request.POST(fhctx, endpoint) == -1 {
+		var result int
+		if endpoint == request.ENDPOINTSTRING_LINTBATCH {
+			result = request.BatchPOST(fhctx)
+		} else if endpoint == request.ENDPOINTSTRING_DETECTPROFILE {
+			request.DetectProfilePOST(fhctx)
+		} else {
+			result = request.POST(fhctx, endpoint)
+		}
+		if result == -1 {
 			// Request timed out.
 			fhctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
 			fhctx.SetContentType("text/plain")